@@ -10,6 +10,6 @@ var version = "dev"
 
 func main() {
 	if err := cmd.Execute(version); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -2,6 +2,8 @@ package bundle
 
 import (
 	"archive/zip"
+	"crypto"
+	"crypto/ed25519"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -25,19 +27,32 @@ type Config struct {
 	WASMBytes        []byte // Compiled recover.wasm binary
 	RecoveryURL      string // Optional: base URL for QR code (e.g. "https://example.com/recover.html")
 	NoEmbedManifest  bool   // If true, do not embed MANIFEST.age in recover.html even when small enough
+	Practice         bool   // If true, generate watermarked practice bundles for a recovery drill
+	// PaperManifest prints MANIFEST.age in README.pdf, as QR codes and typed
+	// lines, so the paper alone is enough to recover. The manifest must be
+	// at most core.MaxPaperManifestSize bytes.
+	PaperManifest bool
+	// Destinations maps friend names to the directory their bundle is written
+	// to, e.g. a USB stick. Friends not listed use the default directory.
+	Destinations map[string]string
+	Progress     Progress // Optional: called as each bundle is finished
+	Workers      int      // Bundles built at once (default: one per CPU)
+	// Reproducible derives the CSP nonce of recover.html from the seal
+	// instead of drawing a random one, so rebuilding a bundle from the same
+	// share and MANIFEST.age gives the same bytes.
+	Reproducible bool
+	// Signer, when set, signs every bundle with the owner's Ed25519 key.
+	// Its fingerprint is printed in README.txt and README.pdf.
+	Signer crypto.Signer
 }
 
 // GenerateAll creates bundles for all friends in the project.
 func GenerateAll(p *project.Project, cfg Config) error {
-	if p.Sealed == nil {
+	sealed := p.CurrentSeal()
+	if sealed == nil {
 		return fmt.Errorf("project must be sealed before generating bundles")
 	}
 
-	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
-	if err := os.MkdirAll(bundlesDir, 0755); err != nil {
-		return fmt.Errorf("creating bundles directory: %w", err)
-	}
-
 	// Load all shares
 	shares, err := loadShares(p)
 	if err != nil {
@@ -45,96 +60,193 @@ func GenerateAll(p *project.Project, cfg Config) error {
 	}
 
 	// Read MANIFEST.age
-	manifestPath := p.ManifestAgePath()
-	manifestData, err := os.ReadFile(manifestPath)
+	manifestData, _, err := ReadManifestFile(p.ManifestAgePath())
 	if err != nil {
 		return fmt.Errorf("reading manifest: %w", err)
 	}
-	manifestChecksum := core.HashBytes(manifestData)
 
-	// Generate bundle for each friend
-	for i, friend := range p.Friends {
-		share := shares[i]
+	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
+	_, err = GenerateBundles(p, shares, manifestData, bundlesDir, sealed.At, cfg)
+	return err
+}
 
-		// Resolve language: friend override > project default > "en"
-		lang := friend.Language
-		if lang == "" {
-			lang = p.Language
+// WriteManifestFile writes MANIFEST.age to path, and its parity data next
+// to it, at path + ".par".
+func WriteManifestFile(path string, manifestData []byte) error {
+	if err := os.WriteFile(path, manifestData, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(path+".par", core.NewParity(manifestData), 0644)
+}
+
+// ReadManifestFile reads MANIFEST.age from path. When it's damaged, it's
+// repaired with the parity data next to it, if there is any; the number of
+// blocks rebuilt is returned.
+func ReadManifestFile(path string) ([]byte, int, error) {
+	manifestData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	parityData, err := os.ReadFile(path + ".par")
+	if err != nil {
+		return manifestData, 0, nil
+	}
+	return repair(manifestData, parityData)
+}
+
+// GenerateBundles creates one bundle per friend in dir, from shares given in
+// the same order as p.Friends and an encrypted manifest. Friends whose share
+// is nil are skipped. created is the date shown in the bundles. GenerateAll
+// uses it with the project's sealed files; drills and ephemeral seals use it
+// with shares that never touch the shares directory. Each bundle is verified
+// before it's written. It returns the paths of the bundles written.
+//
+// Bundles are written all or nothing: each one goes to a temporary file
+// next to its destination, and they're only renamed into place once every
+// bundle has been written. If writing any of them fails, no bundle is
+// replaced and the temporary files are removed.
+func GenerateBundles(p *project.Project, shares []*core.Share, manifestData []byte, dir string, created time.Time, cfg Config) ([]string, error) {
+	var paths, temps []string
+	err := Build(p, shares, manifestData, created, cfg, func(b Bundle) error {
+		friendDir := dir
+		if d, ok := cfg.Destinations[b.Friend.Name]; ok {
+			friendDir = d
 		}
-		if lang == "" {
-			lang = "en"
+		if err := os.MkdirAll(friendDir, 0755); err != nil {
+			return fmt.Errorf("creating bundles directory: %w", err)
 		}
-
-		// Get other friends (excluding this one) - empty for anonymous mode
-		var otherFriends []project.Friend
-		var otherFriendsInfo []html.FriendInfo
-		if !p.Anonymous {
-			otherFriends = make([]project.Friend, 0, len(p.Friends)-1)
-			otherFriendsInfo = make([]html.FriendInfo, 0, len(p.Friends)-1)
-			for j, f := range p.Friends {
-				if j != i {
-					otherFriends = append(otherFriends, f)
-					otherFriendsInfo = append(otherFriendsInfo, html.FriendInfo{
-						Name:       f.Name,
-						Contact:    f.Contact,
-						ShareIndex: j + 1, // 1-based share index
-					})
-				}
+		path := filepath.Join(friendDir, b.FileName)
+		if err := os.WriteFile(path+".tmp", b.Data, 0644); err != nil {
+			os.Remove(path + ".tmp")
+			return fmt.Errorf("writing bundle for %s: %w", b.Friend.Name, err)
+		}
+		paths = append(paths, path)
+		temps = append(temps, path+".tmp")
+		return nil
+	})
+	if err == nil {
+		for i, path := range paths {
+			if err = os.Rename(temps[i], path); err != nil {
+				err = fmt.Errorf("writing bundle: %w", err)
+				break
 			}
 		}
-
-		// Generate personalized recover.html for this friend
-		personalization := &html.PersonalizationData{
-			Holder:       friend.Name,
-			HolderShare:  share.Encode(),
-			OtherFriends: otherFriendsInfo,
-			Threshold:    p.Threshold,
-			Total:        len(p.Friends),
-			Language:     lang,
+	}
+	if err != nil {
+		for _, tmp := range temps {
+			os.Remove(tmp)
 		}
+		return nil, err
+	}
+	return paths, nil
+}
 
+// NewBundleParams prepares the bundle of the i-th friend of p, including
+// their personalized recover.html. OutputPath is left empty.
+func NewBundleParams(p *project.Project, i int, share *core.Share, manifestData []byte, created time.Time, cfg Config) BundleParams {
+	return newAssets(manifestData, cfg).params(p, i, share, created, cfg)
+}
+
+// assets holds what every bundle of a seal embeds, prepared once.
+type assets struct {
+	page             *html.RecoverPage
+	manifestData     []byte
+	manifestChecksum string
+	manifestEmbedded bool
+	manifestB64      string
+	parity           []byte
+	parityB64        string
+}
+
+func newAssets(manifestData []byte, cfg Config) *assets {
+	a := &assets{
+		page:             html.NewRecoverPage(cfg.WASMBytes, cfg.Version, cfg.GitHubReleaseURL),
+		manifestData:     manifestData,
+		manifestChecksum: core.HashBytes(manifestData),
 		// Embed manifest in recover.html when small enough and not disabled
-		manifestEmbedded := !cfg.NoEmbedManifest && len(manifestData) <= html.MaxEmbeddedManifestSize
-		if manifestEmbedded {
-			personalization.ManifestB64 = base64.StdEncoding.EncodeToString(manifestData)
-		}
+		manifestEmbedded: !cfg.NoEmbedManifest && len(manifestData) <= html.MaxEmbeddedManifestSize,
+		parity:           core.NewParity(manifestData),
+	}
+	if a.manifestEmbedded {
+		a.manifestB64 = base64.StdEncoding.EncodeToString(manifestData)
+		a.parityB64 = base64.StdEncoding.EncodeToString(a.parity)
+	}
+	return a
+}
 
-		recoverHTML := html.GenerateRecoverHTML(cfg.WASMBytes, cfg.Version, cfg.GitHubReleaseURL, personalization)
-		recoverChecksum := core.HashString(recoverHTML)
-
-		bundlePath := filepath.Join(bundlesDir, fmt.Sprintf("bundle-%s.zip", core.SanitizeFilename(friend.Name)))
-
-		err := GenerateBundle(BundleParams{
-			OutputPath:       bundlePath,
-			ProjectName:      p.Name,
-			Friend:           friend,
-			Share:            share,
-			OtherFriends:     otherFriends,
-			Threshold:        p.Threshold,
-			Total:            len(p.Friends),
-			ManifestData:     manifestData,
-			ManifestChecksum: manifestChecksum,
-			ManifestEmbedded: manifestEmbedded,
-			RecoverHTML:      recoverHTML,
-			RecoverChecksum:  recoverChecksum,
-			Version:          cfg.Version,
-			GitHubReleaseURL: cfg.GitHubReleaseURL,
-			SealedAt:         p.Sealed.At,
-			Anonymous:        p.Anonymous,
-			RecoveryURL:      cfg.RecoveryURL,
-			Language:         lang,
-		})
-		if err != nil {
-			return fmt.Errorf("generating bundle for %s: %w", friend.Name, err)
-		}
+// params prepares the bundle of the i-th friend of p.
+func (a *assets) params(p *project.Project, i int, share *core.Share, created time.Time, cfg Config) BundleParams {
+	friend := p.Friends[i]
 
-		// Verify the bundle we just created
-		if err := VerifyBundle(bundlePath); err != nil {
-			return fmt.Errorf("verifying bundle for %s: %w", friend.Name, err)
+	// Resolve language: friend override > project default > "en"
+	lang := friend.Language
+	if lang == "" {
+		lang = p.Language
+	}
+	if lang == "" {
+		lang = "en"
+	}
+
+	// Get other friends (excluding this one) - empty for anonymous mode
+	var otherFriends []project.Friend
+	var otherFriendsInfo []html.FriendInfo
+	if !p.Anonymous {
+		otherFriends = make([]project.Friend, 0, len(p.Friends)-1)
+		otherFriendsInfo = make([]html.FriendInfo, 0, len(p.Friends)-1)
+		for j, f := range p.Friends {
+			if j != i {
+				otherFriends = append(otherFriends, f)
+				otherFriendsInfo = append(otherFriendsInfo, html.FriendInfo{
+					Name:       f.Name,
+					Contact:    f.Contact,
+					ShareIndex: j + 1, // 1-based share index
+				})
+			}
 		}
 	}
 
-	return nil
+	// Generate personalized recover.html for this friend
+	personalization := &html.PersonalizationData{
+		Holder:       friend.Name,
+		HolderShare:  share.Encode(),
+		OtherFriends: otherFriendsInfo,
+		Threshold:    p.Threshold,
+		Total:        len(p.Friends),
+		Language:     lang,
+		Practice:     cfg.Practice,
+		ManifestB64:  a.manifestB64,
+		ParityB64:    a.parityB64,
+	}
+	var recoverHTML string
+	if cfg.Reproducible {
+		recoverHTML = a.page.RenderNonce(personalization, html.DeriveCSPNonce(a.manifestChecksum+"\n"+share.Checksum))
+	} else {
+		recoverHTML = a.page.Render(personalization)
+	}
+
+	return BundleParams{
+		ProjectName:      p.Name,
+		Friend:           friend,
+		Share:            share,
+		OtherFriends:     otherFriends,
+		Threshold:        p.Threshold,
+		Total:            len(p.Friends),
+		ManifestData:     a.manifestData,
+		ManifestChecksum: a.manifestChecksum,
+		ManifestEmbedded: a.manifestEmbedded,
+		ManifestParity:   a.parity,
+		RecoverHTML:      recoverHTML,
+		RecoverChecksum:  core.HashString(recoverHTML),
+		Version:          cfg.Version,
+		GitHubReleaseURL: cfg.GitHubReleaseURL,
+		SealedAt:         created,
+		Anonymous:        p.Anonymous,
+		RecoveryURL:      cfg.RecoveryURL,
+		Language:         lang,
+		Practice:         cfg.Practice,
+		PaperManifest:    cfg.PaperManifest,
+		Signer:           cfg.Signer,
+	}
 }
 
 // BundleParams contains all parameters for generating a single bundle.
@@ -148,7 +260,8 @@ type BundleParams struct {
 	Total            int
 	ManifestData     []byte
 	ManifestChecksum string
-	ManifestEmbedded bool // true when manifest is base64-embedded in recover.html
+	ManifestEmbedded bool   // true when manifest is base64-embedded in recover.html
+	ManifestParity   []byte // Optional: parity data that repairs a damaged MANIFEST.age
 	RecoverHTML      string
 	RecoverChecksum  string
 	Version          string
@@ -156,11 +269,36 @@ type BundleParams struct {
 	SealedAt         time.Time
 	Anonymous        bool
 	RecoveryURL      string
-	Language         string // Bundle language for this friend
+	Language         string        // Bundle language for this friend
+	Practice         bool          // Watermark README.txt, README.pdf and recover.html as practice
+	PaperManifest    bool          // Print MANIFEST.age in README.pdf
+	Signer           crypto.Signer // Optional: Ed25519 key that signs the bundle
 }
 
-// GenerateBundle creates a single bundle ZIP file for one friend.
+// GenerateBundle creates a single bundle ZIP file for one friend at params.OutputPath.
 func GenerateBundle(params BundleParams) error {
+	f, err := os.Create(params.OutputPath)
+	if err != nil {
+		return fmt.Errorf("creating zip file: %w", err)
+	}
+	if err := WriteBundle(f, params); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteBundle writes one friend's bundle ZIP to w. OutputPath is ignored.
+func WriteBundle(w io.Writer, params BundleParams) error {
+	var signedBy string
+	if params.Signer != nil {
+		pub, ok := params.Signer.Public().(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key must be Ed25519, got %T", params.Signer.Public())
+		}
+		signedBy = core.KeyFingerprint(pub)
+	}
+
 	// Common data for both README formats
 	readmeData := ReadmeData{
 		ProjectName:      params.ProjectName,
@@ -177,12 +315,18 @@ func GenerateBundle(params BundleParams) error {
 		Anonymous:        params.Anonymous,
 		Language:         params.Language,
 		ManifestEmbedded: params.ManifestEmbedded,
+		Practice:         params.Practice,
+		SignedBy:         signedBy,
 	}
 
 	// Generate README.txt
 	readmeContent := GenerateReadme(readmeData)
 
 	// Generate README.pdf
+	var paperManifest []byte
+	if params.PaperManifest {
+		paperManifest = params.ManifestData
+	}
 	pdfContent, err := pdf.GenerateReadme(pdf.ReadmeData{
 		ProjectName:      readmeData.ProjectName,
 		Holder:           readmeData.Holder,
@@ -199,6 +343,9 @@ func GenerateBundle(params BundleParams) error {
 		RecoveryURL:      params.RecoveryURL,
 		Language:         params.Language,
 		ManifestEmbedded: params.ManifestEmbedded,
+		Practice:         params.Practice,
+		SignedBy:         signedBy,
+		PaperManifest:    paperManifest,
 	})
 	if err != nil {
 		return fmt.Errorf("generating PDF: %w", err)
@@ -218,7 +365,26 @@ func GenerateBundle(params BundleParams) error {
 		files = append(files, ZipFile{Name: "MANIFEST.age", Content: params.ManifestData, ModTime: params.SealedAt})
 	}
 
-	return CreateZip(params.OutputPath, files)
+	if params.Signer != nil {
+		signed := make([]core.SignedFile, len(files))
+		for i, f := range files {
+			signed[i] = core.SignedFile{Name: f.Name, Checksum: core.HashBytes(f.Content)}
+		}
+		sig, err := core.SignBundle(params.Signer, params.ManifestChecksum, signed)
+		if err != nil {
+			return err
+		}
+		files = append(files, ZipFile{Name: core.SignatureFileName, Content: []byte(sig.Encode()), ModTime: params.SealedAt})
+	}
+
+	// Parity data checks itself, so it's left out of the checksums and the
+	// signature: a repair only counts if it gives back the sealed MANIFEST.age.
+	// An embedded manifest has its parity data in recover.html.
+	if !params.ManifestEmbedded && len(params.ManifestParity) > 0 {
+		files = append(files, ZipFile{Name: core.ParityFileName, Content: params.ManifestParity, ModTime: params.SealedAt})
+	}
+
+	return WriteZip(w, files)
 }
 
 // loadShares reads all share files from the project's shares directory.
@@ -232,6 +398,11 @@ func loadShares(p *project.Project) ([]*core.Share, error) {
 		sharePath := filepath.Join(sharesDir, filename)
 
 		data, err := os.ReadFile(sharePath)
+		if os.IsNotExist(err) {
+			if d := p.CurrentSeal().Delivery(friend.Name); d != nil && d.ShareWipedAt != nil {
+				return nil, fmt.Errorf("share for %s was wiped after delivery; reseal to create new bundles", friend.Name)
+			}
+		}
 		if err != nil {
 			return nil, fmt.Errorf("reading share for %s: %w", friend.Name, err)
 		}
@@ -256,16 +427,76 @@ func VerifyBundle(bundlePath string) error {
 	}
 	defer r.Close()
 
+	_, err = verifyZip(&r.Reader)
+	return err
+}
+
+// VerifyBundleReader verifies a bundle ZIP read from r, which holds size bytes.
+func VerifyBundleReader(r io.ReaderAt, size int64) error {
+	_, err := VerifySigned(r, size)
+	return err
+}
+
+// VerifySigned is VerifyBundleReader for bundles that may be signed. It
+// returns the bundle's signature, already checked against its files, or
+// nil when the bundle isn't signed. Whose key signed it is up to the caller
+// to check.
+func VerifySigned(r io.ReaderAt, size int64) (*core.BundleSignature, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening bundle: %w", err)
+	}
+	v, err := verifyZip(zr)
+	if err != nil {
+		return nil, err
+	}
+	return v.sig, nil
+}
+
+// RecoverProvenance checks the recover.html in a bundle ZIP of size bytes
+// read from r against the official releases.
+func RecoverProvenance(r io.ReaderAt, size int64) (*html.Provenance, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("opening bundle: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "recover.html" {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		return html.CheckRecoverHTML(data)
+	}
+	return nil, fmt.Errorf("recover.html not found in bundle")
+}
+
+// verification is what verifyZip found in a bundle.
+type verification struct {
+	sig      *core.BundleSignature // nil when the bundle isn't signed
+	manifest []byte                // MANIFEST.age, repaired if it was damaged
+	repaired int                   // blocks of MANIFEST.age rebuilt from its parity data
+}
+
+// verifyZip verifies the contents of an opened bundle ZIP, and its
+// signature if it has one. A damaged MANIFEST.age is repaired with its
+// parity data first, if the bundle has it.
+func verifyZip(r *zip.Reader) (*verification, error) {
 	// Read files from ZIP
 	var readmeContent string
 	var manifestData []byte
 	var recoverData []byte
 	var pdfData []byte
+	var signatureData []byte
+	var parityData []byte
+	contents := make(map[string][]byte)
 
 	for _, f := range r.File {
 		rc, err := f.Open()
 		if err != nil {
-			return fmt.Errorf("opening %s: %w", f.Name, err)
+			return nil, fmt.Errorf("opening %s: %w", f.Name, err)
 		}
 
 		data, err := io.ReadAll(rc)
@@ -273,8 +504,18 @@ func VerifyBundle(bundlePath string) error {
 			err = closeErr
 		}
 		if err != nil {
-			return fmt.Errorf("reading %s: %w", f.Name, err)
+			return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+
+		switch f.Name {
+		case core.SignatureFileName:
+			signatureData = data
+			continue
+		case core.ParityFileName:
+			parityData = data
+			continue
 		}
+		contents[f.Name] = data
 
 		switch {
 		case translations.IsReadmeFile(f.Name, ".txt"):
@@ -289,59 +530,89 @@ func VerifyBundle(bundlePath string) error {
 	}
 
 	if readmeContent == "" {
-		return fmt.Errorf("README file (.txt) not found in bundle")
+		return nil, fmt.Errorf("README file (.txt) not found in bundle")
 	}
 	if len(pdfData) == 0 {
-		return fmt.Errorf("README file (.pdf) not found in bundle")
+		return nil, fmt.Errorf("README file (.pdf) not found in bundle")
 	}
 	if len(recoverData) == 0 {
-		return fmt.Errorf("recover.html not found in bundle")
+		return nil, fmt.Errorf("recover.html not found in bundle")
 	}
 
 	// When MANIFEST.age is not in the ZIP, the manifest is embedded in recover.html.
 	// Extract it from there for checksum verification.
-	if len(manifestData) == 0 {
+	_, inZip := contents["MANIFEST.age"]
+	if !inZip {
 		extracted, err := html.ExtractManifestFromHTML(recoverData)
 		if err != nil {
-			return fmt.Errorf("MANIFEST.age not in bundle and could not extract from recover.html: %w", err)
+			return nil, fmt.Errorf("MANIFEST.age not in bundle and could not extract from recover.html: %w", err)
 		}
 		manifestData = extracted
+		if parityData, err = html.ExtractParityFromHTML(recoverData); err != nil {
+			return nil, err
+		}
 	}
 
 	// Parse metadata from footer
 	metadata := parseMetadataFooter(readmeContent)
 
 	// Verify manifest checksum
+	v := &verification{manifest: manifestData}
 	actualManifestChecksum := core.HashBytes(manifestData)
 	expectedManifestChecksum := metadata["checksum-manifest"]
 	if expectedManifestChecksum == "" {
-		return fmt.Errorf("manifest checksum not found in README metadata")
+		return nil, fmt.Errorf("manifest checksum not found in README metadata")
+	}
+	if actualManifestChecksum != expectedManifestChecksum && parityData != nil {
+		repaired, n, err := core.Repair(manifestData, parityData)
+		if err != nil {
+			return nil, fmt.Errorf("MANIFEST.age %w, and its parity data can't repair it: %v", core.ErrChecksumMismatch, err)
+		}
+		v.manifest, v.repaired = repaired, n
+		actualManifestChecksum = core.HashBytes(repaired)
+		if inZip {
+			contents["MANIFEST.age"] = repaired
+		}
 	}
 	if actualManifestChecksum != expectedManifestChecksum {
-		return fmt.Errorf("MANIFEST.age checksum mismatch")
+		return nil, fmt.Errorf("MANIFEST.age %w", core.ErrChecksumMismatch)
 	}
 
 	// Verify recover.html checksum
 	actualRecoverChecksum := core.HashString(string(recoverData))
 	expectedRecoverChecksum := metadata["checksum-recover-html"]
 	if expectedRecoverChecksum == "" {
-		return fmt.Errorf("recover.html checksum not found in README metadata")
+		return nil, fmt.Errorf("recover.html checksum not found in README metadata")
 	}
 	if actualRecoverChecksum != expectedRecoverChecksum {
-		return fmt.Errorf("recover.html checksum mismatch")
+		return nil, fmt.Errorf("recover.html %w", core.ErrChecksumMismatch)
 	}
 
 	// Verify embedded share
 	share, err := core.ParseShare([]byte(readmeContent))
 	if err != nil {
-		return fmt.Errorf("parsing share: %w", err)
+		return nil, fmt.Errorf("parsing share: %w", err)
 	}
 
 	if err := share.Verify(); err != nil {
-		return fmt.Errorf("share verification failed: %w", err)
+		return nil, fmt.Errorf("share verification failed: %w", err)
 	}
 
-	return nil
+	if signatureData == nil {
+		return v, nil
+	}
+	sig, err := core.ParseBundleSignature(signatureData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v: %w", core.SignatureFileName, err, core.ErrBadSignature)
+	}
+	if err := sig.VerifyFiles(contents); err != nil {
+		return nil, err
+	}
+	if sig.ManifestChecksum != actualManifestChecksum {
+		return nil, fmt.Errorf("MANIFEST.age wasn't the signed manifest: %w", core.ErrBadSignature)
+	}
+	v.sig = sig
+	return v, nil
 }
 
 // parseMetadataFooter extracts key-value pairs from the README.txt footer section.
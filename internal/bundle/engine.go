@@ -0,0 +1,263 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/crypto"
+	"github.com/eljojo/rememory/internal/manifest"
+	"github.com/eljojo/rememory/internal/project"
+)
+
+// The seal engine below is shared by the CLI and maker.html, so every
+// bundle goes through the same steps and the same verification, wherever
+// it was made.
+
+// Stage is a step of sealing or bundling, reported through Progress.
+type Stage string
+
+const (
+	StageArchive Stage = "archive"
+	StageEncrypt Stage = "encrypt"
+	StageSplit   Stage = "split"
+	StageVerify  Stage = "verify"
+	StageBundle  Stage = "bundle"
+)
+
+// Event reports that a stage has started. StageBundle is reported after
+// each bundle is written and verified: Friend is whose bundle it was, and
+// Done counts the bundles finished out of Total.
+type Event struct {
+	Stage  Stage  `json:"stage"`
+	Friend string `json:"friend,omitempty"`
+	Done   int    `json:"done,omitempty"`
+	Total  int    `json:"total,omitempty"`
+}
+
+// Progress receives the engine's events. A nil Progress ignores them.
+type Progress func(Event)
+
+func (p Progress) emit(e Event) {
+	if p != nil {
+		p(e)
+	}
+}
+
+// Sealed is an encrypted manifest and the shares of its passphrase.
+// The passphrase itself is not kept.
+type Sealed struct {
+	Manifest         []byte // MANIFEST.age
+	ManifestChecksum string
+	VerificationHash string
+	Shares           []*core.Share // in the order of the project's friends
+	Files            []manifest.FileHash
+	Warnings         []string // files skipped when archiving
+}
+
+// SealDir archives dir and seals it for p's friends and threshold.
+func SealDir(p *project.Project, dir string, progress Progress) (*Sealed, error) {
+	progress.emit(Event{Stage: StageArchive})
+	count, err := manifest.CountFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("checking manifest directory: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("manifest directory is empty: %s", dir)
+	}
+
+	var archive bytes.Buffer
+	result, err := manifest.Archive(&archive, dir)
+	if err != nil {
+		return nil, fmt.Errorf("archiving manifest: %w", err)
+	}
+	return sealIndexed(p, archive.Bytes(), result.Warnings, progress)
+}
+
+// SealFiles archives in-memory files under a manifest/ folder and seals
+// them for p's friends and threshold.
+func SealFiles(p *project.Project, files []manifest.File, progress Progress) (*Sealed, error) {
+	progress.emit(Event{Stage: StageArchive})
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files provided")
+	}
+
+	var archive bytes.Buffer
+	if err := manifest.ArchiveFiles(&archive, files); err != nil {
+		return nil, fmt.Errorf("creating archive: %w", err)
+	}
+	return sealIndexed(p, archive.Bytes(), nil, progress)
+}
+
+// sealIndexed seals an archive and records the files in it.
+func sealIndexed(p *project.Project, archive []byte, warnings []string, progress Progress) (*Sealed, error) {
+	// Index exactly what was archived, so later edits can be detected
+	files, err := manifest.HashArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("indexing manifest: %w", err)
+	}
+	sealed, err := SealArchive(p, archive, progress)
+	if err != nil {
+		return nil, err
+	}
+	sealed.Files = files
+	sealed.Warnings = warnings
+	return sealed, nil
+}
+
+// SealArchive encrypts an archive with a new passphrase, splits the
+// passphrase into one share per friend of p, and checks that the first
+// threshold shares give it back.
+func SealArchive(p *project.Project, archive []byte, progress Progress) (*Sealed, error) {
+	n, k := len(p.Friends), p.Threshold
+	if err := core.ValidateShamirParams(n, k); err != nil {
+		return nil, err
+	}
+
+	progress.emit(Event{Stage: StageEncrypt})
+	// v2: split the raw bytes, not the base64 string
+	raw, passphrase, err := crypto.GenerateRawPassphrase(crypto.DefaultPassphraseBytes)
+	if err != nil {
+		return nil, fmt.Errorf("generating passphrase: %w", err)
+	}
+	defer raw.Wipe()
+	defer passphrase.Wipe()
+	var encrypted bytes.Buffer
+	if err := core.EncryptSecret(&encrypted, bytes.NewReader(archive), passphrase); err != nil {
+		return nil, fmt.Errorf("encrypting: %w", err)
+	}
+
+	progress.emit(Event{Stage: StageSplit})
+	parts, err := core.Split(raw.Bytes(), n, k)
+	if err != nil {
+		return nil, fmt.Errorf("splitting passphrase: %w", err)
+	}
+	shares := make([]*core.Share, n)
+	for i, data := range parts {
+		shares[i] = core.NewShare(2, i+1, n, k, p.Friends[i].Name, data)
+	}
+
+	progress.emit(Event{Stage: StageVerify})
+	recovered, err := core.Combine(parts[:k])
+	if err != nil {
+		return nil, fmt.Errorf("verification failed: %w", err)
+	}
+	candidate := core.RecoverSecret(recovered, 2)
+	core.Wipe(recovered)
+	defer candidate.Wipe()
+	if !candidate.Equal(passphrase) {
+		return nil, fmt.Errorf("verification failed: reconstructed passphrase doesn't match")
+	}
+
+	return &Sealed{
+		Manifest:         encrypted.Bytes(),
+		ManifestChecksum: core.HashBytes(encrypted.Bytes()),
+		VerificationHash: core.HashBytes(passphrase.Bytes()),
+		Shares:           shares,
+	}, nil
+}
+
+// Bundle is a generated and verified bundle ZIP.
+type Bundle struct {
+	Friend   project.Friend
+	FileName string
+	Data     []byte
+}
+
+// Build creates the bundle of each friend of p whose share is not nil,
+// verifies it, and passes it to out. Shares are in the same order as
+// p.Friends. created is the date shown in the bundles.
+//
+// Bundles are built concurrently by up to cfg.Workers goroutines, sharing
+// one copy of the compressed assets, but out is called in friend order
+// from the calling goroutine. When bundles fail, the others are still
+// built; out isn't called after the first failure, and the failures are
+// returned together, one per friend.
+func Build(p *project.Project, shares []*core.Share, manifestData []byte, created time.Time, cfg Config, out func(Bundle) error) error {
+	if len(shares) != len(p.Friends) {
+		return fmt.Errorf("have %d shares for %d friends", len(shares), len(p.Friends))
+	}
+	if cfg.PaperManifest && len(manifestData) > core.MaxPaperManifestSize {
+		return fmt.Errorf("MANIFEST.age is %d bytes, too large to print on paper (at most %d)", len(manifestData), core.MaxPaperManifestSize)
+	}
+	prefix := "bundle"
+	if cfg.Practice {
+		prefix = "practice-bundle"
+	}
+
+	var todo []int
+	for i, share := range shares {
+		if share != nil {
+			todo = append(todo, i)
+		}
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(todo))
+
+	shared := newAssets(manifestData, cfg)
+	type result struct {
+		bundle Bundle
+		err    error
+	}
+	results := make([]chan result, len(p.Friends))
+	for _, i := range todo {
+		results[i] = make(chan result, 1)
+	}
+
+	jobs := make(chan int)
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				b, err := buildOne(p, i, shares[i], shared, created, cfg, prefix)
+				results[i] <- result{b, err}
+			}
+		}()
+	}
+	go func() {
+		for _, i := range todo {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+
+	var errs []error
+	for done, i := range todo {
+		r := <-results[i]
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		if len(errs) > 0 {
+			continue
+		}
+		if err := out(r.bundle); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cfg.Progress.emit(Event{Stage: StageBundle, Friend: r.bundle.Friend.Name, Done: done + 1, Total: len(todo)})
+	}
+	return errors.Join(errs...)
+}
+
+// buildOne creates and verifies the bundle of the i-th friend of p.
+func buildOne(p *project.Project, i int, share *core.Share, shared *assets, created time.Time, cfg Config, prefix string) (Bundle, error) {
+	friend := p.Friends[i]
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, shared.params(p, i, share, created, cfg)); err != nil {
+		return Bundle{}, fmt.Errorf("generating bundle for %s: %w", friend.Name, err)
+	}
+	if err := VerifyBundleReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		return Bundle{}, fmt.Errorf("verifying bundle for %s: %w", friend.Name, err)
+	}
+	return Bundle{
+		Friend:   friend,
+		FileName: fmt.Sprintf("%s-%s.zip", prefix, core.SanitizeFilename(friend.Name)),
+		Data:     buf.Bytes(),
+	}, nil
+}
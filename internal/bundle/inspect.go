@@ -0,0 +1,460 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/translations"
+)
+
+// Kind identifies the type of a ReMemory file.
+type Kind string
+
+const (
+	KindShare       Kind = "share"         // PEM share block (SHARE-*.txt)
+	KindCompact     Kind = "compact-share" // RM2:... string, or a recovery URL containing one
+	KindReadme      Kind = "readme"        // README.txt from a bundle
+	KindBundle      Kind = "bundle"        // bundle ZIP
+	KindRecoverHTML Kind = "recover-html"  // recover.html, personalized or generic
+	KindManifest    Kind = "manifest"      // MANIFEST.age
+)
+
+// Inspection describes a ReMemory file. Only the sections that apply
+// to the file's kind are set; a bundle fills in all of them.
+type Inspection struct {
+	Kind Kind `json:"kind"`
+
+	// Share is the share found in the file, including its secret data.
+	// It is never serialized; callers decide what to reveal.
+	Share *core.Share `json:"-"`
+	// ShareError is set if the share's checksum doesn't match its data.
+	ShareError string `json:"share_error,omitempty"`
+
+	Readme   *ReadmeInfo   `json:"readme,omitempty"`
+	HTML     *HTMLInfo     `json:"recover_html,omitempty"`
+	Manifest *ManifestInfo `json:"manifest,omitempty"`
+
+	// Bundle only
+	Files       []FileInfo `json:"files,omitempty"`
+	Verified    bool       `json:"verified,omitempty"`
+	VerifyError string     `json:"verify_error,omitempty"`
+	SignedBy    string     `json:"signed_by,omitempty"` // fingerprint of the key that signed the bundle
+	// ManifestRepaired counts the damaged blocks of MANIFEST.age that were
+	// rebuilt from its parity data to verify the bundle.
+	ManifestRepaired int `json:"manifest_repaired_blocks,omitempty"`
+}
+
+// ReadmeInfo holds what can be read from a README.txt without the share itself.
+type ReadmeInfo struct {
+	Language     string            `json:"language,omitempty"`
+	Version      string            `json:"rememory_version,omitempty"`
+	Project      string            `json:"project,omitempty"`
+	Created      string            `json:"created,omitempty"`
+	OtherHolders []string          `json:"other_holders,omitempty"`
+	Practice     bool              `json:"practice,omitempty"`
+	Metadata     map[string]string `json:"metadata"` // every field of the metadata footer
+}
+
+// HTMLInfo describes a recover.html file.
+type HTMLInfo struct {
+	Version      string            `json:"rememory_version,omitempty"`
+	Size         int               `json:"size"`
+	Checksum     string            `json:"checksum"`
+	Personalized bool              `json:"personalized"`
+	Holder       string            `json:"holder,omitempty"`
+	Language     string            `json:"language,omitempty"`
+	Threshold    int               `json:"threshold,omitempty"`
+	Total        int               `json:"total,omitempty"`
+	OtherFriends []html.FriendInfo `json:"other_friends,omitempty"`
+	Practice     bool              `json:"practice,omitempty"`
+	// ManifestSize is the size of the embedded MANIFEST.age, or 0 if none is embedded
+	ManifestSize int `json:"embedded_manifest_size"`
+	// Provenance tells whether it's an official release's recover.html.
+	// Nil if its WASM couldn't be read.
+	Provenance *html.Provenance `json:"provenance,omitempty"`
+}
+
+// ManifestInfo describes an encrypted MANIFEST.age file.
+type ManifestInfo struct {
+	Size     int    `json:"size"`
+	Checksum string `json:"checksum"`
+	Embedded bool   `json:"embedded"` // read from recover.html rather than a separate file
+	// Recipients lists the age stanza types, e.g. "scrypt" for passphrase encryption
+	Recipients []string `json:"recipients"`
+	// WorkFactor is the scrypt work factor (log2 N), if passphrase-encrypted
+	WorkFactor int `json:"scrypt_work_factor,omitempty"`
+}
+
+// FileInfo is a single entry in a bundle ZIP.
+type FileInfo struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// Inspect detects what kind of ReMemory file data is and reads its metadata.
+// Nothing is decrypted and no shares are combined.
+func Inspect(data []byte) (*Inspection, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return inspectBundle(data)
+	case bytes.HasPrefix(data, []byte("age-encryption.org/")):
+		info, err := inspectManifest(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Inspection{Kind: KindManifest, Manifest: info}, nil
+	case html.IsRecoverHTML(data):
+		ins := &Inspection{Kind: KindRecoverHTML}
+		if err := ins.addHTML(data); err != nil {
+			return nil, err
+		}
+		return ins, nil
+	case bytes.Contains(data, []byte(core.ShareBegin)):
+		ins := &Inspection{Kind: KindShare}
+		if bytes.Contains(data, []byte("METADATA FOOTER")) {
+			ins.Kind = KindReadme
+			readme, err := inspectReadme(string(data))
+			if err != nil {
+				return nil, err
+			}
+			ins.Readme = readme
+		}
+		share, err := core.ParseShare(data)
+		if err != nil {
+			return nil, err
+		}
+		ins.setShare(share)
+		return ins, nil
+	case len(trimmed) > 0 && !bytes.ContainsAny(trimmed, " \n"):
+		share, err := parseCompactOrURL(string(trimmed))
+		if err != nil {
+			return nil, err
+		}
+		return &Inspection{Kind: KindCompact, Share: share}, nil
+	}
+
+	return nil, fmt.Errorf("not a recognized ReMemory file (expected a share, README.txt, bundle ZIP, recover.html or MANIFEST.age)")
+}
+
+// ExtractManifest returns the MANIFEST.age inside a bundle ZIP or a
+// personalized recover.html, or data itself if it's already MANIFEST.age.
+// In a bundle, MANIFEST.age is read from recover.html when it was embedded
+// instead of included as a separate file. A damaged MANIFEST.age is
+// repaired with the parity data stored with it.
+func ExtractManifest(data []byte) ([]byte, error) {
+	manifestData, _, err := RepairManifest(data)
+	return manifestData, err
+}
+
+// RepairManifest is ExtractManifest that also returns how many damaged
+// blocks of MANIFEST.age were rebuilt from its parity data.
+func RepairManifest(data []byte) ([]byte, int, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, 0, fmt.Errorf("opening bundle: %w", err)
+		}
+		var manifestFile, parityFile, recoverFile *zip.File
+		for _, f := range r.File {
+			switch f.Name {
+			case "MANIFEST.age":
+				manifestFile = f
+			case core.ParityFileName:
+				parityFile = f
+			case "recover.html":
+				recoverFile = f
+			}
+		}
+		if manifestFile == nil {
+			if recoverFile == nil {
+				return nil, 0, fmt.Errorf("bundle has no MANIFEST.age")
+			}
+			content, err := readZipFile(recoverFile)
+			if err != nil {
+				return nil, 0, err
+			}
+			return repairFromHTML(content)
+		}
+		manifestData, err := readZipFile(manifestFile)
+		if err != nil || parityFile == nil {
+			return manifestData, 0, err
+		}
+		parityData, err := readZipFile(parityFile)
+		if err != nil {
+			return nil, 0, err
+		}
+		return repair(manifestData, parityData)
+	case bytes.HasPrefix(data, []byte("age-encryption.org/")):
+		return data, 0, nil
+	case html.IsRecoverHTML(data):
+		return repairFromHTML(data)
+	}
+	return nil, 0, fmt.Errorf("no MANIFEST.age found (expected a bundle ZIP, recover.html or MANIFEST.age)")
+}
+
+// repairFromHTML returns the MANIFEST.age embedded in a recover.html,
+// repaired with its parity data if it has it.
+func repairFromHTML(content []byte) ([]byte, int, error) {
+	manifestData, err := html.ExtractManifestFromHTML(content)
+	if err != nil {
+		return nil, 0, err
+	}
+	parityData, err := html.ExtractParityFromHTML(content)
+	if err != nil || parityData == nil {
+		return manifestData, 0, nil
+	}
+	return repair(manifestData, parityData)
+}
+
+// repair repairs MANIFEST.age with its parity data. Parity data that can't
+// be read is ignored, so it never stands in the way of an intact manifest.
+func repair(manifestData, parityData []byte) ([]byte, int, error) {
+	repaired, n, err := core.Repair(manifestData, parityData)
+	if errors.Is(err, core.ErrUnrepairable) {
+		return nil, 0, fmt.Errorf("MANIFEST.age is damaged: %w", err)
+	}
+	if err != nil {
+		return manifestData, 0, nil
+	}
+	return repaired, n, nil
+}
+
+// parseCompactOrURL parses a compact share, either bare or inside a recovery
+// URL fragment ("recover.html#share=RM2:...") as printed in QR codes.
+func parseCompactOrURL(s string) (*core.Share, error) {
+	if i := strings.Index(s, "#share="); i >= 0 {
+		frag, err := url.QueryUnescape(s[i+len("#share="):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid share URL: %w", err)
+		}
+		s = frag
+	}
+	if !strings.HasPrefix(s, "RM") {
+		return nil, fmt.Errorf("not a recognized ReMemory file (expected a share, README.txt, bundle ZIP, recover.html or MANIFEST.age)")
+	}
+	return core.ParseCompact(s)
+}
+
+func (ins *Inspection) setShare(share *core.Share) {
+	ins.Share = share
+	if err := share.Verify(); err != nil {
+		ins.ShareError = err.Error()
+	}
+}
+
+func (ins *Inspection) addHTML(data []byte) error {
+	info := &HTMLInfo{
+		Version:  html.ExtractVersion(data),
+		Size:     len(data),
+		Checksum: core.HashBytes(data),
+	}
+	info.Provenance, _ = html.CheckRecoverHTML(data)
+	ins.HTML = info
+
+	p, err := html.ExtractPersonalization(data)
+	if err != nil || p == nil {
+		return err
+	}
+	info.Personalized = true
+	info.Holder = p.Holder
+	info.Language = p.Language
+	info.Threshold = p.Threshold
+	info.Total = p.Total
+	info.OtherFriends = p.OtherFriends
+	info.Practice = p.Practice
+
+	if p.HolderShare != "" && ins.Share == nil {
+		share, err := core.ParseShare([]byte(p.HolderShare))
+		if err != nil {
+			return fmt.Errorf("parsing holder share in recover.html: %w", err)
+		}
+		ins.setShare(share)
+	}
+
+	if p.ManifestB64 != "" {
+		manifestData, err := base64.StdEncoding.DecodeString(p.ManifestB64)
+		if err != nil {
+			return fmt.Errorf("decoding embedded manifest: %w", err)
+		}
+		info.ManifestSize = len(manifestData)
+		if ins.Manifest == nil {
+			m, err := inspectManifest(manifestData)
+			if err != nil {
+				return fmt.Errorf("embedded manifest: %w", err)
+			}
+			m.Embedded = true
+			ins.Manifest = m
+		}
+	}
+	return nil
+}
+
+// inspectReadme reads the metadata footer, language and other holders from README.txt.
+func inspectReadme(content string) (*ReadmeInfo, error) {
+	metadata := parseMetadataFooter(content)
+	info := &ReadmeInfo{
+		Language: readmeLanguage(content),
+		Version:  metadata["rememory-version"],
+		Project:  metadata["project"],
+		Created:  metadata["created"],
+		Practice: metadata["practice"] == "true",
+		Metadata: metadata,
+	}
+
+	// Other holders are listed one per line under their section heading,
+	// with contact details indented below each name.
+	heading := translations.T("readme", info.Language, "other_holders")
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != heading {
+			continue
+		}
+		// The heading is underlined, and the names follow
+		if i+2 > len(lines) {
+			return nil, fmt.Errorf("truncated README: it ends at the list of other holders")
+		}
+		for _, l := range lines[i+2:] {
+			if strings.HasPrefix(l, "-----") {
+				break
+			}
+			if l != "" && !strings.HasPrefix(l, " ") {
+				info.OtherHolders = append(info.OtherHolders, strings.TrimSpace(l))
+			}
+		}
+		break
+	}
+	return info, nil
+}
+
+// readmeLanguage detects the language of a README.txt from its title.
+func readmeLanguage(content string) string {
+	header := content
+	if i := strings.Index(content, "\n\n"); i >= 0 {
+		header = content[:i]
+	}
+	for _, lang := range translations.Languages {
+		if strings.Contains(header, translations.GetString("readme", lang, "title")) {
+			return lang
+		}
+	}
+	return "en"
+}
+
+// inspectManifest reads the age header of MANIFEST.age.
+func inspectManifest(data []byte) (*ManifestInfo, error) {
+	info := &ManifestInfo{
+		Size:       len(data),
+		Checksum:   core.HashBytes(data),
+		Recipients: []string{},
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	if !sc.Scan() || !strings.HasPrefix(sc.Text(), "age-encryption.org/v1") {
+		return nil, fmt.Errorf("not an age-encrypted file")
+	}
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "---") {
+			return info, nil
+		}
+		if !strings.HasPrefix(line, "-> ") {
+			continue
+		}
+		// Stanza: "-> type arg..." (for scrypt: "-> scrypt <salt> <log2 N>")
+		fields := strings.Fields(line[3:])
+		if len(fields) == 0 {
+			continue
+		}
+		info.Recipients = append(info.Recipients, fields[0])
+		if fields[0] == "scrypt" && len(fields) == 3 {
+			info.WorkFactor, _ = strconv.Atoi(fields[2])
+		}
+	}
+	return nil, fmt.Errorf("truncated age header")
+}
+
+// inspectBundle reads every file in a bundle ZIP and verifies its checksums.
+func inspectBundle(data []byte) (*Inspection, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("opening bundle: %w", err)
+	}
+
+	ins := &Inspection{Kind: KindBundle}
+	var recoverData []byte
+	for _, f := range r.File {
+		ins.Files = append(ins.Files, FileInfo{Name: f.Name, Size: int64(f.UncompressedSize64)})
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case translations.IsReadmeFile(f.Name, ".txt"):
+			if ins.Readme, err = inspectReadme(string(content)); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			share, err := core.ParseShare(content)
+			if err != nil {
+				return nil, fmt.Errorf("parsing share in %s: %w", f.Name, err)
+			}
+			ins.setShare(share)
+		case f.Name == "MANIFEST.age":
+			// A damaged header is reported by verification, and may be repaired
+			ins.Manifest, _ = inspectManifest(content)
+		case f.Name == "recover.html":
+			recoverData = content
+		}
+	}
+	if recoverData != nil {
+		if err := ins.addHTML(recoverData); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, err := verifyZip(r); err != nil {
+		ins.VerifyError = err.Error()
+	} else {
+		ins.Verified = true
+		if v.sig != nil {
+			ins.SignedBy = v.sig.Fingerprint()
+		}
+		if v.repaired > 0 {
+			// Describe the manifest as it was sealed, not its damaged copy
+			embedded := ins.Manifest != nil && ins.Manifest.Embedded
+			if ins.Manifest, err = inspectManifest(v.manifest); err != nil {
+				return nil, fmt.Errorf("MANIFEST.age: %w", err)
+			}
+			ins.Manifest.Embedded = embedded
+			ins.ManifestRepaired = v.repaired
+		}
+	}
+	return ins, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, core.MaxTotalSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", f.Name, err)
+	}
+	return data, nil
+}
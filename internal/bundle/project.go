@@ -0,0 +1,145 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/project"
+)
+
+// A project archive carries a seal made outside a project directory, in
+// maker.html, to the CLI: a ZIP holding project.yml with the seal's record,
+// and optionally MANIFEST.age and its parity data, laid out as they are in
+// a project. 'rememory import-project' unpacks it into a project directory,
+// which is then managed like any other.
+
+// ProjectArchiveName is the file name maker.html gives a project archive.
+const ProjectArchiveName = "rememory-project.zip"
+
+// projectArchiveManifest is where MANIFEST.age goes in a project archive.
+const projectArchiveManifest = project.OutputDir + "/MANIFEST.age"
+
+// ShareInfos returns the records of shares for project.yml, in friend order,
+// checksummed as encoded shares: the checksum a share file gets when one is
+// written replaces it.
+func ShareInfos(p *project.Project, shares []*core.Share) []project.ShareInfo {
+	infos := make([]project.ShareInfo, len(shares))
+	for i, share := range shares {
+		infos[i] = project.ShareInfo{
+			Friend:        p.Friends[i].Name,
+			Checksum:      core.HashBytes([]byte(share.Encode())),
+			ShareChecksum: share.Checksum,
+			ChallengeKey:  core.ChallengeKey(share.Data),
+		}
+	}
+	return infos
+}
+
+// Record returns the seal's record for project.yml. No share files are
+// written for it, so it's an ephemeral seal: the shares only exist inside
+// the bundles. Nor does it index the files: ones picked in a browser have
+// no modification time to tell a later copy of them by.
+func (s *Sealed) Record(p *project.Project, at time.Time, cfg Config) project.Sealed {
+	recoveryURL := cfg.RecoveryURL
+	if recoveryURL == "" {
+		recoveryURL = core.DefaultRecoveryURL
+	}
+	return project.Sealed{
+		At:               at,
+		ManifestChecksum: s.ManifestChecksum,
+		VerificationHash: s.VerificationHash,
+		Threshold:        p.Threshold,
+		Shares:           ShareInfos(p, s.Shares),
+		Version:          cfg.Version,
+		RecoveryURL:      recoveryURL,
+		Ephemeral:        true,
+	}
+}
+
+// WriteProjectArchive writes a project archive for p to w, with
+// manifestData as MANIFEST.age unless it's nil.
+func WriteProjectArchive(w io.Writer, p *project.Project, manifestData []byte) error {
+	sealed := p.CurrentSeal()
+	if sealed == nil {
+		return fmt.Errorf("project must be sealed before it's archived")
+	}
+	if manifestData != nil && core.HashBytes(manifestData) != sealed.ManifestChecksum {
+		return fmt.Errorf("MANIFEST.age doesn't match the seal's checksum")
+	}
+
+	yml, err := p.Encode()
+	if err != nil {
+		return err
+	}
+	files := []ZipFile{{Name: project.ProjectFileName, Content: yml, ModTime: sealed.At}}
+	if manifestData != nil {
+		files = append(files,
+			ZipFile{Name: projectArchiveManifest, Content: manifestData, ModTime: sealed.At},
+			ZipFile{Name: projectArchiveManifest + ".par", Content: core.NewParity(manifestData), ModTime: sealed.At},
+		)
+	}
+	return WriteZip(w, files)
+}
+
+// ReadProjectArchive reads a project archive, and returns its project,
+// which has no Path, and its MANIFEST.age, or nil if it has none. The
+// project must be valid and sealed, and MANIFEST.age must match the seal;
+// a damaged one is repaired with its parity data first.
+func ReadProjectArchive(data []byte) (*project.Project, []byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening project archive: %w", err)
+	}
+
+	var yml, manifestData, parityData []byte
+	for _, f := range zr.File {
+		var dst *[]byte
+		switch f.Name {
+		case project.ProjectFileName:
+			dst = &yml
+		case projectArchiveManifest:
+			dst = &manifestData
+		case projectArchiveManifest + ".par":
+			dst = &parityData
+		default:
+			continue
+		}
+		if *dst, err = readZipFile(f); err != nil {
+			return nil, nil, err
+		}
+	}
+	if yml == nil {
+		return nil, nil, fmt.Errorf("not a project archive: no %s", project.ProjectFileName)
+	}
+
+	p, err := project.Parse(yml)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid project: %w", err)
+	}
+	sealed := p.CurrentSeal()
+	if sealed == nil {
+		return nil, nil, fmt.Errorf("the project in the archive has not been sealed")
+	}
+	if len(sealed.Shares) != len(p.Friends) {
+		return nil, nil, fmt.Errorf("the seal has %d shares for %d friends", len(sealed.Shares), len(p.Friends))
+	}
+
+	if manifestData != nil {
+		if parityData != nil {
+			if manifestData, _, err = repair(manifestData, parityData); err != nil {
+				return nil, nil, fmt.Errorf("reading MANIFEST.age: %w", err)
+			}
+		}
+		if core.HashBytes(manifestData) != sealed.ManifestChecksum {
+			return nil, nil, fmt.Errorf("MANIFEST.age doesn't match the seal's checksum")
+		}
+	}
+	return p, manifestData, nil
+}
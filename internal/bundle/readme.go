@@ -28,6 +28,8 @@ type ReadmeData struct {
 	Anonymous        bool
 	Language         string // Bundle language (e.g. "en", "es"); defaults to "en"
 	ManifestEmbedded bool   // true when manifest is embedded in recover.html
+	Practice         bool   // true for drill bundles, which are watermarked as practice
+	SignedBy         string // fingerprint of the owner's signing key, if the bundle is signed
 }
 
 // writeWordGrid writes a two-column word grid to the string builder.
@@ -46,6 +48,15 @@ func writeWordGrid(sb *strings.Builder, words []string) {
 	}
 }
 
+// writePracticeBanner writes the notice that marks a drill bundle as practice.
+func writePracticeBanner(sb *strings.Builder, t func(string, ...any) string) {
+	sb.WriteString("********************************************************************************\n")
+	sb.WriteString(fmt.Sprintf("***  %s\n", t("practice_title")))
+	sb.WriteString("***\n")
+	sb.WriteString(fmt.Sprintf("***  %s\n", t("practice_notice")))
+	sb.WriteString("********************************************************************************\n\n")
+}
+
 // GenerateReadme creates the README.txt content with all embedded information.
 func GenerateReadme(data ReadmeData) string {
 	lang := data.Language
@@ -64,6 +75,11 @@ func GenerateReadme(data ReadmeData) string {
 	sb.WriteString(fmt.Sprintf("                              %s\n", t("for", data.Holder)))
 	sb.WriteString("================================================================================\n\n")
 
+	// Practice watermark for drill bundles
+	if data.Practice {
+		writePracticeBanner(&sb, t)
+	}
+
 	// What is this
 	sb.WriteString("--------------------------------------------------------------------------------\n")
 	sb.WriteString(fmt.Sprintf("%s\n", t("what_is_this")))
@@ -178,6 +194,10 @@ func GenerateReadme(data ReadmeData) string {
 	sb.WriteString(data.Share.Encode())
 	sb.WriteString("\n")
 
+	if data.Practice {
+		writePracticeBanner(&sb, t)
+	}
+
 	// Metadata footer (use fixed English marker for machine parsing)
 	sb.WriteString("================================================================================\n")
 	sb.WriteString("METADATA FOOTER (machine-parseable)\n")
@@ -190,6 +210,12 @@ func GenerateReadme(data ReadmeData) string {
 	sb.WriteString(fmt.Sprintf("github-release: %s\n", data.GitHubReleaseURL))
 	sb.WriteString(fmt.Sprintf("checksum-manifest: %s\n", data.ManifestChecksum))
 	sb.WriteString(fmt.Sprintf("checksum-recover-html: %s\n", data.RecoverChecksum))
+	if data.Practice {
+		sb.WriteString("practice: true\n")
+	}
+	if data.SignedBy != "" {
+		sb.WriteString(fmt.Sprintf("signed-by: %s\n", data.SignedBy))
+	}
 	sb.WriteString("================================================================================\n")
 
 	return sb.String()
@@ -3,6 +3,7 @@ package bundle
 import (
 	"archive/zip"
 	"fmt"
+	"io"
 	"os"
 	"time"
 )
@@ -20,19 +21,25 @@ func CreateZip(path string, files []ZipFile) error {
 	if err != nil {
 		return fmt.Errorf("creating zip file: %w", err)
 	}
-	defer f.Close()
-
-	w := zip.NewWriter(f)
-	defer w.Close()
+	if err := WriteZip(f, files); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
+// WriteZip writes a ZIP archive with the given files to w.
+func WriteZip(w io.Writer, files []ZipFile) error {
+	zw := zip.NewWriter(w)
 	for _, file := range files {
 		header := &zip.FileHeader{
 			Name:   file.Name,
 			Method: zip.Deflate,
 		}
-		header.Modified = file.ModTime
+		// In UTC, so the DOS timestamps don't depend on the local time zone
+		header.Modified = file.ModTime.UTC()
 
-		fw, err := w.CreateHeader(header)
+		fw, err := zw.CreateHeader(header)
 		if err != nil {
 			return fmt.Errorf("creating entry %s: %w", file.Name, err)
 		}
@@ -41,6 +48,5 @@ func CreateZip(path string, files []ZipFile) error {
 			return fmt.Errorf("writing entry %s: %w", file.Name, err)
 		}
 	}
-
-	return nil
+	return zw.Close()
 }
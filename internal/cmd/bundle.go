@@ -1,19 +1,21 @@
 package cmd
 
 import (
+	stdcrypto "crypto"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/eljojo/rememory/internal/bundle"
 	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/crypto"
 	"github.com/eljojo/rememory/internal/html"
 	"github.com/eljojo/rememory/internal/project"
 	"github.com/spf13/cobra"
 )
 
 var bundleCmd = &cobra.Command{
-	Use:   "bundle",
+	Use:   "bundle [bundle.zip...]",
 	Short: "Regenerate distribution bundles for all friends",
 	Long: `Regenerates ZIP bundles for each friend. This is useful if you:
   - Lost the original bundle files
@@ -22,6 +24,19 @@ var bundleCmd = &cobra.Command{
 Note: 'rememory seal' automatically generates bundles, so you typically
 don't need to run this command separately.
 
+If the shares aren't on disk (an ephemeral seal, or shares wiped after
+delivery), pass existing bundles from the current seal instead. Their shares
+are read back and only those friends' bundles are regenerated:
+
+  rememory bundle /media/usb1/bundle-alice.zip
+
+With --reproducible, bundles are built deterministically from the seal, so
+running it again, or on another computer with the same version of rememory,
+gives byte-identical ZIPs. Their SHA-256 checksums are printed to compare.
+
+If the seal's bundles were signed, sign the new ones with the same key:
+pass it with --sign-key.
+
 Each bundle contains:
   - README.txt (with embedded share, contacts, instructions)
   - README.pdf (same content, formatted for printing)
@@ -33,30 +48,16 @@ Each bundle contains:
 func init() {
 	bundleCmd.Flags().String("recovery-url", core.DefaultRecoveryURL, "Base URL for QR code in PDF")
 	bundleCmd.Flags().Bool("no-embed-manifest", false, "Do not embed MANIFEST.age in recover.html (it is embedded by default when 5 MB or less)")
+	bundleCmd.Flags().Bool("paper-manifest", false, "Also print MANIFEST.age in README.pdf, as QR codes and typed lines (8 KB or less)")
+	bundleCmd.Flags().Bool("reproducible", false, "Build byte-identical bundles from the same seal, and print their checksums")
+	bundleCmd.Flags().String("sign-key", "", "Sign the bundles with this Ed25519 SSH private key (e.g. ~/.ssh/id_ed25519)")
 	rootCmd.AddCommand(bundleCmd)
 }
 
 func runBundle(cmd *cobra.Command, args []string) error {
-	// Find project
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
-	}
-
-	projectDir, err := project.FindProjectDir(cwd)
-	if err != nil {
-		return fmt.Errorf("no rememory project found (run 'rememory init' first)")
-	}
-
-	// Load project
-	p, err := project.Load(projectDir)
+	p, err := loadSealedProject()
 	if err != nil {
-		return fmt.Errorf("loading project: %w", err)
-	}
-
-	// Check if sealed
-	if p.Sealed == nil {
-		return fmt.Errorf("project must be sealed before generating bundles (run 'rememory seal' first)")
+		return err
 	}
 
 	// Get embedded recovery WASM binary (smaller, for bundles)
@@ -65,11 +66,24 @@ func runBundle(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("recover.wasm not embedded - rebuild with 'make build'")
 	}
 
-	// Generate bundles
-	fmt.Printf("Generating bundles for %d friends...\n\n", len(p.Friends))
-
 	recoveryURL, _ := cmd.Flags().GetString("recovery-url")
 	noEmbedManifest, _ := cmd.Flags().GetBool("no-embed-manifest")
+	paperManifest, _ := cmd.Flags().GetBool("paper-manifest")
+	reproducible, _ := cmd.Flags().GetBool("reproducible")
+	keyPath, _ := cmd.Flags().GetString("sign-key")
+
+	seal := p.CurrentSeal()
+	var signer stdcrypto.Signer
+	if keyPath != "" {
+		if signer, err = loadSigningKey(keyPath); err != nil {
+			return newError(CodeUsage, "%v", err)
+		}
+		if seal.SignedBy != "" && signerFingerprint(signer) != seal.SignedBy {
+			return newError(CodeUsage, "this seal's bundles were signed with %s, not %s; friends comparing fingerprints would see a different key", seal.SignedBy, signerFingerprint(signer))
+		}
+	} else if seal.SignedBy != "" {
+		fmt.Fprintf(textOut, "%s this seal's bundles were signed with %s; these won't be signed (use --sign-key)\n\n", yellow("Warning:"), seal.SignedBy)
+	}
 
 	cfg := bundle.Config{
 		Version:          version,
@@ -77,26 +91,98 @@ func runBundle(cmd *cobra.Command, args []string) error {
 		WASMBytes:        wasmBytes,
 		RecoveryURL:      recoveryURL,
 		NoEmbedManifest:  noEmbedManifest,
+		PaperManifest:    paperManifest,
+		Reproducible:     reproducible,
+		Signer:           signer,
 	}
 
-	if err := bundle.GenerateAll(p, cfg); err != nil {
-		return fmt.Errorf("generating bundles: %w", err)
+	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
+	var bundles []bundleFile
+	if len(args) > 0 {
+		shares, err := sharesFromBundles(p, args)
+		if err != nil {
+			return err
+		}
+		manifestData, _, err := bundle.ReadManifestFile(p.ManifestAgePath())
+		if err != nil {
+			return fmt.Errorf("reading manifest: %w", err)
+		}
+		fmt.Fprintf(textOut, "Generating bundles for %d friend%s...\n\n", len(args), plural(len(args)))
+		paths, err := bundle.GenerateBundles(p, shares, manifestData, bundlesDir, seal.At, cfg)
+		if err != nil {
+			return fmt.Errorf("generating bundles: %w", err)
+		}
+		bundles = statBundleFiles(paths)
+	} else {
+		if seal.Ephemeral {
+			return newError(CodeUsage, "this project was sealed with --ephemeral, so no shares are on disk; pass the bundles to regenerate (rememory bundle bundle-alice.zip ...)")
+		}
+		fmt.Fprintf(textOut, "Generating bundles for %d friends...\n\n", len(p.Friends))
+		if err := bundle.GenerateAll(p, cfg); err != nil {
+			return fmt.Errorf("generating bundles: %w", err)
+		}
+		bundles = listBundleFiles(bundlesDir)
+	}
+	if reproducible {
+		for i := range bundles {
+			if bundles[i].Checksum, err = crypto.HashFile(bundles[i].Path); err != nil {
+				return fmt.Errorf("hashing %s: %w", bundles[i].Path, err)
+			}
+		}
 	}
 
 	// Print summary
-	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
-	entries, _ := os.ReadDir(bundlesDir)
+	if isJSON() {
+		return printJSON(struct {
+			Bundles []bundleFile `json:"bundles"`
+		}{bundles})
+	}
 
-	fmt.Println("Created bundles:")
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			info, _ := entry.Info()
-			fmt.Printf("  %s %s (%s)\n", green("✓"), entry.Name(), formatSize(info.Size()))
+	fmt.Fprintln(textOut, "Created bundles:")
+	for _, b := range bundles {
+		fmt.Fprintf(textOut, "  %s %s (%s)\n", green("✓"), filepath.Base(b.Path), formatSize(b.Size))
+		if b.Checksum != "" {
+			fmt.Fprintf(textOut, "    %s\n", b.Checksum)
 		}
 	}
 
-	fmt.Printf("\nBundles saved to: %s\n", bundlesDir)
-	fmt.Println("\nNote: Each README contains the friend's share - remind them not to share it!")
+	fmt.Fprintf(textOut, "\nBundles saved to: %s\n", bundlesDir)
+	fmt.Fprintln(textOut, "\nNote: Each README contains the friend's share - remind them not to share it!")
 
 	return nil
 }
+
+// sharesFromBundles reads the shares inside bundles (or README.txt files) from
+// the current seal. The result is in p.Friends order, with nil for friends
+// whose bundle wasn't given.
+func sharesFromBundles(p *project.Project, paths []string) ([]*core.Share, error) {
+	current := p.CurrentSealIndex()
+	shares := make([]*core.Share, len(p.Friends))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		ins, err := bundle.Inspect(data)
+		if err != nil || ins.Share == nil {
+			return nil, newError(CodeInvalidShare, "%s: no share found", path)
+		}
+		if ins.ShareError != "" {
+			return nil, newError(CodeInvalidShare, "%s: %s", path, ins.ShareError)
+		}
+		// Seals from before share checksums were recorded know the share file's checksum
+		switch i := p.FindSeal(ins.Share.Checksum, core.HashBytes([]byte(ins.Share.Encode()))); {
+		case i < 0:
+			return nil, newError(CodeInvalidShare, "%s isn't from this project: its share matches none of its seals", path)
+		case i != current:
+			return nil, newError(CodeInvalidShare, "%s is from seal #%d, not the current seal #%d", path, i+1, current+1)
+		}
+
+		idx, err := findFriend(p.Friends, ins.Share.Holder)
+		if err != nil {
+			return nil, newError(CodeInvalidShare, "%s belongs to %q, who isn't a friend in this project", path, ins.Share.Holder)
+		}
+		shares[idx] = ins.Share
+	}
+	return shares, nil
+}
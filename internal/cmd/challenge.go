@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var challengeCmd = &cobra.Command{
+	Use:   "challenge [friend...]",
+	Short: "Check that friends still have their share, without them sending it",
+	Long: `Challenge starts a proof-of-possession check: each share holder of the
+current seal (or only the friends named) gets a few random words.
+
+A friend answers with a short code computed from their own share, either
+with the CLI:
+
+  rememory respond --share bundle-alice.zip "apple river stone moon"
+
+or by opening recover.html from their bundle. The share itself never leaves
+their hands. Record each answer you get back:
+
+  rememory challenge verify Alice --code "lemon tiger cable fog"
+
+'rememory status' shows who proved they still have their bundle.`,
+	RunE: runChallenge,
+}
+
+var challengeVerifyCmd = &cobra.Command{
+	Use:   "verify <friend>",
+	Short: "Check a friend's answer to their last challenge",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runChallengeVerify,
+}
+
+var respondCmd = &cobra.Command{
+	Use:   "respond <challenge words>",
+	Short: "Answer a challenge from the owner with your share",
+	Long: `Respond computes the code that proves you hold a share, for a challenge
+the owner sent you. Send the code back to them; your share stays with you.
+
+The share can be read from a bundle ZIP, README.txt, SHARE-*.txt or a
+personalized recover.html. No project is needed.
+
+Example:
+  rememory respond --share bundle-alice.zip "apple river stone moon"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRespond,
+}
+
+func init() {
+	challengeVerifyCmd.Flags().String("code", "", "Code the friend sent back")
+	challengeVerifyCmd.MarkFlagRequired("code")
+	respondCmd.Flags().String("share", "", "Your bundle, README.txt or share file")
+	respondCmd.MarkFlagRequired("share")
+
+	challengeCmd.AddCommand(challengeVerifyCmd)
+	rootCmd.AddCommand(challengeCmd)
+	rootCmd.AddCommand(respondCmd)
+}
+
+// challengeResult is the JSON output of the challenge commands.
+type challengeResult struct {
+	Challenge project.ChallengeRound `json:"challenge"`
+}
+
+// respondResult is the JSON output of the respond command.
+type respondResult struct {
+	Holder    string `json:"holder,omitempty"`
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+func runChallenge(cmd *cobra.Command, args []string) error {
+	p, err := loadSealedProject()
+	if err != nil {
+		return err
+	}
+	current := p.CurrentSealIndex()
+	sealed := p.CurrentSeal()
+
+	names := sealed.Holders()
+	if len(args) > 0 {
+		if names, err = findHolders(sealed, args); err != nil {
+			return err
+		}
+	}
+
+	round := project.ChallengeRound{At: time.Now().UTC(), Seal: current + 1}
+	for _, name := range names {
+		// Seals made before challenges existed have no key yet; derive it
+		// from the share file while it's still around.
+		if _, err := ensureChallengeKey(p, current, name); err != nil {
+			return err
+		}
+		challenge, err := core.NewChallenge()
+		if err != nil {
+			return err
+		}
+		round.Holders = append(round.Holders, project.ChallengeEntry{Friend: name, Challenge: challenge})
+	}
+
+	p.Challenges = append(p.Challenges, round)
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(challengeResult{Challenge: round})
+	}
+
+	fmt.Fprintf(textOut, "Challenge for seal #%d:\n\n", round.Seal)
+	for _, e := range round.Holders {
+		fmt.Fprintf(textOut, "  %-12s %s\n", e.Friend+":", e.Challenge)
+	}
+	fmt.Fprintln(textOut, "\nSend each friend their words. They answer with:")
+	fmt.Fprintf(textOut, "  rememory respond --share bundle-%s.zip \"%s\"\n", core.SanitizeFilename(round.Holders[0].Friend), round.Holders[0].Challenge)
+	fmt.Fprintln(textOut, "or by opening recover.html from their bundle.")
+	fmt.Fprintln(textOut, "\nThen check each answer:")
+	fmt.Fprintf(textOut, "  rememory challenge verify %s --code \"...\"\n", round.Holders[0].Friend)
+	return nil
+}
+
+func runChallengeVerify(cmd *cobra.Command, args []string) error {
+	p, err := loadSealedProject()
+	if err != nil {
+		return err
+	}
+
+	last := p.LastChallenge()
+	if last == nil {
+		return newError(CodeUsage, "no challenge to verify (run 'rememory challenge' first)")
+	}
+	sealIdx := last.Seal - 1
+	names, err := findHolders(&p.Seals[sealIdx], args)
+	if err != nil {
+		return err
+	}
+	name := names[0]
+	// Rounds can cover only some friends: check against this friend's own
+	// latest challenge, not only the latest round
+	round, entry := p.LastChallengeOf(name, last.Seal)
+	if entry == nil {
+		return newError(CodeUsage, "%s hasn't been challenged for seal #%d (run 'rememory challenge %s')", name, last.Seal, name)
+	}
+
+	code, _ := cmd.Flags().GetString("code")
+	key, err := ensureChallengeKey(p, sealIdx, name)
+	if err != nil {
+		return err
+	}
+	if core.VerifyChallengeResponse(key, entry.Challenge, code) {
+		now := time.Now().UTC()
+		entry.ProvedAt = &now
+		entry.OutdatedSeal = 0
+		if err := p.Save(); err != nil {
+			return fmt.Errorf("saving project: %w", err)
+		}
+		if isJSON() {
+			return printJSON(challengeResult{Challenge: *round})
+		}
+		fmt.Fprintf(textOut, "%s %s proved they have their share from seal #%d\n", green("✓"), name, round.Seal)
+		return nil
+	}
+
+	// An answer computed from an earlier seal's share means the friend kept
+	// an old bundle; record that so status can point it out.
+	if old := matchOlderSeal(p, sealIdx, name, entry.Challenge, code); old >= 0 {
+		entry.OutdatedSeal = old + 1
+		if err := p.Save(); err != nil {
+			return fmt.Errorf("saving project: %w", err)
+		}
+		return newError(CodeVerificationFailed, "%s answered with their bundle from seal #%d, superseded on %s; they need the bundle from seal #%d",
+			name, old+1, p.SupersededAt(old).Format("2006-01-02"), round.Seal)
+	}
+	return newError(CodeVerificationFailed, "code doesn't match the challenge sent to %s on %s", name, round.At.Format("2006-01-02"))
+}
+
+func runRespond(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("share")
+	share, _, err := readShareFrom(path)
+	if err != nil {
+		return err
+	}
+
+	challenge := core.NormalizeChallenge(strings.Join(args, " "))
+	if n := len(strings.Fields(challenge)); n != core.ChallengeWords {
+		return newError(CodeUsage, "expected a challenge of %d words, got %d", core.ChallengeWords, n)
+	}
+	result := respondResult{
+		Holder:    share.Holder,
+		Challenge: challenge,
+		Code:      share.RespondToChallenge(challenge),
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+	fmt.Fprintf(textOut, "Your code: %s\n", green(result.Code))
+	fmt.Fprintln(textOut, "Send this code back. Don't send your share or bundle.")
+	return nil
+}
+
+// ensureChallengeKey returns the challenge key recorded for a friend's share
+// in a seal. If none was recorded, it is derived from the share file and
+// saved in the seal's share info.
+func ensureChallengeKey(p *project.Project, sealIdx int, friend string) (string, error) {
+	sealed := &p.Seals[sealIdx]
+	for i := range sealed.Shares {
+		si := &sealed.Shares[i]
+		if si.Friend != friend {
+			continue
+		}
+		if si.ChallengeKey != "" {
+			return si.ChallengeKey, nil
+		}
+		if si.File != "" {
+			if data, err := os.ReadFile(filepath.Join(p.Path, si.File)); err == nil {
+				if share, err := core.ParseShare(data); err == nil {
+					si.ChallengeKey = core.ChallengeKey(share.Data)
+					return si.ChallengeKey, nil
+				}
+			}
+		}
+	}
+	return "", newError(CodeUsage, "can't challenge %s: seal #%d predates challenges and their share file is gone (reseal to challenge them)", friend, sealIdx+1)
+}
+
+// matchOlderSeal checks a challenge answer against the friend's shares from
+// earlier seals. Returns the seal index that matches, or -1.
+func matchOlderSeal(p *project.Project, sealIdx int, friend, challenge, code string) int {
+	for i := sealIdx - 1; i >= 0; i-- {
+		for _, si := range p.Seals[i].Shares {
+			if si.Friend == friend && si.ChallengeKey != "" && core.VerifyChallengeResponse(si.ChallengeKey, challenge, code) {
+				return i
+			}
+		}
+	}
+	return -1
+}
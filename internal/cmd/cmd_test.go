@@ -1,9 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/manifest"
 	"github.com/eljojo/rememory/internal/project"
+	"github.com/eljojo/rememory/pkg/rememory"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestFormatSize(t *testing.T) {
@@ -66,3 +83,780 @@ func TestFriendNames(t *testing.T) {
 		}
 	}
 }
+
+func TestShareCombinations(t *testing.T) {
+	t.Run("all combinations", func(t *testing.T) {
+		subsets, total := shareCombinations(5, 3, 100)
+		if total.Int64() != 10 || len(subsets) != 10 {
+			t.Fatalf("got %d subsets (total %s), want 10", len(subsets), total)
+		}
+		seen := make(map[string]bool)
+		for _, s := range subsets {
+			if len(s) != 3 {
+				t.Errorf("subset %v has wrong size", s)
+			}
+			seen[fmt.Sprint(s)] = true
+		}
+		if len(seen) != 10 {
+			t.Errorf("got %d distinct subsets, want 10", len(seen))
+		}
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		subsets, total := shareCombinations(30, 15, 50)
+		if total.Cmp(big.NewInt(50)) <= 0 {
+			t.Fatalf("total = %s, expected more than the limit", total)
+		}
+		if len(subsets) != 50 {
+			t.Fatalf("got %d subsets, want 50", len(subsets))
+		}
+		covered := make(map[int]bool)
+		for _, s := range subsets {
+			if len(s) != 15 {
+				t.Errorf("subset %v has wrong size", s)
+			}
+			for _, idx := range s {
+				covered[idx] = true
+			}
+		}
+		if len(covered) != 30 {
+			t.Errorf("sample covers %d shares, want all 30", len(covered))
+		}
+	})
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, 0},
+		{fmt.Errorf("plain failure"), 1},
+		{newError(CodeUsage, "bad flag"), 2},
+		{newError(CodeProjectNotFound, "no project"), 3},
+		{fmt.Errorf("wrapped: %w", newError(CodeVerificationFailed, "mismatch")), 5},
+		{&Error{Code: CodeDecryptionFailed, Err: fmt.Errorf("bad key"), Reported: true}, 8},
+	}
+
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.expected {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.expected)
+		}
+	}
+}
+
+func TestValidateFriend(t *testing.T) {
+	tests := []struct {
+		friend  project.Friend
+		wantErr bool
+	}{
+		{project.Friend{Name: "Alice"}, false},
+		{project.Friend{Name: "Alice", Contact: "alice@example.com", Language: "es"}, false},
+		{project.Friend{Name: ""}, true},
+		{project.Friend{Name: strings.Repeat("a", MaxNameLength+1)}, true},
+		{project.Friend{Name: "Alice", Contact: strings.Repeat("c", MaxContactLength+1)}, true},
+		{project.Friend{Name: "Alice", Language: "xx"}, true},
+	}
+
+	for _, tt := range tests {
+		err := validateFriend(tt.friend)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateFriend(%+v) error = %v, wantErr %v", tt.friend, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckShareFilenames(t *testing.T) {
+	ok := []project.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "José"}}
+	if err := checkShareFilenames(ok); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	collide := []project.Friend{{Name: "José"}, {Name: "Bob"}, {Name: "jose"}}
+	err := checkShareFilenames(collide)
+	if err == nil {
+		t.Fatal("expected collision error")
+	}
+	if !strings.Contains(err.Error(), "SHARE-jose.txt") {
+		t.Errorf("error should name the colliding file, got: %v", err)
+	}
+}
+
+func TestFindFriend(t *testing.T) {
+	friends := []project.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "alice"}}
+
+	tests := []struct {
+		query    string
+		expected int
+		wantErr  bool
+	}{
+		{"Alice", 0, false},
+		{"alice", 2, false}, // exact match wins over case-insensitive
+		{"BOB", 1, false},
+		{"2", 1, false},
+		{"4", 0, true},
+		{"Carol", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := findFriend(friends, tt.query)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("findFriend(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.expected {
+			t.Errorf("findFriend(%q) = %d, want %d", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"es", "es"},
+		{"EN", "en"},
+		{"de-CH", "de"},
+		{"fr_CA", "fr"},
+		{"zh-tw", "zh-TW"},
+		{"xx", "xx"},
+	}
+	for _, tt := range tests {
+		if got := normalizeLanguage(tt.in); got != tt.want {
+			t.Errorf("normalizeLanguage(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeDrillCode(t *testing.T) {
+	code, err := newDrillCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Fields(code)); n != drillCodeWords {
+		t.Errorf("got %d words in %q, want %d", n, code, drillCodeWords)
+	}
+	if got := normalizeDrillCode("  " + strings.ToUpper(code) + "\n"); got != code {
+		t.Errorf("normalizeDrillCode: got %q, want %q", got, code)
+	}
+}
+
+func TestDrillCodeHash(t *testing.T) {
+	hash := func(code, salt string) string {
+		h, err := drillCodeHash(code, []byte(salt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	if hash("apple river stone", "salt one") != hash("apple river stone", "salt one") {
+		t.Error("same code and salt should hash the same")
+	}
+	if hash("apple river stone", "salt one") == hash("apple river stone", "salt two") {
+		t.Error("each drill's salt should change the hash")
+	}
+	if hash("apple river stone", "salt one") == hash("apple river moon", "salt one") {
+		t.Error("different codes should hash differently")
+	}
+	// Drills recorded before salts
+	if hash("apple river stone", "") != core.HashString("apple river stone") {
+		t.Error("unsalted drills should keep their SHA-256 hash")
+	}
+}
+
+func TestFindHolders(t *testing.T) {
+	sealed := &project.Sealed{Shares: []project.ShareInfo{{Friend: "Alice"}, {Friend: "Bob"}}}
+
+	names, err := findHolders(sealed, []string{"bob", "2", "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "Bob,Alice" {
+		t.Errorf("got %v, want [Bob Alice]", names)
+	}
+
+	if _, err := findHolders(sealed, []string{"Zed"}); err == nil {
+		t.Error("expected error for unknown holder")
+	}
+}
+
+func TestWipeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "SHARE-alice.txt")
+	if err := os.WriteFile(path, []byte("secret share"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wipeFile(path); err != nil {
+		t.Fatalf("wipeFile: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file still exists: %v", err)
+	}
+
+	// Wiping again is fine
+	if err := wipeFile(path); err != nil {
+		t.Errorf("wipeFile on missing file: %v", err)
+	}
+}
+
+func TestWipeShareCopies(t *testing.T) {
+	p := &project.Project{Path: t.TempDir()}
+	sealed := &project.Sealed{Shares: []project.ShareInfo{
+		{Friend: "Alice", File: "output/shares/SHARE-alice.txt"},
+		{Friend: "Bob", File: "output/shares/SHARE-bob.txt"},
+	}}
+	files := []string{"shares/SHARE-alice.txt", "shares/SHARE-bob.txt", "bundles/bundle-alice.zip", "bundles/bundle-bob.zip"}
+	for _, f := range files {
+		path := filepath.Join(p.OutputPath(), f)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte("share"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := wipeShareCopies(p, sealed, "Alice"); err != nil {
+		t.Fatalf("wipeShareCopies: %v", err)
+	}
+	for i, f := range files {
+		_, err := os.Stat(filepath.Join(p.OutputPath(), f))
+		if gone := os.IsNotExist(err); gone != (i%2 == 0) {
+			t.Errorf("%s: gone = %v", f, gone)
+		}
+	}
+}
+
+func TestAckWipeShareFailure(t *testing.T) {
+	t.Cleanup(func() {
+		textOut = os.Stdout
+		ackCmd.Flags().Set("wipe-share", "false")
+	})
+	textOut = io.Discard
+
+	dir := t.TempDir()
+	p := &project.Project{Name: "ack-test", Threshold: 2, Path: dir,
+		Friends: []project.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "Camila"}}}
+	delivered := time.Now().UTC()
+	sealed := project.Sealed{At: delivered}
+	for _, f := range p.Friends {
+		file := "output/shares/SHARE-" + strings.ToLower(f.Name) + ".txt"
+		sealed.Shares = append(sealed.Shares, project.ShareInfo{Friend: f.Name, File: file})
+		sealed.Deliveries = append(sealed.Deliveries, project.Delivery{Friend: f.Name, DeliveredAt: delivered, Method: "usb", Format: "zip"})
+		os.MkdirAll(filepath.Join(dir, "output", "shares"), 0755)
+		os.WriteFile(filepath.Join(dir, file), []byte("share"), 0600)
+	}
+	p.AddSeal(sealed)
+	if err := p.Save(); err != nil {
+		t.Fatal(err)
+	}
+	// Bob's bundle can't be wiped: a directory is in the way
+	os.MkdirAll(filepath.Join(p.OutputPath(), "bundles", "bundle-bob.zip", "x"), 0755)
+
+	t.Chdir(dir)
+	rootCmd.SetArgs([]string{"ack", "Alice", "Bob", "--wipe-share"})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("ack should fail when a share can't be wiped")
+	}
+	p, err := project.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := p.CurrentSeal().Delivery("Alice"); d == nil || d.ShareWipedAt == nil {
+		t.Error("Alice's share was wiped but that wasn't recorded")
+	}
+	if d := p.CurrentSeal().Delivery("Bob"); d == nil || d.ShareWipedAt != nil {
+		t.Error("Bob's share was recorded as wiped")
+	}
+}
+
+func TestParseDestinations(t *testing.T) {
+	friends := []project.Friend{{Name: "Alice"}, {Name: "Bob"}}
+
+	dests, err := parseDestinations(friends, []string{"alice=/media/usb1", "2=/media/usb2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dests["Alice"] != "/media/usb1" || dests["Bob"] != "/media/usb2" {
+		t.Errorf("got %v", dests)
+	}
+
+	for _, bad := range []string{"Alice", "=/media/usb1", "Alice=", "Zed=/media/usb1"} {
+		if _, err := parseDestinations(friends, []string{bad}); err == nil {
+			t.Errorf("parseDestinations(%q): expected error", bad)
+		}
+	}
+}
+
+func TestIsInside(t *testing.T) {
+	tests := []struct {
+		base, path string
+		want       bool
+	}{
+		{"/home/me/project", "/home/me/project", true},
+		{"/home/me/project", "/home/me/project/output/bundles", true},
+		{"/home/me/project", "/home/me/project-usb", false},
+		{"/home/me/project", "/media/usb1", false},
+		{"/home/me/project", "/home/me/..project", false},
+	}
+	for _, tt := range tests {
+		if got := isInside(tt.base, tt.path); got != tt.want {
+			t.Errorf("isInside(%q, %q) = %v, want %v", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestChallengeKeys(t *testing.T) {
+	dir := t.TempDir()
+	oldShare := core.NewShare(2, 1, 2, 2, "Alice", []byte("old share data"))
+	newShare := core.NewShare(2, 1, 2, 2, "Alice", []byte("new share data"))
+	if err := os.WriteFile(filepath.Join(dir, "SHARE-alice.txt"), []byte(newShare.Encode()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &project.Project{Path: dir}
+	p.AddSeal(project.Sealed{Shares: []project.ShareInfo{{Friend: "Alice", ChallengeKey: core.ChallengeKey(oldShare.Data)}}})
+	// A seal from before challenges: the key comes from the share file
+	p.AddSeal(project.Sealed{Shares: []project.ShareInfo{{Friend: "Alice", File: "SHARE-alice.txt"}, {Friend: "Bob", File: "SHARE-bob.txt"}}})
+
+	key, err := ensureChallengeKey(p, 1, "Alice")
+	if err != nil {
+		t.Fatalf("ensureChallengeKey: %v", err)
+	}
+	if key != core.ChallengeKey(newShare.Data) || p.Seals[1].Shares[0].ChallengeKey != key {
+		t.Error("key not derived from the share file")
+	}
+	if _, err := ensureChallengeKey(p, 1, "Bob"); err == nil {
+		t.Error("expected an error without a key or share file")
+	}
+
+	challenge := "apple river stone moon"
+	if got := matchOlderSeal(p, 1, "Alice", challenge, oldShare.RespondToChallenge(challenge)); got != 0 {
+		t.Errorf("matchOlderSeal with old share = %d, want 0", got)
+	}
+	if got := matchOlderSeal(p, 1, "Alice", challenge, newShare.RespondToChallenge(challenge)); got != -1 {
+		t.Errorf("matchOlderSeal with current share = %d, want -1", got)
+	}
+}
+
+func TestSharesFromBundles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, share *core.Share) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(share.Encode()), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	oldShare := core.NewShare(2, 1, 2, 2, "Alice", []byte("old share data"))
+	alice := core.NewShare(2, 1, 2, 2, "Alice", []byte("alice share data"))
+	bob := core.NewShare(2, 2, 2, 2, "Bob", []byte("bob share data"))
+	foreign := core.NewShare(2, 2, 2, 2, "Bob", []byte("another project"))
+
+	p := &project.Project{Path: dir, Friends: []project.Friend{{Name: "Alice"}, {Name: "Bob"}}}
+	p.AddSeal(project.Sealed{Shares: []project.ShareInfo{{Friend: "Alice", ShareChecksum: oldShare.Checksum}}})
+	// Bob's share is known by the checksum of its file, as in seals from before share checksums
+	p.AddSeal(project.Sealed{Shares: []project.ShareInfo{
+		{Friend: "Alice", ShareChecksum: alice.Checksum},
+		{Friend: "Bob", Checksum: core.HashBytes([]byte(bob.Encode()))},
+	}})
+
+	shares, err := sharesFromBundles(p, []string{write("alice.txt", alice), write("bob.txt", bob)})
+	if err != nil {
+		t.Fatalf("sharesFromBundles: %v", err)
+	}
+	if shares[0] == nil || shares[1] == nil {
+		t.Errorf("expected both shares, got %v", shares)
+	}
+	if _, err := sharesFromBundles(p, []string{write("old.txt", oldShare)}); err == nil || !strings.Contains(err.Error(), "seal #1") {
+		t.Errorf("expected an error for a share from an older seal, got %v", err)
+	}
+	if _, err := sharesFromBundles(p, []string{write("foreign.txt", foreign)}); err == nil || !strings.Contains(err.Error(), "isn't from this project") {
+		t.Errorf("expected an error for a share from another project, got %v", err)
+	}
+}
+
+func TestSealFailedBundles(t *testing.T) {
+	if len(html.GetRecoverWASMBytes()) == 0 {
+		t.Skip("recover.wasm not built")
+	}
+	t.Cleanup(func() { textOut = os.Stdout })
+	textOut = io.Discard
+
+	dir := t.TempDir()
+	p := &project.Project{Name: "seal-test", Threshold: 2, Path: dir,
+		Friends: []project.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "Camila"}}}
+	os.MkdirAll(p.ManifestPath(), 0755)
+	if err := os.WriteFile(filepath.Join(p.ManifestPath(), "secret.txt"), []byte("the secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sealProject(p, sealOptions{Ephemeral: true}); err != nil {
+		t.Fatalf("sealProject: %v", err)
+	}
+	manifestBefore, _ := os.ReadFile(p.ManifestAgePath())
+	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
+	aliceBefore, _ := os.ReadFile(filepath.Join(bundlesDir, "bundle-alice.zip"))
+
+	// Bob's USB stick can't be written to: a file is in the way
+	blocked := filepath.Join(t.TempDir(), "usb")
+	os.WriteFile(blocked, nil, 0644)
+	os.WriteFile(filepath.Join(p.ManifestPath(), "more.txt"), []byte("more"), 0644)
+	_, err := sealProject(p, sealOptions{Ephemeral: true, Destinations: map[string]string{"Bob": filepath.Join(blocked, "bundles")}})
+	if err == nil {
+		t.Fatal("sealing to an unwritable destination should fail")
+	}
+
+	// The previous seal is still current, with its files in place
+	p, err = project.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Seals) != 1 {
+		t.Errorf("a failed seal was recorded: %d seals", len(p.Seals))
+	}
+	if data, _ := os.ReadFile(p.ManifestAgePath()); !bytes.Equal(data, manifestBefore) {
+		t.Error("MANIFEST.age was replaced")
+	}
+	if data, _ := os.ReadFile(filepath.Join(bundlesDir, "bundle-alice.zip")); !bytes.Equal(data, aliceBefore) {
+		t.Error("the previous seal's bundle for Alice was replaced")
+	}
+	if files, _ := filepath.Glob(filepath.Join(bundlesDir, "*.tmp")); len(files) != 0 {
+		t.Errorf("temporary bundles left behind: %v", files)
+	}
+}
+
+func TestReshare(t *testing.T) {
+	if len(html.GetRecoverWASMBytes()) == 0 {
+		t.Skip("recover.wasm not built")
+	}
+	t.Cleanup(func() {
+		outputFormat, textOut, jsonOut = "text", os.Stdout, os.Stdout
+		reshareCmd.Flags().Set("dir", "")
+	})
+	textOut = io.Discard
+
+	// An ephemeral 2 of 3 seal
+	dir := t.TempDir()
+	p := &project.Project{Name: "reshare-test", Threshold: 2, Path: dir,
+		Friends: []project.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "Camila"}}}
+	os.MkdirAll(p.ManifestPath(), 0755)
+	if err := os.WriteFile(filepath.Join(p.ManifestPath(), "secret.txt"), []byte("the secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sealProject(p, sealOptions{Ephemeral: true}); err != nil {
+		t.Fatalf("sealProject: %v", err)
+	}
+	// Alice and Camila hand their bundles back
+	old := t.TempDir()
+	var oldBundles []string
+	for _, name := range []string{"alice", "camila"} {
+		data, err := os.ReadFile(filepath.Join(p.OutputPath(), "bundles", "bundle-"+name+".zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(old, "bundle-"+name+".zip")
+		os.WriteFile(path, data, 0644)
+		oldBundles = append(oldBundles, path)
+	}
+	friends := filepath.Join(old, "new.csv")
+	if err := os.WriteFile(friends, []byte("name\nAlice\nBob\nDana\nEve\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reshare := func(args ...string) reshareResult {
+		t.Helper()
+		var out bytes.Buffer
+		jsonOut = &out
+		rootCmd.SetArgs(append(append([]string{"reshare", "--format", "json", "--threshold", "3", "--friends", friends}, oldBundles...), args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("reshare %v: %v", args, err)
+		}
+		var result reshareResult
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatalf("reshare output: %v", err)
+		}
+		return result
+	}
+	// The new bundles recover the manifest with 3 of the 4 shares
+	recoverFrom := func(paths []string) {
+		t.Helper()
+		var shares []*core.Share
+		var manifestData []byte
+		for _, path := range paths[:3] {
+			share, _, err := readShareFrom(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			shares = append(shares, share)
+			data, _ := os.ReadFile(path)
+			manifestData, _ = bundle.ExtractManifest(data)
+		}
+		passphrase, err := rememory.Combine(shares)
+		if err != nil {
+			t.Fatalf("Combine: %v", err)
+		}
+		defer passphrase.Wipe()
+		var archive, secret bytes.Buffer
+		if err := rememory.Decrypt(&archive, bytes.NewReader(manifestData), passphrase); err != nil {
+			t.Fatalf("Decrypt: %v", err)
+		}
+		if _, err := manifest.ExtractFile(&archive, "secret.txt", &secret); err != nil || secret.String() != "the secret" {
+			t.Errorf("recovered %q, %v", secret.String(), err)
+		}
+	}
+
+	t.Run("failed bundles", func(t *testing.T) {
+		t.Chdir(dir)
+		// Eve's bundle can't be written: a directory is in the way
+		blocked := filepath.Join(p.OutputPath(), "bundles", "bundle-eve.zip")
+		os.MkdirAll(blocked, 0755)
+		defer os.Remove(blocked)
+		jsonOut = io.Discard
+		rootCmd.SetArgs(append([]string{"reshare", "--format", "json", "--threshold", "3", "--friends", friends}, oldBundles...))
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatal("reshare should fail when a bundle can't be written")
+		}
+		p, err := project.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Seals) != 1 {
+			t.Errorf("a failed reshare was recorded: %d seals", len(p.Seals))
+		}
+		if _, err := os.Stat(filepath.Join(p.OutputPath(), "bundles", "bundle-camila.zip")); err != nil {
+			t.Error("the previous seal's bundle for Camila was removed")
+		}
+	})
+
+	t.Run("in project", func(t *testing.T) {
+		t.Chdir(dir)
+		result := reshare()
+		if result.Seal != 2 || len(result.Bundles) != 4 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		p, err := project.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sealed := p.CurrentSeal()
+		if p.CurrentSealIndex() != 1 || sealed.Threshold != 3 || len(sealed.Shares) != 4 || sealed.Reshare == nil {
+			t.Errorf("unexpected seal: %+v", sealed)
+		}
+		if !sealed.Ephemeral {
+			t.Error("the reshare of an ephemeral seal must be ephemeral")
+		}
+		if files, _ := filepath.Glob(filepath.Join(p.SharesPath(), "*")); len(files) != 0 {
+			t.Errorf("share files written: %v", files)
+		}
+		if _, err := os.Stat(filepath.Join(p.OutputPath(), "bundles", "bundle-camila.zip")); !os.IsNotExist(err) {
+			t.Error("the previous seal's bundle for Camila is still there")
+		}
+		var paths []string
+		for _, b := range result.Bundles {
+			paths = append(paths, b.Path)
+		}
+		recoverFrom(paths)
+	})
+
+	t.Run("new directory", func(t *testing.T) {
+		newDir := filepath.Join(t.TempDir(), "reshared")
+		result := reshare("--dir", newDir)
+		p, err := project.Load(newDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != "reshare-test" || len(p.Seals) != 1 || p.Threshold != 3 || len(p.Friends) != 4 {
+			t.Errorf("unexpected project: %+v", p)
+		}
+		if files, _ := filepath.Glob(filepath.Join(p.SharesPath(), "SHARE-*.txt")); len(files) != 4 {
+			t.Errorf("expected 4 share files, got %v", files)
+		}
+		var paths []string
+		for _, b := range result.Bundles {
+			paths = append(paths, b.Path)
+		}
+		recoverFrom(paths)
+	})
+}
+
+func TestVerifyDeepShareErrors(t *testing.T) {
+	textOut = io.Discard
+	t.Cleanup(func() { textOut = os.Stdout })
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	parts, err := core.Split(secret, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &project.Project{Path: t.TempDir(), Threshold: 2}
+	os.MkdirAll(p.SharesPath(), 0755)
+	sealed := project.Sealed{VerificationHash: core.HashBytes(core.RecoverSecret(secret, 2).Bytes()), Current: true}
+	for i, name := range []string{"Alice", "Bob", "Camila", "Dana"} {
+		p.Friends = append(p.Friends, project.Friend{Name: name})
+		file := filepath.Join(project.OutputDir, project.SharesDir, "SHARE-"+strings.ToLower(name)+".txt")
+		sealed.Shares = append(sealed.Shares, project.ShareInfo{Friend: name, File: file})
+		var content string
+		switch name {
+		case "Alice", "Bob":
+			content = core.NewShare(2, i+1, 3, 2, name, parts[i]).Encode()
+		case "Camila":
+			// Its data doesn't match its checksum
+			share := core.NewShare(2, i+1, 3, 2, name, parts[i])
+			share.Data[0] ^= 1
+			content = share.Encode()
+		case "Dana":
+			continue // missing, and never wiped
+		}
+		if err := os.WriteFile(filepath.Join(p.Path, file), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.AddSeal(sealed)
+
+	result := verifyDeep(p, nil)
+	if result.ValidShares != 2 || len(result.FailedCombinations) != 0 {
+		t.Errorf("the good shares should recover: %+v", result)
+	}
+	if len(result.ShareErrors) != 2 || !strings.HasPrefix(result.ShareErrors[0], "SHARE-camila.txt") || !strings.HasPrefix(result.ShareErrors[1], "SHARE-dana.txt") {
+		t.Errorf("share errors: %v", result.ShareErrors)
+	}
+	if result.OK {
+		t.Error("expected deep verification to fail")
+	}
+}
+
+func TestReshareParticipants(t *testing.T) {
+	shares := []*core.Share{
+		core.NewShare(2, 1, 5, 3, "Alice", []byte("a")),
+		core.NewShare(2, 4, 5, 3, "", []byte("b")),
+	}
+	got := reshareParticipants(shares)
+	want := []string{"Alice (share 1)", "share 4"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSigningKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "owner@laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_ed25519")
+	authorized := string(ssh.MarshalAuthorizedKey(sshPub))
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath+".pub", []byte(authorized), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := loadSigningKey(keyPath)
+	if err != nil {
+		t.Fatalf("loadSigningKey: %v", err)
+	}
+	want := ssh.FingerprintSHA256(sshPub) // what ssh-keygen -l shows
+	if got := signerFingerprint(signer); got != want {
+		t.Errorf("signerFingerprint = %s, want %s", got, want)
+	}
+
+	for _, value := range []string{want, authorized, keyPath + ".pub"} {
+		got, err := readPublicKey(value)
+		if err != nil {
+			t.Errorf("readPublicKey(%q): %v", value, err)
+		} else if got != want {
+			t.Errorf("readPublicKey(%q) = %s, want %s", value, got, want)
+		}
+	}
+	if _, err := readPublicKey("ssh-rsa AAAAB3NzaC1yc2E="); err == nil {
+		t.Error("expected an error for an RSA key")
+	}
+}
+
+func TestCheckBundleSet(t *testing.T) {
+	entry := func(index int, holder, manifest string) bundleSetEntry {
+		return bundleSetEntry{
+			Path:             fmt.Sprintf("bundle-%s.zip", strings.ToLower(holder)),
+			Share:            &core.Share{Version: 2, Index: index, Total: 3, Threshold: 2, Holder: holder},
+			ManifestChecksum: manifest,
+		}
+	}
+	failed := func(checks []bundleSetCheck) []string {
+		var names []string
+		for _, c := range checks {
+			if !c.OK {
+				names = append(names, c.Name)
+			}
+		}
+		return names
+	}
+	p := &project.Project{Seals: []project.Sealed{
+		{ManifestChecksum: "sha256:old", Shares: []project.ShareInfo{{Friend: "Alice"}, {Friend: "Bob"}, {Friend: "Camila"}}},
+		{ManifestChecksum: "sha256:m", Current: true, Shares: []project.ShareInfo{{Friend: "Alice"}, {Friend: "Bob"}, {Friend: "Camila"}}},
+	}}
+
+	good := []bundleSetEntry{entry(1, "Alice", "sha256:m"), entry(2, "Bob", "sha256:m")}
+	if got := failed(checkBundleSet(good, p)); len(got) > 0 {
+		t.Errorf("good bundles failed %v", got)
+	}
+
+	tests := []struct {
+		name    string
+		entries []bundleSetEntry
+		want    string
+	}{
+		{"other manifest", []bundleSetEntry{entry(1, "Alice", "sha256:m"), entry(2, "Bob", "sha256:x")}, "manifest,holders"},
+		{"same share", []bundleSetEntry{entry(1, "Alice", "sha256:m"), entry(1, "Alice", "sha256:m")}, "shares"},
+		{"wrong holder", []bundleSetEntry{entry(1, "Alice", "sha256:m"), entry(2, "Mallory", "sha256:m")}, "holders"},
+		{"superseded", []bundleSetEntry{entry(1, "Alice", "sha256:old"), entry(2, "Bob", "sha256:old")}, "holders"},
+	}
+	for _, tt := range tests {
+		if got := failed(checkBundleSet(tt.entries, p)); strings.Join(got, ",") != tt.want {
+			t.Errorf("%s: failed %v, want [%s]", tt.name, got, tt.want)
+		}
+	}
+
+	// A reshare keeps MANIFEST.age: the shares tell the seals apart
+	shared := func(index int, holder, checksum string) bundleSetEntry {
+		e := entry(index, holder, "sha256:m")
+		e.Share.Checksum = checksum
+		return e
+	}
+	reshared := &project.Project{Seals: []project.Sealed{
+		{ManifestChecksum: "sha256:m", Shares: []project.ShareInfo{{Friend: "Alice", ShareChecksum: "sha256:a1"}, {Friend: "Bob", ShareChecksum: "sha256:b1"}}},
+		{ManifestChecksum: "sha256:m", Current: true, Shares: []project.ShareInfo{{Friend: "Alice", ShareChecksum: "sha256:a2"}, {Friend: "Bob", ShareChecksum: "sha256:b2"}}},
+	}}
+	holders := func(entries ...bundleSetEntry) bundleSetCheck {
+		checks := checkBundleHolders(entries, reshared)
+		return checks[len(checks)-1]
+	}
+	if c := holders(shared(1, "Alice", "sha256:a2"), shared(2, "Bob", "sha256:b2")); !c.OK {
+		t.Errorf("current bundles after a reshare: %s", c.Detail)
+	}
+	if c := holders(shared(1, "Alice", "sha256:a1"), shared(2, "Bob", "sha256:b1")); c.OK || !strings.Contains(c.Detail, "from seal #1, superseded") {
+		t.Errorf("bundles from before a reshare: %+v", c)
+	}
+	if c := holders(shared(1, "Alice", "sha256:a1"), shared(2, "Bob", "sha256:b2")); c.OK || !strings.Contains(c.Detail, "different seals") {
+		t.Errorf("bundles from both sides of a reshare: %+v", c)
+	}
+
+	threshold := []bundleSetEntry{entry(1, "Alice", "sha256:m"), entry(2, "Bob", "sha256:m")}
+	threshold[1].Share.Threshold = 3
+	if got := failed(checkBundleSet(threshold, nil)); strings.Join(got, ",") != "parameters" {
+		t.Errorf("different threshold: failed %v", got)
+	}
+
+	signed := []bundleSetEntry{entry(1, "Alice", "sha256:m"), entry(2, "Bob", "sha256:m")}
+	signed[0].SignedBy = "SHA256:abc"
+	if got := failed(checkBundleSet(signed, nil)); strings.Join(got, ",") != "signer" {
+		t.Errorf("partly signed: failed %v", got)
+	}
+}
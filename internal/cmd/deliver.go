@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var deliverCmd = &cobra.Command{
+	Use:   "deliver <friend>...",
+	Short: "Record that a friend's bundle was handed over",
+	Long: `Deliver records in project.yml how each friend received their bundle
+from the current seal, so you don't need a separate spreadsheet.
+
+Once a friend confirms they have it, record that with 'rememory ack'.
+'rememory status' lists deliveries that are still outstanding.
+
+Example:
+  rememory deliver Alice --method usb
+  rememory deliver Bob Camila --method post --format paper`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDeliver,
+}
+
+var ackCmd = &cobra.Command{
+	Use:   "ack <friend>...",
+	Short: "Record that a friend confirmed they have their bundle",
+	Long: `Ack records that a friend confirmed receiving their bundle.
+
+With --wipe-share, your own copies of the friend's share are overwritten
+with random data and deleted, so they no longer sit on your disk: the share
+file in output/shares/, and their bundle in output/bundles/, whose README
+and recover.html hold the same share. Copies made anywhere else, such as
+bundles written with --dest or a printed README, aren't touched. Bundles
+can't be regenerated for that friend afterwards; reseal to make new ones.
+On SSDs and copy-on-write filesystems, overwriting may not reach every copy
+of the old data.
+
+Example:
+  rememory ack Alice
+  rememory ack Bob --wipe-share`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAck,
+}
+
+// deliveryFormats are the forms a bundle can be handed over in.
+var deliveryFormats = []string{"zip", "pdf", "paper"}
+
+func init() {
+	deliverCmd.Flags().String("method", "", "How the bundle was handed over (e.g. usb, email, post, in-person)")
+	deliverCmd.Flags().String("format", "zip", "What was handed over: "+strings.Join(deliveryFormats, ", "))
+	deliverCmd.MarkFlagRequired("method")
+	ackCmd.Flags().Bool("wipe-share", false, "Securely delete your copies of the friend's share file and bundle")
+
+	rootCmd.AddCommand(deliverCmd)
+	rootCmd.AddCommand(ackCmd)
+}
+
+// deliveryResult is the JSON output of the deliver and ack commands.
+type deliveryResult struct {
+	Deliveries []project.Delivery `json:"deliveries"`
+}
+
+func runDeliver(cmd *cobra.Command, args []string) error {
+	p, err := loadSealedProject()
+	if err != nil {
+		return err
+	}
+	sealed := p.CurrentSeal()
+
+	method, _ := cmd.Flags().GetString("method")
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		return newError(CodeUsage, "--method can't be empty")
+	}
+	format, _ := cmd.Flags().GetString("format")
+	format = strings.ToLower(format)
+	if !validDeliveryFormat(format) {
+		return newError(CodeUsage, "unknown format %q (expected %s)", format, strings.Join(deliveryFormats, ", "))
+	}
+
+	names, err := findHolders(sealed, args)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var result deliveryResult
+	for _, name := range names {
+		// Delivering again replaces the earlier record, e.g. after a lost USB stick
+		d := project.Delivery{Friend: name, DeliveredAt: now, Method: method, Format: format}
+		if existing := sealed.Delivery(name); existing != nil {
+			*existing = d
+		} else {
+			sealed.Deliveries = append(sealed.Deliveries, d)
+		}
+		result.Deliveries = append(result.Deliveries, d)
+	}
+
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+	for _, d := range result.Deliveries {
+		fmt.Fprintf(textOut, "%s Delivered to %s (%s, %s)\n", green("✓"), d.Friend, d.Method, d.Format)
+	}
+	fmt.Fprintln(textOut, "  Run 'rememory ack' once they confirm they have it.")
+	return nil
+}
+
+func runAck(cmd *cobra.Command, args []string) error {
+	p, err := loadSealedProject()
+	if err != nil {
+		return err
+	}
+	sealed := p.CurrentSeal()
+	wipe, _ := cmd.Flags().GetBool("wipe-share")
+
+	names, err := findHolders(sealed, args)
+	if err != nil {
+		return err
+	}
+
+	// Check everything first, so nothing is half-recorded
+	for _, name := range names {
+		if sealed.Delivery(name) == nil {
+			return newError(CodeUsage, "%s's bundle hasn't been delivered yet (run 'rememory deliver %s --method ...' first)", name, name)
+		}
+	}
+
+	now := time.Now().UTC()
+	var result deliveryResult
+	for _, name := range names {
+		d := sealed.Delivery(name)
+		if d.AckedAt == nil {
+			d.AckedAt = &now
+		}
+		if wipe && d.ShareWipedAt == nil {
+			if err := wipeShareCopies(p, sealed, name); err != nil {
+				// Record the shares already wiped, or verify would report them missing
+				if saveErr := p.Save(); saveErr != nil {
+					return fmt.Errorf("wiping share for %s: %w (and saving project: %v)", name, err, saveErr)
+				}
+				return fmt.Errorf("wiping share for %s: %w", name, err)
+			}
+			d.ShareWipedAt = &now
+		}
+		result.Deliveries = append(result.Deliveries, *d)
+	}
+
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+	for _, d := range result.Deliveries {
+		fmt.Fprintf(textOut, "%s %s confirmed their bundle\n", green("✓"), d.Friend)
+		if wipe && d.ShareWipedAt != nil {
+			fmt.Fprintf(textOut, "  Share file and bundle wiped from %s\n", project.OutputDir+string(filepath.Separator))
+		}
+	}
+	if wipe {
+		fmt.Fprintln(textOut, "  Copies elsewhere (bundles written with --dest, USB sticks, printouts) still hold the share.")
+	}
+	return nil
+}
+
+// wipeShareCopies wipes the copies of a friend's share the project keeps:
+// their share file, and their bundle in output/bundles, which holds the same
+// share in README.txt, README.pdf and recover.html.
+func wipeShareCopies(p *project.Project, sealed *project.Sealed, name string) error {
+	for _, si := range sealed.Shares {
+		if si.Friend != name || si.File == "" {
+			continue
+		}
+		if err := wipeFile(filepath.Join(p.Path, si.File)); err != nil {
+			return err
+		}
+	}
+	bundlePath := filepath.Join(p.OutputPath(), "bundles", "bundle-"+core.SanitizeFilename(name)+".zip")
+	return wipeFile(bundlePath)
+}
+
+// findHolders matches friend names or numbers against the holders of a seal.
+func findHolders(sealed *project.Sealed, queries []string) ([]string, error) {
+	holders := make([]project.Friend, len(sealed.Shares))
+	for i, name := range sealed.Holders() {
+		holders[i] = project.Friend{Name: name}
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, q := range queries {
+		idx, err := findFriend(holders, q)
+		if err != nil {
+			return nil, newError(CodeUsage, "no share holder named %q in the current seal (run 'rememory status')", q)
+		}
+		if name := holders[idx].Name; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func validDeliveryFormat(format string) bool {
+	for _, f := range deliveryFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// wipeFile overwrites a file with random data, flushes it to disk and deletes
+// it. A file that's already gone is not an error.
+func wipeFile(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	noise := make([]byte, info.Size())
+	if _, err := rand.Read(noise); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.WriteAt(noise, 0); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -51,7 +51,7 @@ func runDemo(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("directory already exists: %s", dir)
 	}
 
-	fmt.Printf("Creating demo project: %s/\n\n", dirName)
+	fmt.Fprintf(textOut, "Creating demo project: %s/\n\n", dirName)
 
 	// Demo friends
 	friends := []project.Friend{
@@ -61,8 +61,8 @@ func runDemo(cmd *cobra.Command, args []string) error {
 	}
 	threshold := 2
 
-	fmt.Printf("Friends: %s\n", friendNames(friends))
-	fmt.Printf("Threshold: %d of %d\n\n", threshold, len(friends))
+	fmt.Fprintf(textOut, "Friends: %s\n", friendNames(friends))
+	fmt.Fprintf(textOut, "Threshold: %d of %d\n\n", threshold, len(friends))
 
 	// Create the project
 	p, err := project.New(dir, "Demo Project", threshold, friends)
@@ -115,25 +115,30 @@ Note: In a real project, these would be your actual sensitive credentials.
 		return fmt.Errorf("writing passwords file: %w", err)
 	}
 
-	fmt.Println("Created demo files:")
-	fmt.Printf("  %s manifest/demo-secret.txt\n", green("✓"))
-	fmt.Printf("  %s manifest/passwords.txt\n", green("✓"))
-	fmt.Println()
+	fmt.Fprintln(textOut, "Created demo files:")
+	fmt.Fprintf(textOut, "  %s manifest/demo-secret.txt\n", green("✓"))
+	fmt.Fprintf(textOut, "  %s manifest/passwords.txt\n", green("✓"))
+	fmt.Fprintln(textOut)
 
-	if err := sealProject(p, "", false); err != nil {
+	result, err := sealProject(p, sealOptions{})
+	if err != nil {
 		return err
 	}
 
-	fmt.Println()
-	fmt.Println("Demo project created successfully!")
-	fmt.Println()
-	fmt.Println("To test recovery:")
-	fmt.Printf("  1. Open %s/output/bundles/bundle-alice.zip\n", dirName)
-	fmt.Println("  2. Extract and open recover.html in a browser")
-	fmt.Println("  3. Alice's share is pre-loaded, add Bob's or Camila's README/LEEME file")
-	fmt.Println("  4. Recovery will happen automatically!")
-	fmt.Println()
-	fmt.Println("Camila's bundle is in Spanish - check bundle-camila.zip to see!")
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "Demo project created successfully!")
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "To test recovery:")
+	fmt.Fprintf(textOut, "  1. Open %s/output/bundles/bundle-alice.zip\n", dirName)
+	fmt.Fprintln(textOut, "  2. Extract and open recover.html in a browser")
+	fmt.Fprintln(textOut, "  3. Alice's share is pre-loaded, add Bob's or Camila's README/LEEME file")
+	fmt.Fprintln(textOut, "  4. Recovery will happen automatically!")
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "Camila's bundle is in Spanish - check bundle-camila.zip to see!")
 
 	return nil
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eljojo/rememory/internal/manifest"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show manifest changes since the last seal",
+	Long: `Diff compares the manifest/ directory with the file index recorded
+in project.yml the last time the project was sealed.
+
+It lists files that were added, removed or modified. If anything changed,
+the bundles your friends hold are out of date and the project should be
+resealed with 'rememory seal'.`,
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	p, err := loadSealedProject()
+	if err != nil {
+		return err
+	}
+	sealed := p.CurrentSeal()
+	if len(sealed.Files) == 0 {
+		return fmt.Errorf("project was sealed without a file index; run 'rememory verify --deep' to compare against MANIFEST.age, or reseal")
+	}
+
+	changes, err := manifestChanges(p)
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(struct {
+			SealedAt time.Time `json:"sealed_at"`
+			Changed  bool      `json:"changed"`
+			manifest.Changes
+		}{sealed.At, !changes.IsEmpty(), changes})
+	}
+
+	if changes.IsEmpty() {
+		fmt.Fprintf(textOut, "No changes since seal (%s).\n", sealed.At.Format("2006-01-02 15:04:05 UTC"))
+		return nil
+	}
+
+	fmt.Fprintf(textOut, "Changes since seal (%s):\n", sealed.At.Format("2006-01-02 15:04:05 UTC"))
+	printChanges(changes)
+	fmt.Fprintln(textOut)
+	fmt.Fprintf(textOut, "%s Run 'rememory seal' so your friends' bundles include these changes.\n", yellow("Reseal needed."))
+
+	return nil
+}
+
+// manifestChanges compares manifest/ with the file index recorded at seal time.
+func manifestChanges(p *project.Project) (manifest.Changes, error) {
+	current, err := manifest.HashDir(p.ManifestPath())
+	if err != nil {
+		return manifest.Changes{}, fmt.Errorf("reading manifest: %w", err)
+	}
+	return manifest.Compare(p.CurrentSeal().Files, current), nil
+}
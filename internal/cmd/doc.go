@@ -34,16 +34,20 @@ func runDoc(cmd *cobra.Command, args []string) error {
 		if err := doc.GenManTree(rootCmd, header, outputDir); err != nil {
 			return fmt.Errorf("generating man pages: %w", err)
 		}
-		fmt.Printf("Man pages generated in %s\n", outputDir)
+		fmt.Fprintf(textOut, "Man pages generated in %s\n", outputDir)
 
 	case "markdown":
 		if err := doc.GenMarkdownTree(rootCmd, outputDir); err != nil {
 			return fmt.Errorf("generating markdown: %w", err)
 		}
-		fmt.Printf("Markdown docs generated in %s\n", outputDir)
+		fmt.Fprintf(textOut, "Markdown docs generated in %s\n", outputDir)
+
+	case "json":
+		// doc's own --format takes the place of the global one
+		return newError(CodeUsage, "doc has no JSON output (use --format man or markdown)")
 
 	default:
-		return fmt.Errorf("unknown format: %s (use 'man' or 'markdown')", docFormat)
+		return newError(CodeUsage, "unknown format: %s (use 'man' or 'markdown')", docFormat)
 	}
 
 	return nil
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/manifest"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/scrypt"
+)
+
+var drillCmd = &cobra.Command{
+	Use:   "drill",
+	Short: "Create practice bundles to rehearse recovery",
+	Long: `Drill creates a parallel set of practice bundles for the same friends,
+languages and threshold as your project, so everyone can rehearse recovery
+without touching their real bundle.
+
+Practice bundles use a fresh passphrase and contain only a harmless test file
+with a code word. README.txt, README.pdf and recover.html are all clearly
+marked as PRACTICE. Your real shares, manifest and bundles are not touched.
+
+Once your friends have recovered the practice files, ask them for the code
+word and record who took part:
+
+  rememory drill complete --code "word word word" Alice Bob
+
+'rememory status' shows when the last drill happened.`,
+	Args: cobra.NoArgs,
+	RunE: runDrill,
+}
+
+var drillCompleteCmd = &cobra.Command{
+	Use:   "complete <friend>...",
+	Short: "Record which friends recovered the practice bundles",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDrillComplete,
+}
+
+func init() {
+	drillCmd.Flags().String("recovery-url", core.DefaultRecoveryURL, "Base URL for QR code in PDF")
+	drillCompleteCmd.Flags().String("code", "", "Code word found in the recovered practice files")
+	drillCompleteCmd.MarkFlagRequired("code")
+
+	drillCmd.AddCommand(drillCompleteCmd)
+	rootCmd.AddCommand(drillCmd)
+}
+
+// drillResult is the JSON output of the drill commands.
+type drillResult struct {
+	Drill   project.Drill `json:"drill"`
+	Dir     string        `json:"dir,omitempty"`
+	Bundles []bundleFile  `json:"bundles,omitempty"`
+	Missing []string      `json:"missing,omitempty"`
+}
+
+// drillCodeWords is the number of words in a drill's code word.
+const drillCodeWords = 3
+
+func runDrill(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid project: %w", err)
+	}
+
+	recoveryURL, _ := cmd.Flags().GetString("recovery-url")
+
+	code, err := newDrillCode()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(textOut, "Creating practice bundles for %d friends (threshold: %d)...\n", len(p.Friends), p.Threshold)
+
+	// Fresh passphrase, sealed like a real manifest
+	practice := manifest.File{Name: "PRACTICE.md", Data: []byte(drillInstructions(p.Name, code))}
+	sealed, err := bundle.SealFiles(p, []manifest.File{practice}, nil)
+	if err != nil {
+		return err
+	}
+
+	wasmBytes := html.GetRecoverWASMBytes()
+	if len(wasmBytes) == 0 {
+		return fmt.Errorf("recover.wasm not embedded - rebuild with 'make build'")
+	}
+
+	// Replace bundles from any earlier drill
+	dir := p.DrillPath()
+	if err := removeBundles(dir); err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("generating salt: %w", err)
+	}
+	codeHash, err := drillCodeHash(code, salt)
+	if err != nil {
+		return err
+	}
+	drill := project.Drill{
+		At:       time.Now().UTC(),
+		CodeHash: codeHash,
+		CodeSalt: hex.EncodeToString(salt),
+	}
+	cfg := bundle.Config{
+		Version:          version,
+		GitHubReleaseURL: fmt.Sprintf("https://github.com/eljojo/rememory/releases/tag/%s", version),
+		WASMBytes:        wasmBytes,
+		RecoveryURL:      recoveryURL,
+		Practice:         true,
+	}
+	if _, err := bundle.GenerateBundles(p, sealed.Shares, sealed.Manifest, dir, drill.At, cfg); err != nil {
+		return fmt.Errorf("generating practice bundles: %w", err)
+	}
+
+	p.Drills = append(p.Drills, drill)
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	bundles := listBundleFiles(dir)
+	if isJSON() {
+		return printJSON(drillResult{Drill: drill, Dir: dir, Bundles: bundles})
+	}
+
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "Practice bundles ready:")
+	for _, b := range bundles {
+		fmt.Fprintf(textOut, "  %s %s (%s)\n", green("✓"), filepath.Base(b.Path), formatSize(b.Size))
+	}
+	fmt.Fprintf(textOut, "\nSaved to: %s\n\n", dir)
+	fmt.Fprintln(textOut, "Next steps:")
+	fmt.Fprintln(textOut, "  1. Send each friend their practice bundle and agree on a time to rehearse")
+	fmt.Fprintf(textOut, "  2. Any %d of them recover the practice files together, which contain a code word\n", p.Threshold)
+	fmt.Fprintln(textOut, "  3. Record who took part:")
+	fmt.Fprintln(textOut, "       rememory drill complete --code \"<code word>\" <friend>...")
+	return nil
+}
+
+func runDrillComplete(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	drill := p.LastDrill()
+	if drill == nil {
+		return newError(CodeUsage, "no drill to complete (run 'rememory drill' first)")
+	}
+
+	code, _ := cmd.Flags().GetString("code")
+	salt, err := hex.DecodeString(drill.CodeSalt)
+	if err != nil {
+		return fmt.Errorf("reading the last drill's code salt: %w", err)
+	}
+	codeHash, err := drillCodeHash(normalizeDrillCode(code), salt)
+	if err != nil {
+		return err
+	}
+	if !core.VerifyHash(codeHash, drill.CodeHash) {
+		return newError(CodeVerificationFailed, "code word doesn't match the last drill (from %s)", drill.At.Format("2006-01-02"))
+	}
+
+	// Collect participants in the project's friend order, without duplicates
+	took := make(map[int]bool)
+	for _, arg := range args {
+		idx, err := findFriend(p.Friends, arg)
+		if err != nil {
+			return err
+		}
+		took[idx] = true
+	}
+	var participants, missing []string
+	for i, f := range p.Friends {
+		if took[i] {
+			participants = append(participants, f.Name)
+		} else {
+			missing = append(missing, f.Name)
+		}
+	}
+
+	now := time.Now().UTC()
+	drill.CompletedAt = &now
+	drill.Participants = participants
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(drillResult{Drill: *drill, Missing: missing})
+	}
+
+	fmt.Fprintf(textOut, "%s Drill recorded: %s recovered the practice files.\n", green("✓"), strings.Join(participants, ", "))
+	if len(participants) < p.Threshold {
+		fmt.Fprintf(textOut, "  %s\n", yellow(fmt.Sprintf("Only %d of the %d needed to recover took part.", len(participants), p.Threshold)))
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(textOut, "  Not yet practiced: %s\n", strings.Join(missing, ", "))
+	}
+	fmt.Fprintln(textOut, "  Friends can now delete their practice bundles.")
+	return nil
+}
+
+// newDrillCode picks random words from the English word list.
+func newDrillCode() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating code word: %w", err)
+	}
+	words := core.EncodeWords(b)
+	if len(words) > drillCodeWords {
+		words = words[:drillCodeWords]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// drillCodeHash hashes a drill's code word for project.yml. The code is only
+// a few words, so it's stretched with scrypt under the drill's own salt:
+// guessing it from project.yml takes as long as the guesses do. Drills
+// recorded without a salt hashed it with plain SHA-256.
+func drillCodeHash(code string, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		return core.HashString(code), nil
+	}
+	key, err := scrypt.Key([]byte(code), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return "", fmt.Errorf("hashing code word: %w", err)
+	}
+	return "scrypt:" + hex.EncodeToString(key), nil
+}
+
+// normalizeDrillCode makes code word comparison ignore case and spacing.
+func normalizeDrillCode(code string) string {
+	return strings.Join(strings.Fields(strings.ToLower(code)), " ")
+}
+
+// drillInstructions is the practice file recovered in a drill, holding the code word.
+func drillInstructions(projectName, code string) string {
+	return fmt.Sprintf(`# Recovery drill: %s
+
+If you can read this, the recovery worked. Well done!
+
+This was only a practice run: there are no real secrets in here.
+Tell the person who organized the drill this code word, so they
+know it worked:
+
+    %s
+
+You can delete your practice bundle now. Keep your real bundle safe.
+`, projectName, strings.ToUpper(code))
+}
+
+// removeBundles deletes the ZIP files in dir, if any.
+func removeBundles(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".zip" {
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+				return fmt.Errorf("removing old practice bundle: %w", err)
+			}
+		}
+	}
+	return nil
+}
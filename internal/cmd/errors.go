@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/eljojo/rememory/pkg/rememory"
+)
+
+// ErrorCode identifies a class of failure. Codes are part of the JSON
+// output and map to process exit statuses, so scripts can rely on them.
+type ErrorCode string
+
+const (
+	CodeError              ErrorCode = "error"
+	CodeUsage              ErrorCode = "usage"
+	CodeProjectNotFound    ErrorCode = "project_not_found"
+	CodeNotSealed          ErrorCode = "not_sealed"
+	CodeVerificationFailed ErrorCode = "verification_failed"
+	CodeInvalidShare       ErrorCode = "invalid_share"
+	CodeInsufficientShares ErrorCode = "insufficient_shares"
+	CodeDecryptionFailed   ErrorCode = "decryption_failed"
+)
+
+// exitCodes maps error codes to process exit statuses.
+var exitCodes = map[ErrorCode]int{
+	CodeError:              1,
+	CodeUsage:              2,
+	CodeProjectNotFound:    3,
+	CodeNotSealed:          4,
+	CodeVerificationFailed: 5,
+	CodeInvalidShare:       6,
+	CodeInsufficientShares: 7,
+	CodeDecryptionFailed:   8,
+}
+
+// Error is a command failure with a stable error code.
+type Error struct {
+	Code ErrorCode
+	Err  error
+	// Reported is set when the command's JSON output already describes
+	// the failure, so no separate error document is printed.
+	Reported bool
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// newError creates an Error with a formatted message.
+func newError(code ErrorCode, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// errorCode returns the code of the first Error in err's chain. Otherwise
+// errors from the rememory package map to their code, or CodeError.
+func errorCode(err error) ErrorCode {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e.Code
+	case errors.Is(err, rememory.ErrThresholdNotMet):
+		return CodeInsufficientShares
+	case errors.Is(err, rememory.ErrMixedSeals), errors.Is(err, rememory.ErrDuplicateShare), errors.Is(err, rememory.ErrChecksumMismatch):
+		return CodeInvalidShare
+	case errors.Is(err, rememory.ErrDecryptionFailed):
+		return CodeDecryptionFailed
+	}
+	return CodeError
+}
+
+// ExitCode returns the process exit status for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return exitCodes[errorCode(err)]
+}
+
+// loadProject finds and loads the project containing the current directory.
+func loadProject() (*project.Project, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+
+	projectDir, err := project.FindProjectDir(cwd)
+	if err != nil {
+		return nil, newError(CodeProjectNotFound, "no rememory project found (run 'rememory init' first)")
+	}
+
+	p, err := project.Load(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+	return p, nil
+}
+
+// loadSealedProject is like loadProject, but also requires the project to be sealed.
+func loadSealedProject() (*project.Project, error) {
+	p, err := loadProject()
+	if err != nil {
+		return nil, err
+	}
+	if p.CurrentSeal() == nil {
+		return nil, newError(CodeNotSealed, "project has not been sealed yet; run 'rememory seal' first")
+	}
+	return p, nil
+}
@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var friendCmd = &cobra.Command{
+	Use:   "friend",
+	Short: "List, add, edit or remove share holders",
+	Long: `Manage the friends who hold shares, without hand-editing project.yml.
+
+Friends are validated with the same rules as 'rememory init'. Changes only
+take effect once the project is resealed: bundles you've already handed out
+keep working with the old group until you ask friends to destroy them.
+
+Example:
+  rememory friend list
+  rememory friend add "Dana" --contact dana@example.com --language es
+  rememory friend edit "Dana" --contact "+1 555 0100"
+  rememory friend remove "Bob" --threshold 2
+  rememory friend import contacts.vcf --dry-run`,
+}
+
+var friendListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List share holders",
+	Args:  cobra.NoArgs,
+	RunE:  runFriendList,
+}
+
+var friendAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a share holder",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFriendAdd,
+}
+
+var friendEditCmd = &cobra.Command{
+	Use:   "edit <name|number>",
+	Short: "Change a share holder's name, contact or language",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFriendEdit,
+}
+
+var friendImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add share holders from a CSV, vCard, JSON or YAML file",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFriendImport,
+}
+
+var friendRemoveCmd = &cobra.Command{
+	Use:   "remove <name|number>",
+	Short: "Remove a share holder",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runFriendRemove,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{friendAddCmd, friendEditCmd} {
+		c.Flags().String("contact", "", "Contact info (phone, email, address...)")
+		c.Flags().String("language", "", "Bundle language for this friend (e.g. en, es, de)")
+	}
+	friendEditCmd.Flags().String("name", "", "New name")
+	for _, c := range []*cobra.Command{friendAddCmd, friendRemoveCmd, friendImportCmd} {
+		c.Flags().Int("threshold", 0, "New number of shares needed to recover")
+	}
+	friendImportCmd.Flags().Bool("dry-run", false, "Show the friends that would be added without saving")
+
+	friendCmd.AddCommand(friendListCmd, friendAddCmd, friendEditCmd, friendRemoveCmd, friendImportCmd)
+	rootCmd.AddCommand(friendCmd)
+}
+
+// friendListResult is the JSON output of the friend commands.
+type friendListResult struct {
+	Threshold     int              `json:"threshold"`
+	Total         int              `json:"total"`
+	Friends       []project.Friend `json:"friends"`
+	ResealNeeded  bool             `json:"reseal_needed"`
+	PrevThreshold int              `json:"previous_threshold,omitempty"`
+	PrevTotal     int              `json:"previous_total,omitempty"`
+}
+
+func runFriendList(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	if isJSON() {
+		return printJSON(friendListResult{Threshold: p.Threshold, Total: len(p.Friends), Friends: p.Friends})
+	}
+
+	fmt.Fprintf(textOut, "Share holders (%d of %d needed to recover):\n", p.Threshold, len(p.Friends))
+	for i, f := range p.Friends {
+		details := []string{fmt.Sprintf("SHARE-%s.txt", core.SanitizeFilename(f.Name))}
+		if f.Contact != "" {
+			details = append(details, f.Contact)
+		}
+		if f.Language != "" {
+			details = append(details, "language: "+f.Language)
+		}
+		fmt.Fprintf(textOut, "  %d. %s (%s)\n", i+1, f.Name, strings.Join(details, ", "))
+	}
+	return nil
+}
+
+func runFriendAdd(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	contact, _ := cmd.Flags().GetString("contact")
+	language, _ := cmd.Flags().GetString("language")
+	friend := project.Friend{
+		Name:     strings.TrimSpace(args[0]),
+		Contact:  strings.TrimSpace(contact),
+		Language: strings.TrimSpace(language),
+	}
+	if err := validateFriend(friend); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+
+	friends := append(append([]project.Friend{}, p.Friends...), friend)
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	return updateFriends(p, friends, threshold, fmt.Sprintf("Added %s.", friend.Name))
+}
+
+func runFriendEdit(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	idx, err := findFriend(p.Friends, args[0])
+	if err != nil {
+		return err
+	}
+
+	friends := append([]project.Friend{}, p.Friends...)
+	friend := &friends[idx]
+	oldName := friend.Name
+
+	if cmd.Flags().Changed("name") {
+		name, _ := cmd.Flags().GetString("name")
+		friend.Name = strings.TrimSpace(name)
+	}
+	if cmd.Flags().Changed("contact") {
+		contact, _ := cmd.Flags().GetString("contact")
+		friend.Contact = strings.TrimSpace(contact)
+	}
+	if cmd.Flags().Changed("language") {
+		language, _ := cmd.Flags().GetString("language")
+		friend.Language = strings.TrimSpace(language)
+	}
+	if err := validateFriend(*friend); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+
+	return updateFriends(p, friends, 0, fmt.Sprintf("Updated %s.", oldName))
+}
+
+func runFriendRemove(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	idx, err := findFriend(p.Friends, args[0])
+	if err != nil {
+		return err
+	}
+	removed := p.Friends[idx]
+
+	friends := append(append([]project.Friend{}, p.Friends[:idx]...), p.Friends[idx+1:]...)
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	if threshold == 0 && p.Threshold > len(friends) {
+		return newError(CodeUsage, "removing %s would leave %d friends with a threshold of %d; pass --threshold to lower it", removed.Name, len(friends), p.Threshold)
+	}
+	return updateFriends(p, friends, threshold, fmt.Sprintf("Removed %s.", removed.Name))
+}
+
+func runFriendImport(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
+	if err != nil {
+		return err
+	}
+
+	imported, err := loadFriendsFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	friends := append(append([]project.Friend{}, p.Friends...), imported.FriendList()...)
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	summary := fmt.Sprintf("Added %d friend%s from %s.", len(imported.Friends), plural(len(imported.Friends)), args[0])
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !dryRun {
+		return updateFriends(p, friends, threshold, summary)
+	}
+
+	// Run the same checks as a real import, without saving
+	if err := checkShareFilenames(friends); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+	if threshold == 0 {
+		threshold = p.Threshold
+	}
+	preview := *p
+	preview.Friends = friends
+	preview.Threshold = threshold
+	if err := preview.Validate(); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+
+	if isJSON() {
+		return printJSON(friendListResult{Threshold: threshold, Total: len(friends), Friends: friends, ResealNeeded: p.CurrentSeal() != nil})
+	}
+	fmt.Fprintf(textOut, "Dry run: would add %d friend%s from %s.\n", len(imported.Friends), plural(len(imported.Friends)), args[0])
+	printFriendPreview(friends, threshold)
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "Nothing was saved. Run again without --dry-run to add them.")
+	return nil
+}
+
+// findFriend returns the index of the friend matching a name (case-insensitive)
+// or a 1-based number as shown by 'rememory friend list'.
+func findFriend(friends []project.Friend, query string) (int, error) {
+	for i, f := range friends {
+		if f.Name == query {
+			return i, nil
+		}
+	}
+	for i, f := range friends {
+		if strings.EqualFold(f.Name, query) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(query); err == nil && n >= 1 && n <= len(friends) {
+		return n - 1, nil
+	}
+	return 0, newError(CodeUsage, "no friend named %q (run 'rememory friend list')", query)
+}
+
+// updateFriends validates and saves a new friend list, then prints the summary
+// and explains how the threshold changed and whether the project must be resealed.
+// A threshold of 0 keeps the current one.
+func updateFriends(p *project.Project, friends []project.Friend, threshold int, summary string) error {
+	if err := checkShareFilenames(friends); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+
+	prevThreshold, prevTotal := p.Threshold, len(p.Friends)
+	if threshold == 0 {
+		threshold = p.Threshold
+	}
+
+	p.Friends = friends
+	p.Threshold = threshold
+	if err := p.Validate(); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	result := friendListResult{
+		Threshold:    p.Threshold,
+		Total:        len(p.Friends),
+		Friends:      p.Friends,
+		ResealNeeded: p.CurrentSeal() != nil,
+	}
+	if prevThreshold != p.Threshold || prevTotal != len(p.Friends) {
+		result.PrevThreshold = prevThreshold
+		result.PrevTotal = prevTotal
+	}
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	fmt.Fprintln(textOut, summary)
+	if result.PrevTotal != 0 {
+		fmt.Fprintf(textOut, "Threshold: %d of %d → %d of %d\n", prevThreshold, prevTotal, p.Threshold, len(p.Friends))
+	} else {
+		fmt.Fprintf(textOut, "Threshold: %d of %d (unchanged)\n", p.Threshold, len(p.Friends))
+	}
+	fmt.Fprintf(textOut, "  %s\n", describeThreshold(p.Threshold, len(p.Friends)))
+
+	if p.CurrentSeal() != nil {
+		fmt.Fprintln(textOut)
+		fmt.Fprintf(textOut, "%s The existing shares and bundles still use the old group.\n", yellow("Reseal needed."))
+		fmt.Fprintln(textOut, "  Run 'rememory seal' to create new ones, then ask friends to destroy their old bundles.")
+	}
+	return nil
+}
+
+// describeThreshold explains what a K-of-N threshold means in practice.
+func describeThreshold(threshold, total int) string {
+	spare := total - threshold
+	if spare == 0 {
+		return yellow("Every friend is needed: losing a single share makes recovery impossible.")
+	}
+	return fmt.Sprintf("Any %d friends can recover together, and up to %d share%s can be lost.", threshold, spare, plural(spare))
+}
@@ -39,8 +39,17 @@ func init() {
 	rootCmd.AddCommand(htmlCmd)
 }
 
+// htmlResult is the JSON output of the html command.
+type htmlResult struct {
+	File string `json:"file"`
+	Size int64  `json:"size"`
+}
+
 func runHTML(cmd *cobra.Command, args []string) error {
 	subcommand := args[0]
+	if htmlOutputFile == "" && isJSON() {
+		return newError(CodeUsage, "the HTML goes to stdout, so --format json needs --output")
+	}
 
 	var content string
 	// Use specific release URL if version is a tag, otherwise use latest
@@ -79,7 +88,7 @@ func runHTML(cmd *cobra.Command, args []string) error {
 		content = html.GenerateMakerHTML(createWASM, version, githubURL)
 
 	default:
-		return fmt.Errorf("unknown subcommand: %s (use 'index', 'create', 'docs', or 'recover')", subcommand)
+		return newError(CodeUsage, "unknown subcommand: %s (use 'index', 'create', 'docs', or 'recover')", subcommand)
 	}
 
 	// Output to file or stdout
@@ -87,9 +96,12 @@ func runHTML(cmd *cobra.Command, args []string) error {
 		if err := os.WriteFile(htmlOutputFile, []byte(content), 0644); err != nil {
 			return fmt.Errorf("writing file: %w", err)
 		}
+		if isJSON() {
+			return printJSON(htmlResult{File: htmlOutputFile, Size: int64(len(content))})
+		}
 		fmt.Fprintf(os.Stderr, "Generated %s (%s)\n", htmlOutputFile, formatSize(int64(len(content))))
 	} else {
-		fmt.Print(content)
+		fmt.Fprint(textOut, content)
 	}
 
 	return nil
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/eljojo/rememory/internal/translations"
+)
+
+// loadFriendsFile reads a CSV, vCard, JSON or YAML friends file and validates every row.
+// Languages are normalized first ("EN", "es-MX" → "en", "es"). If any row is
+// invalid, all problems are reported together with their row numbers.
+func loadFriendsFile(path string) (*project.Import, error) {
+	imp, err := project.ImportFile(path)
+	if err != nil {
+		return nil, &Error{Code: CodeUsage, Err: err}
+	}
+	if len(imp.Friends) == 0 {
+		return nil, newError(CodeUsage, "no friends found in %s", path)
+	}
+
+	imp.Language = normalizeLanguage(imp.Language)
+	if imp.Language != "" && !validLanguage(imp.Language) {
+		return nil, newError(CodeUsage, "unsupported language %q in %s (supported: %s)", imp.Language, path, strings.Join(translations.Languages, ", "))
+	}
+
+	var problems []string
+	for i := range imp.Friends {
+		f := &imp.Friends[i]
+		f.Language = normalizeLanguage(f.Language)
+		if err := validateFriend(f.Friend); err != nil {
+			problems = append(problems, fmt.Sprintf("  row %d: %v", f.Row, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &Error{Code: CodeUsage, Err: fmt.Errorf("%s has %d invalid row%s:\n%s", path, len(problems), plural(len(problems)), strings.Join(problems, "\n"))}
+	}
+	return imp, nil
+}
+
+// normalizeLanguage maps a language tag to a supported code, ignoring case and
+// falling back to the base language ("pt-BR" → "pt"). Unknown tags are
+// returned unchanged so validation can report them.
+func normalizeLanguage(lang string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		return ""
+	}
+	candidates := []string{lang}
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		candidates = append(candidates, lang[:i])
+	}
+	for _, c := range candidates {
+		for _, l := range translations.Languages {
+			if strings.EqualFold(l, c) {
+				return l
+			}
+		}
+	}
+	return lang
+}
+
+// printFriendPreview shows which friends would be created, for --dry-run.
+func printFriendPreview(friends []project.Friend, threshold int) {
+	fmt.Fprintf(textOut, "Friends (%d of %d needed to recover):\n", threshold, len(friends))
+	for i, f := range friends {
+		var details []string
+		if f.Contact != "" {
+			details = append(details, f.Contact)
+		}
+		if f.Language != "" {
+			details = append(details, "language: "+f.Language)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(textOut, "  %d. %s (%s)\n", i+1, f.Name, strings.Join(details, ", "))
+		} else {
+			fmt.Fprintf(textOut, "  %d. %s\n", i+1, f.Name)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var importProjectCmd = &cobra.Command{
+	Use:   "import-project <rememory-project.zip> [directory]",
+	Short: "Adopt bundles made in maker.html as a project",
+	Long: `Import-project turns the project archive maker.html offers next to the
+bundles (rememory-project.zip) into a project directory, so 'status',
+'verify', 'bundle' and the other commands can manage the seal made in the
+browser.
+
+The archive holds project.yml, with the seal's manifest checksum and share
+checksums, and usually MANIFEST.age. The shares only exist inside the
+bundles, as with 'rememory seal --ephemeral': pass bundles to 'rememory
+bundle' to regenerate them. If the archive has no MANIFEST.age, take it
+from any bundle with --manifest.
+
+The directory defaults to one named after the project, and may already
+exist, holding the files you sealed in manifest/, but not a project.yml.
+
+Example:
+  rememory import-project ~/Downloads/rememory-project.zip
+  rememory import-project rememory-project.zip my-recovery --manifest bundle-alice.zip`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runImportProject,
+}
+
+func init() {
+	importProjectCmd.Flags().String("manifest", "", "Take MANIFEST.age from this file: MANIFEST.age, a bundle ZIP or a recover.html")
+	rootCmd.AddCommand(importProjectCmd)
+}
+
+// importProjectResult is the JSON output of the import-project command.
+type importProjectResult struct {
+	Project          string `json:"project"`
+	Path             string `json:"path"`
+	Seal             int    `json:"seal"`
+	Threshold        int    `json:"threshold"`
+	Total            int    `json:"total"`
+	ManifestChecksum string `json:"manifest_checksum"`
+	Manifest         string `json:"manifest,omitempty"` // MANIFEST.age, relative to the project, if it was written
+}
+
+func runImportProject(cmd *cobra.Command, args []string) error {
+	manifestPath, _ := cmd.Flags().GetString("manifest")
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading project archive: %w", err)
+	}
+	p, manifestData, err := bundle.ReadProjectArchive(data)
+	if err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+	sealed := p.CurrentSeal()
+
+	if manifestPath != "" {
+		fileData, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", manifestPath, err)
+		}
+		if manifestData, err = bundle.ExtractManifest(fileData); err != nil {
+			return newError(CodeUsage, "%s: %v", manifestPath, err)
+		}
+		if core.HashBytes(manifestData) != sealed.ManifestChecksum {
+			return newError(CodeUsage, "the MANIFEST.age in %s isn't from this seal", manifestPath)
+		}
+	}
+
+	dirName := core.SanitizeFilename(p.Name)
+	if dirName == "" {
+		dirName = "recovery"
+	}
+	if len(args) > 1 {
+		dirName = args[1]
+	}
+	dir, err := filepath.Abs(dirName)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, project.ProjectFileName)); err == nil {
+		return newError(CodeUsage, "%s already has a %s", dir, project.ProjectFileName)
+	}
+
+	// Same layout as 'rememory init' and 'rememory seal'
+	p.Path = dir
+	if err := os.MkdirAll(p.ManifestPath(), 0755); err != nil {
+		return fmt.Errorf("creating manifest directory: %w", err)
+	}
+	if err := os.MkdirAll(p.OutputPath(), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	result := importProjectResult{
+		Project:          p.Name,
+		Path:             dir,
+		Seal:             p.CurrentSealIndex() + 1,
+		Threshold:        p.Threshold,
+		Total:            len(p.Friends),
+		ManifestChecksum: sealed.ManifestChecksum,
+	}
+	if manifestData != nil {
+		if err := bundle.WriteManifestFile(p.ManifestAgePath(), manifestData); err != nil {
+			return fmt.Errorf("writing encrypted manifest: %w", err)
+		}
+		result.Manifest, _ = filepath.Rel(dir, p.ManifestAgePath())
+	}
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	fmt.Fprintf(textOut, "%s Imported %s into %s\n", green("✓"), p.Name, dir)
+	fmt.Fprintf(textOut, "  Seal #%d: %d of %d friends, sealed %s\n", result.Seal, p.Threshold, len(p.Friends), sealed.At.Format("2006-01-02"))
+	fmt.Fprintf(textOut, "  Manifest Checksum: %s\n", truncateHash(sealed.ManifestChecksum))
+	if result.Manifest != "" {
+		fmt.Fprintf(textOut, "  %s %s (and %s)\n", green("✓"), result.Manifest, core.ParityFileName)
+	} else {
+		fmt.Fprintf(textOut, "  %s the archive has no MANIFEST.age; copy it from any bundle into %s\n", yellow("Warning:"), filepath.Join(dirName, project.OutputDir)+string(filepath.Separator))
+	}
+
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "The shares only exist inside the bundles. Next steps:")
+	fmt.Fprintf(textOut, "  cd %s && rememory status\n", dirName)
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/eljojo/rememory/internal/core"
 	"github.com/eljojo/rememory/internal/project"
 	"github.com/eljojo/rememory/internal/translations"
 	"github.com/spf13/cobra"
@@ -24,7 +25,12 @@ The project will contain:
 
 Example:
   rememory init my-recovery-2026
-  rememory init my-recovery --from ../old-project`,
+  rememory init my-recovery --from ../old-project
+  rememory init my-recovery --friends-file people.csv --dry-run
+
+A friends file can be CSV (name, contact, language columns, with or without
+a header), a vCard export (.vcf), or JSON or YAML (a list of friends, or an
+object with "friends" plus optional "name", "threshold" and "language").`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
@@ -37,6 +43,8 @@ var (
 	initAnonymous bool
 	initShares    int
 	initLanguage  string
+	initFile      string
+	initDryRun    bool
 )
 
 const (
@@ -55,6 +63,11 @@ func init() {
 	initCmd.Flags().BoolVar(&initAnonymous, "anonymous", false, "Anonymous mode (no contact info for shareholders)")
 	initCmd.Flags().IntVar(&initShares, "shares", 0, "Number of shares (for anonymous mode)")
 	initCmd.Flags().StringVar(&initLanguage, "language", "", "Default bundle language (en, es, de, fr, sl)")
+	initCmd.Flags().StringVar(&initFile, "friends-file", "", "Import friends from a CSV, vCard (.vcf), JSON or YAML file")
+	initCmd.Flags().BoolVar(&initDryRun, "dry-run", false, "Show what would be created without writing anything")
+	initCmd.MarkFlagsMutuallyExclusive("friends-file", "friend")
+	initCmd.MarkFlagsMutuallyExclusive("friends-file", "from")
+	initCmd.MarkFlagsMutuallyExclusive("friends-file", "anonymous")
 }
 
 // validLanguage returns true if the given language code is supported.
@@ -73,6 +86,34 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unsupported language %q (supported: %s)", initLanguage, strings.Join(translations.Languages, ", "))
 	}
 
+	// Prompts are hidden in JSON mode, so every answer must come from flags
+	if isJSON() {
+		interactive := len(initFriends) == 0 && initFrom == "" && initFile == "" && !initAnonymous
+		if interactive || (initAnonymous && (initShares == 0 || initThreshold == 0)) {
+			return newError(CodeUsage, "--format json needs --friend, --friends-file, --from, or --anonymous with --shares and --threshold")
+		}
+	}
+
+	if initDryRun && initFile == "" && len(initFriends) == 0 && initFrom == "" {
+		return newError(CodeUsage, "--dry-run needs --friends-file, --friend or --from")
+	}
+
+	// Read the friends file up front, so its settings can fill in missing flags
+	var imported *project.Import
+	if initFile != "" {
+		var err error
+		imported, err = loadFriendsFile(initFile)
+		if err != nil {
+			return err
+		}
+		if initName == "" {
+			initName = imported.Name
+		}
+		if initLanguage == "" {
+			initLanguage = imported.Language
+		}
+	}
+
 	// Determine project directory from args
 	dirName := "recovery"
 	if len(args) > 0 {
@@ -95,7 +136,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("directory already exists: %s", dir)
 	}
 
-	fmt.Printf("Creating new rememory project: %s/\n\n", dirName)
+	if initDryRun {
+		fmt.Fprintf(textOut, "Dry run: would create rememory project %s/\n\n", dirName)
+	} else {
+		fmt.Fprintf(textOut, "Creating new rememory project: %s/\n\n", dirName)
+	}
 
 	var friends []project.Friend
 	var threshold int
@@ -108,7 +153,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 		numShares := initShares
 		if numShares == 0 {
-			fmt.Print("How many shares? [5]: ")
+			fmt.Fprint(textOut, "How many shares? [5]: ")
 			numStr, _ := reader.ReadString('\n')
 			numStr = strings.TrimSpace(numStr)
 			numShares = 5
@@ -127,7 +172,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 			if defaultThreshold < 2 {
 				defaultThreshold = 2
 			}
-			fmt.Printf("How many shares needed to recover? [%d]: ", defaultThreshold)
+			fmt.Fprintf(textOut, "How many shares needed to recover? [%d]: ", defaultThreshold)
 			threshStr, _ := reader.ReadString('\n')
 			threshStr = strings.TrimSpace(threshStr)
 			threshold = defaultThreshold
@@ -150,15 +195,22 @@ func runInit(cmd *cobra.Command, args []string) error {
 			friends[i] = project.Friend{Name: fmt.Sprintf("Share %d", i+1)}
 		}
 
-		fmt.Printf("\nAnonymous mode: %d shares, threshold %d of %d\n\n", numShares, threshold, numShares)
-	} else if len(initFriends) > 0 {
-		// Non-interactive mode: use flags
-		friends, err = parseFriendFlags(initFriends)
-		if err != nil {
-			return err
+		fmt.Fprintf(textOut, "\nAnonymous mode: %d shares, threshold %d of %d\n\n", numShares, threshold, numShares)
+	} else if len(initFriends) > 0 || imported != nil {
+		// Non-interactive mode: use flags or the friends file
+		if imported != nil {
+			friends = imported.FriendList()
+		} else {
+			friends, err = parseFriendFlags(initFriends)
+			if err != nil {
+				return err
+			}
 		}
 
 		threshold = initThreshold
+		if threshold == 0 && imported != nil {
+			threshold = imported.Threshold
+		}
 		if threshold == 0 {
 			threshold = (len(friends) + 1) / 2 // Default to majority
 			if threshold < 2 {
@@ -170,8 +222,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid threshold: must be between 2 and %d", len(friends))
 		}
 
-		fmt.Printf("Friends: %s\n", friendNames(friends))
-		fmt.Printf("Threshold: %d of %d\n\n", threshold, len(friends))
+		if imported != nil {
+			fmt.Fprintf(textOut, "Imported %d friend%s from %s\n", len(friends), plural(len(friends)), initFile)
+		}
+		fmt.Fprintf(textOut, "Friends: %s\n", friendNames(friends))
+		fmt.Fprintf(textOut, "Threshold: %d of %d\n\n", threshold, len(friends))
 	} else if initFrom != "" {
 		fromDir, err := filepath.Abs(initFrom)
 		if err != nil {
@@ -185,15 +240,15 @@ func runInit(cmd *cobra.Command, args []string) error {
 
 		friends = existing.Friends
 		threshold = existing.Threshold
-		fmt.Printf("Copying configuration from: %s\n", initFrom)
-		fmt.Printf("  Friends: %s\n", friendNames(friends))
-		fmt.Printf("  Threshold: %d of %d\n\n", threshold, len(friends))
+		fmt.Fprintf(textOut, "Copying configuration from: %s\n", initFrom)
+		fmt.Fprintf(textOut, "  Friends: %s\n", friendNames(friends))
+		fmt.Fprintf(textOut, "  Threshold: %d of %d\n\n", threshold, len(friends))
 	} else {
 		// Interactive prompts
 		reader := bufio.NewReader(os.Stdin)
 
 		// Number of friends
-		fmt.Print("How many friends will hold shares? [5]: ")
+		fmt.Fprint(textOut, "How many friends will hold shares? [5]: ")
 		numStr, _ := reader.ReadString('\n')
 		numStr = strings.TrimSpace(numStr)
 		numFriends := 5
@@ -210,7 +265,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		if defaultThreshold < 2 {
 			defaultThreshold = 2
 		}
-		fmt.Printf("How many shares needed to recover? [%d]: ", defaultThreshold)
+		fmt.Fprintf(textOut, "How many shares needed to recover? [%d]: ", defaultThreshold)
 		threshStr, _ := reader.ReadString('\n')
 		threshStr = strings.TrimSpace(threshStr)
 		threshold = defaultThreshold
@@ -222,34 +277,45 @@ func runInit(cmd *cobra.Command, args []string) error {
 			threshold = t
 		}
 
-		fmt.Println()
+		fmt.Fprintln(textOut)
 
 		// Collect friend information
 		friends = make([]project.Friend, numFriends)
 		for i := 0; i < numFriends; i++ {
-			fmt.Printf("Friend %d:\n", i+1)
+			fmt.Fprintf(textOut, "Friend %d:\n", i+1)
 
-			fmt.Print("  Name: ")
+			fmt.Fprint(textOut, "  Name: ")
 			nameStr, _ := reader.ReadString('\n')
 			nameStr = strings.TrimSpace(nameStr)
-			if nameStr == "" {
-				return fmt.Errorf("name is required")
-			}
-			if len(nameStr) > MaxNameLength {
-				return fmt.Errorf("name too long (max %d characters)", MaxNameLength)
-			}
 			friends[i].Name = nameStr
 
-			fmt.Print("  Contact info (optional): ")
+			fmt.Fprint(textOut, "  Contact info (optional): ")
 			contactStr, _ := reader.ReadString('\n')
-			contactStr = strings.TrimSpace(contactStr)
-			if len(contactStr) > MaxContactLength {
-				return fmt.Errorf("contact info too long (max %d characters)", MaxContactLength)
+			friends[i].Contact = strings.TrimSpace(contactStr)
+
+			if err := validateFriend(friends[i]); err != nil {
+				return err
 			}
-			friends[i].Contact = contactStr
 
-			fmt.Println()
+			fmt.Fprintln(textOut)
+		}
+	}
+
+	if err := checkShareFilenames(friends); err != nil {
+		return err
+	}
+
+	if initDryRun {
+		if isJSON() {
+			return printJSON(initResult{Path: dir, Name: name, Threshold: threshold, Anonymous: anonymous, Language: initLanguage, Friends: friends, DryRun: true})
+		}
+		printFriendPreview(friends, threshold)
+		if initLanguage != "" {
+			fmt.Fprintf(textOut, "Language: %s\n", initLanguage)
 		}
+		fmt.Fprintln(textOut)
+		fmt.Fprintln(textOut, "Nothing was written. Run again without --dry-run to create the project.")
+		return nil
 	}
 
 	// Create the project
@@ -276,15 +342,30 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating manifest README: %w", err)
 	}
 
-	fmt.Printf("Created %s/\n", name)
-	fmt.Printf("  - project.yml (edit to update friends)\n")
-	fmt.Printf("  - manifest/README.md (add your secrets here)\n")
-	fmt.Println()
-	fmt.Println("Next: Add files to manifest/, then run `rememory seal`")
+	if isJSON() {
+		return printJSON(initResult{Path: p.Path, Name: p.Name, Threshold: p.Threshold, Anonymous: p.Anonymous, Language: p.Language, Friends: p.Friends})
+	}
+
+	fmt.Fprintf(textOut, "Created %s/\n", name)
+	fmt.Fprintf(textOut, "  - project.yml (edit to update friends)\n")
+	fmt.Fprintf(textOut, "  - manifest/README.md (add your secrets here)\n")
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "Next: Add files to manifest/, then run `rememory seal`")
 
 	return nil
 }
 
+// initResult is the JSON output of the init command.
+type initResult struct {
+	Path      string           `json:"path"`
+	Name      string           `json:"name"`
+	Threshold int              `json:"threshold"`
+	Anonymous bool             `json:"anonymous"`
+	Language  string           `json:"language,omitempty"`
+	Friends   []project.Friend `json:"friends"`
+	DryRun    bool             `json:"dry_run,omitempty"`
+}
+
 func friendNames(friends []project.Friend) string {
 	names := make([]string, len(friends))
 	for i, f := range friends {
@@ -306,9 +387,6 @@ func parseFriendFlags(flags []string) ([]project.Friend, error) {
 		}
 		if len(parts) >= 3 {
 			lang = strings.TrimSpace(parts[2])
-			if lang != "" && !validLanguage(lang) {
-				return nil, fmt.Errorf("friend %q: unsupported language %q (supported: %s)", name, lang, strings.Join(translations.Languages, ", "))
-			}
 		}
 
 		friends[i] = project.Friend{
@@ -317,15 +395,40 @@ func parseFriendFlags(flags []string) ([]project.Friend, error) {
 			Language: lang,
 		}
 
-		if friends[i].Name == "" {
-			return nil, fmt.Errorf("friend name cannot be empty")
-		}
-		if len(friends[i].Name) > MaxNameLength {
-			return nil, fmt.Errorf("friend name too long (max %d characters)", MaxNameLength)
-		}
-		if len(friends[i].Contact) > MaxContactLength {
-			return nil, fmt.Errorf("friend contact too long (max %d characters)", MaxContactLength)
+		if err := validateFriend(friends[i]); err != nil {
+			return nil, err
 		}
 	}
 	return friends, nil
 }
+
+// validateFriend checks a friend's name, contact and language.
+func validateFriend(f project.Friend) error {
+	if f.Name == "" {
+		return fmt.Errorf("friend name cannot be empty")
+	}
+	if len(f.Name) > MaxNameLength {
+		return fmt.Errorf("friend name too long (max %d characters)", MaxNameLength)
+	}
+	if len(f.Contact) > MaxContactLength {
+		return fmt.Errorf("friend contact too long (max %d characters)", MaxContactLength)
+	}
+	if f.Language != "" && !validLanguage(f.Language) {
+		return fmt.Errorf("friend %q: unsupported language %q (supported: %s)", f.Name, f.Language, strings.Join(translations.Languages, ", "))
+	}
+	return nil
+}
+
+// checkShareFilenames makes sure no two friends end up with the same share
+// and bundle filenames, e.g. "José" and "jose" both map to SHARE-jose.txt.
+func checkShareFilenames(friends []project.Friend) error {
+	seen := make(map[string]string)
+	for _, f := range friends {
+		key := core.SanitizeFilename(f.Name)
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("friends %q and %q would both get SHARE-%s.txt; use names that differ in more than accents, case or punctuation", other, f.Name, key)
+		}
+		seen[key] = f.Name
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <file>",
+	Short: "Show what's inside a share, bundle, README.txt, recover.html or MANIFEST.age",
+	Long: `Inspect detects the type of a ReMemory file and prints its metadata,
+without attempting recovery.
+
+Supported inputs:
+  - SHARE-*.txt (PEM share block)
+  - README.txt from a bundle
+  - bundle-*.zip
+  - recover.html (personalized or generic)
+  - MANIFEST.age
+  - a compact share (RM2:...) or recovery URL from a QR code, given directly
+    as the argument or in a file
+
+Use "-" to read from standard input.
+
+The share's secret data is redacted unless --show-secret is passed.
+
+Inside a project directory, inspect also tells which seal the file comes
+from, and whether that seal has since been replaced.
+
+Example:
+  rememory inspect bundle-alice.zip
+  rememory inspect SHARE-alice.txt --show-secret
+  rememory inspect "RM2:1:5:3:...:ab12"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.Flags().Bool("show-secret", false, "Reveal the share's recovery words and data")
+}
+
+// inspectShare is the JSON view of a share. Secret fields are only set with --show-secret.
+type inspectShare struct {
+	Version    int        `json:"version"`
+	Index      int        `json:"index"`
+	Total      int        `json:"total"`
+	Threshold  int        `json:"threshold"`
+	Holder     string     `json:"holder,omitempty"`
+	Created    *time.Time `json:"created,omitempty"`
+	Checksum   string     `json:"checksum"`
+	ChecksumOK bool       `json:"checksum_ok"`
+	Compact    string     `json:"compact,omitempty"`
+	Words      []string   `json:"words,omitempty"`
+}
+
+// inspectSeal identifies the seal of the current project an inspected file belongs to.
+type inspectSeal struct {
+	Seal         int        `json:"seal"`
+	Of           int        `json:"of"`
+	At           time.Time  `json:"at"`
+	Current      bool       `json:"current"`
+	SupersededAt *time.Time `json:"superseded_at,omitempty"`
+}
+
+// inspectResult is the JSON output of the inspect command.
+type inspectResult struct {
+	File string `json:"file"`
+	*bundle.Inspection
+	Share *inspectShare `json:"share,omitempty"`
+	// Seal is set when run inside a sealed project and the file matches one of its seals
+	Seal *inspectSeal `json:"seal,omitempty"`
+	// InProject is true when run inside a sealed project
+	InProject bool `json:"in_project,omitempty"`
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	showSecret, _ := cmd.Flags().GetBool("show-secret")
+
+	data, err := readInspectInput(args[0])
+	if err != nil {
+		return err
+	}
+
+	ins, err := bundle.Inspect(data)
+	if err != nil {
+		return &Error{Code: CodeInvalidShare, Err: fmt.Errorf("%s: %w", args[0], err)}
+	}
+
+	result := inspectResult{File: args[0], Inspection: ins}
+	if ins.Share != nil {
+		s := ins.Share
+		result.Share = &inspectShare{
+			Version:    s.Version,
+			Index:      s.Index,
+			Total:      s.Total,
+			Threshold:  s.Threshold,
+			Holder:     s.Holder,
+			Checksum:   s.Checksum,
+			ChecksumOK: ins.ShareError == "",
+		}
+		if !s.Created.IsZero() {
+			result.Share.Created = &s.Created
+		}
+		if showSecret {
+			result.Share.Compact = s.CompactEncode()
+			result.Share.Words, _ = s.Words()
+		}
+	}
+
+	// Inside a project, find which seal the file came from (drills aren't seals)
+	practice := (ins.Readme != nil && ins.Readme.Practice) || (ins.HTML != nil && ins.HTML.Practice)
+	if p, err := loadProject(); err == nil && len(p.Seals) > 0 && !practice {
+		result.InProject = true
+		result.Seal = findInspectedSeal(p, data, ins)
+	}
+
+	if isJSON() {
+		return printJSON(result)
+	}
+
+	printInspection(result, showSecret)
+	return nil
+}
+
+// readInspectInput reads a file, standard input ("-"), or — when the argument
+// isn't a file — treats the argument itself as a compact share or recovery URL.
+func readInspectInput(arg string) ([]byte, error) {
+	if arg == "-" {
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, core.MaxTotalSize))
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(arg)
+	if err == nil {
+		return data, nil
+	}
+	if os.IsNotExist(err) && (strings.HasPrefix(arg, "RM") || strings.Contains(arg, "#share=")) {
+		return []byte(arg), nil
+	}
+	return nil, fmt.Errorf("reading %s: %w", arg, err)
+}
+
+// readShareFrom reads the share in a bundle, README.txt, share file or
+// personalized recover.html. Shares from practice bundles are rejected.
+func readShareFrom(path string) (*core.Share, *bundle.Inspection, error) {
+	data, err := readInspectInput(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	ins, err := bundle.Inspect(data)
+	if err != nil || ins.Share == nil {
+		return nil, nil, newError(CodeInvalidShare, "%s: no share found", path)
+	}
+	if ins.ShareError != "" {
+		return nil, nil, newError(CodeInvalidShare, "%s: %s", path, ins.ShareError)
+	}
+	if (ins.Readme != nil && ins.Readme.Practice) || (ins.HTML != nil && ins.HTML.Practice) {
+		return nil, nil, newError(CodeInvalidShare, "%s is a practice bundle from a recovery drill; use the real bundle", path)
+	}
+	return ins.Share, ins, nil
+}
+
+// findInspectedSeal matches the checksums found in an inspected file against
+// the project's seal history.
+func findInspectedSeal(p *project.Project, data []byte, ins *bundle.Inspection) *inspectSeal {
+	checksums := []string{core.HashBytes(data)}
+	if ins.Share != nil {
+		checksums = append(checksums, ins.Share.Checksum)
+	}
+	if ins.Manifest != nil {
+		checksums = append(checksums, ins.Manifest.Checksum)
+	}
+	if ins.Readme != nil {
+		checksums = append(checksums, ins.Readme.Metadata["checksum-manifest"])
+	}
+
+	i := p.FindSeal(checksums...)
+	if i < 0 {
+		return nil
+	}
+	return &inspectSeal{
+		Seal:         i + 1,
+		Of:           len(p.Seals),
+		At:           p.Seals[i].At,
+		Current:      p.Seals[i].Current,
+		SupersededAt: p.SupersededAt(i),
+	}
+}
+
+var inspectKindNames = map[bundle.Kind]string{
+	bundle.KindShare:       "share",
+	bundle.KindCompact:     "compact share",
+	bundle.KindReadme:      "README.txt",
+	bundle.KindBundle:      "bundle ZIP",
+	bundle.KindRecoverHTML: "recover.html",
+	bundle.KindManifest:    "MANIFEST.age",
+}
+
+func printInspection(r inspectResult, showSecret bool) {
+	fmt.Fprintf(textOut, "File: %s\n", r.File)
+	fmt.Fprintf(textOut, "Type: %s\n", inspectKindNames[r.Kind])
+	if (r.Readme != nil && r.Readme.Practice) || (r.HTML != nil && r.HTML.Practice) {
+		fmt.Fprintf(textOut, "      %s\n", yellow("PRACTICE (from a recovery drill, not a real bundle)"))
+	}
+	if s := r.Seal; s != nil {
+		if s.SupersededAt != nil {
+			fmt.Fprintf(textOut, "Seal: %s\n", yellow(fmt.Sprintf("#%d of %d, sealed %s, superseded on %s", s.Seal, s.Of, s.At.Format("2006-01-02"), s.SupersededAt.Format("2006-01-02"))))
+		} else {
+			fmt.Fprintf(textOut, "Seal: #%d of %d, sealed %s %s\n", s.Seal, s.Of, s.At.Format("2006-01-02"), green("(current)"))
+		}
+	} else if r.InProject {
+		fmt.Fprintln(textOut, "Seal: not from this project")
+	}
+
+	if s := r.Share; s != nil {
+		fmt.Fprintln(textOut, "\nShare:")
+		fmt.Fprintf(textOut, "  Version:    %d\n", s.Version)
+		fmt.Fprintf(textOut, "  Index:      %d of %d (%d needed to recover)\n", s.Index, s.Total, s.Threshold)
+		if s.Holder != "" {
+			fmt.Fprintf(textOut, "  Holder:     %s\n", s.Holder)
+		}
+		if s.Created != nil {
+			fmt.Fprintf(textOut, "  Created:    %s\n", s.Created.Format("2006-01-02 15:04 UTC"))
+		}
+		if s.ChecksumOK {
+			fmt.Fprintf(textOut, "  Checksum:   %s %s\n", truncateHash(s.Checksum), green("✓ valid"))
+		} else {
+			fmt.Fprintf(textOut, "  Checksum:   %s %s\n", truncateHash(s.Checksum), red("✗ "+r.ShareError))
+		}
+		if showSecret {
+			if len(s.Words) > 0 {
+				fmt.Fprintf(textOut, "  Words:      %s\n", strings.Join(s.Words, " "))
+			}
+			fmt.Fprintf(textOut, "  Compact:    %s\n", s.Compact)
+		} else {
+			fmt.Fprintf(textOut, "  Secret:     [redacted, use --show-secret to reveal]\n")
+		}
+	}
+
+	if rd := r.Readme; rd != nil {
+		fmt.Fprintln(textOut, "\nREADME:")
+		if rd.Project != "" {
+			fmt.Fprintf(textOut, "  Project:    %s\n", rd.Project)
+		}
+		fmt.Fprintf(textOut, "  Language:   %s\n", rd.Language)
+		if rd.Version != "" {
+			fmt.Fprintf(textOut, "  ReMemory:   %s\n", rd.Version)
+		}
+		if rd.Created != "" {
+			fmt.Fprintf(textOut, "  Sealed:     %s\n", rd.Created)
+		}
+		if len(rd.OtherHolders) > 0 {
+			fmt.Fprintf(textOut, "  Others:     %s\n", strings.Join(rd.OtherHolders, ", "))
+		}
+		if v := rd.Metadata["checksum-manifest"]; v != "" {
+			fmt.Fprintf(textOut, "  Manifest:   %s\n", truncateHash(v))
+		}
+		if v := rd.Metadata["checksum-recover-html"]; v != "" {
+			fmt.Fprintf(textOut, "  HTML:       %s\n", truncateHash(v))
+		}
+	}
+
+	if h := r.HTML; h != nil {
+		fmt.Fprintln(textOut, "\nrecover.html:")
+		if h.Version != "" {
+			fmt.Fprintf(textOut, "  ReMemory:   %s\n", h.Version)
+		}
+		fmt.Fprintf(textOut, "  Size:       %s\n", formatSize(int64(h.Size)))
+		fmt.Fprintf(textOut, "  Checksum:   %s\n", truncateHash(h.Checksum))
+		if h.Provenance != nil {
+			fmt.Fprintf(textOut, "  Origin:     %s\n", describeProvenance(h.Provenance))
+		}
+		if h.Personalized {
+			fmt.Fprintf(textOut, "  For:        %s (%d of %d needed)\n", h.Holder, h.Threshold, h.Total)
+			if h.Language != "" {
+				fmt.Fprintf(textOut, "  Language:   %s\n", h.Language)
+			}
+			if len(h.OtherFriends) > 0 {
+				names := make([]string, len(h.OtherFriends))
+				for i, f := range h.OtherFriends {
+					names[i] = f.Name
+				}
+				fmt.Fprintf(textOut, "  Others:     %s\n", strings.Join(names, ", "))
+			}
+		} else {
+			fmt.Fprintln(textOut, "  Generic (not personalized)")
+		}
+		if h.ManifestSize > 0 {
+			fmt.Fprintf(textOut, "  Manifest:   embedded (%s)\n", formatSize(int64(h.ManifestSize)))
+		} else {
+			fmt.Fprintln(textOut, "  Manifest:   not embedded")
+		}
+	}
+
+	if m := r.Manifest; m != nil {
+		fmt.Fprintln(textOut, "\nMANIFEST.age:")
+		source := ""
+		if m.Embedded {
+			source = " (embedded in recover.html)"
+		}
+		fmt.Fprintf(textOut, "  Size:       %s%s\n", formatSize(int64(m.Size)), source)
+		fmt.Fprintf(textOut, "  Checksum:   %s\n", truncateHash(m.Checksum))
+		encryption := strings.Join(m.Recipients, ", ")
+		if m.WorkFactor > 0 {
+			encryption = fmt.Sprintf("passphrase (scrypt, work factor %d)", m.WorkFactor)
+		}
+		fmt.Fprintf(textOut, "  Encryption: %s\n", encryption)
+	}
+
+	if r.Kind == bundle.KindBundle {
+		fmt.Fprintln(textOut, "\nFiles:")
+		for _, f := range r.Files {
+			fmt.Fprintf(textOut, "  %-20s %s\n", f.Name, formatSize(f.Size))
+		}
+		fmt.Fprintln(textOut)
+		if r.Verified {
+			fmt.Fprintf(textOut, "Checksums: %s\n", green("✓ all files match README.txt"))
+		} else {
+			fmt.Fprintf(textOut, "Checksums: %s\n", red("✗ "+r.VerifyError))
+		}
+		if r.ManifestRepaired > 0 {
+			fmt.Fprintf(textOut, "Repaired:  %s\n", describeRepair(r.ManifestRepaired))
+		}
+		if r.SignedBy != "" {
+			fmt.Fprintf(textOut, "Signed by: %s\n", r.SignedBy)
+		}
+	}
+}
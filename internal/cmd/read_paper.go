@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/pkg/rememory"
+	"github.com/spf13/cobra"
+)
+
+var readPaperCmd = &cobra.Command{
+	Use:   "read-paper [lines.txt...]",
+	Short: "Rebuild MANIFEST.age from the lines printed in README.pdf",
+	Long: `Read-paper puts MANIFEST.age back together from the pages a bundle made
+with --paper-manifest prints in README.pdf, for a friend who only kept the
+paper.
+
+Give it text files holding the lines, or pipe them in: typed by hand, or
+the text of the QR codes read by a scanner app or zbarimg. Files can hold
+any of the lines, in any order, as long as one of them has the header line
+(MANIFEST.AGE ...). Every line's check code is verified, so a typo is
+pointed out by its line number, and the rebuilt file is checked against the
+header's checksum.
+
+Example:
+  rememory read-paper page1.txt page2.txt
+  zbarimg --raw -q scans/*.png | rememory read-paper
+  rememory recover SHARE-*.txt --manifest MANIFEST.age`,
+	RunE: runReadPaper,
+}
+
+func init() {
+	readPaperCmd.Flags().StringP("output", "o", "MANIFEST.age", "Where to write MANIFEST.age")
+	readPaperCmd.Flags().Bool("force", false, "Overwrite the output file if it exists")
+	rootCmd.AddCommand(readPaperCmd)
+}
+
+// readPaperResult is the JSON output of the read-paper command.
+type readPaperResult struct {
+	OK       bool     `json:"ok"`
+	File     string   `json:"file,omitempty"`
+	Checksum string   `json:"checksum,omitempty"`
+	Size     int      `json:"size,omitempty"`
+	Lines    int      `json:"lines"`
+	Missing  []int    `json:"missing,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+func runReadPaper(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	force, _ := cmd.Flags().GetBool("force")
+	if _, err := os.Stat(output); err == nil && !force {
+		return newError(CodeUsage, "%s already exists; use --force to overwrite it", output)
+	}
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	r := rememory.NewPaperReader()
+	var result readPaperResult
+	for _, arg := range args {
+		var data []byte
+		var err error
+		if arg == "-" {
+			data, err = io.ReadAll(io.LimitReader(os.Stdin, 64*core.MaxPaperManifestSize))
+		} else {
+			data, err = os.ReadFile(arg)
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", arg, err)
+		}
+		if err := r.Add(string(data)); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			if !isJSON() {
+				fmt.Fprintf(textOut, "%s %v\n", yellow("Warning:"), err)
+			}
+		}
+	}
+
+	result.Lines = r.Lines()
+	result.Missing = r.Missing()
+	manifestData, err := r.Read()
+	if err == nil {
+		if err = os.WriteFile(output, manifestData, 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", output, err)
+		}
+		result.OK = true
+		result.File = output
+		result.Checksum = core.HashBytes(manifestData)
+		result.Size = len(manifestData)
+	}
+
+	if isJSON() {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else if err == nil {
+		fmt.Fprintf(textOut, "%s Read %d line%s\n", green("✓"), result.Lines, plural(result.Lines))
+		fmt.Fprintf(textOut, "%s Wrote %s (%s, %s)\n", green("✓"), output, formatSize(int64(result.Size)), truncateHash(result.Checksum))
+	}
+	if err != nil {
+		return &Error{Code: CodeVerificationFailed, Err: err, Reported: isJSON()}
+	}
+	return nil
+}
@@ -3,14 +3,15 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/eljojo/rememory/internal/core"
-	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/bundle"
 	"github.com/eljojo/rememory/internal/manifest"
+	"github.com/eljojo/rememory/pkg/rememory"
 	"github.com/spf13/cobra"
 )
 
@@ -22,8 +23,15 @@ var recoverCmd = &cobra.Command{
 This command can be run from anywhere (doesn't need a project directory).
 You need at least the threshold number of shares to recover.
 
+Use --list to see what the manifest contains without writing anything to disk,
+--include/--exclude to recover only some files, and --stdout to print a single
+file (useful on a shared machine where secrets should not be left behind).
+
 Example:
-  rememory recover SHARE-alice.txt SHARE-bob.txt SHARE-carol.txt -m MANIFEST.age`,
+  rememory recover SHARE-alice.txt SHARE-bob.txt SHARE-carol.txt -m MANIFEST.age
+  rememory recover SHARE-*.txt --list
+  rememory recover SHARE-*.txt --include '*.md' --exclude 'photos'
+  rememory recover SHARE-*.txt --stdout instructions.md`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runRecover,
 }
@@ -32,6 +40,10 @@ var (
 	recoverManifest   string
 	recoverOutput     string
 	recoverPassphrase bool
+	recoverList       bool
+	recoverInclude    []string
+	recoverExclude    []string
+	recoverStdout     string
 )
 
 func init() {
@@ -39,84 +51,67 @@ func init() {
 	recoverCmd.Flags().StringVarP(&recoverManifest, "manifest", "m", "", "Path to MANIFEST.age file")
 	recoverCmd.Flags().StringVarP(&recoverOutput, "output", "o", "", "Output directory (default: recovered-TIMESTAMP)")
 	recoverCmd.Flags().BoolVar(&recoverPassphrase, "passphrase-only", false, "Only output the passphrase, don't decrypt")
+	recoverCmd.Flags().BoolVar(&recoverList, "list", false, "List the manifest contents without extracting anything")
+	recoverCmd.Flags().StringArrayVar(&recoverInclude, "include", nil, "Only recover files matching this glob pattern (repeatable)")
+	recoverCmd.Flags().StringArrayVar(&recoverExclude, "exclude", nil, "Skip files matching this glob pattern (repeatable)")
+	recoverCmd.Flags().StringVar(&recoverStdout, "stdout", "", "Write a single file from the manifest to stdout instead of extracting")
+	recoverCmd.MarkFlagsMutuallyExclusive("list", "stdout", "passphrase-only")
+	recoverCmd.MarkFlagsMutuallyExclusive("output", "list")
+	recoverCmd.MarkFlagsMutuallyExclusive("output", "stdout")
+	// --stdout names the one file to write; a filter wouldn't apply to it
+	recoverCmd.MarkFlagsMutuallyExclusive("include", "stdout")
+	recoverCmd.MarkFlagsMutuallyExclusive("exclude", "stdout")
 }
 
 func runRecover(cmd *cobra.Command, args []string) error {
+	filter := manifest.Filter{Include: recoverInclude, Exclude: recoverExclude}
+	if err := filter.Validate(); err != nil {
+		return &Error{Code: CodeUsage, Err: err}
+	}
+	if recoverStdout != "" && isJSON() {
+		return newError(CodeUsage, "--stdout cannot be combined with --format json")
+	}
+
+	// When streaming a file to stdout, progress messages go to stderr
+	out := textOut
+	if recoverStdout != "" {
+		out = os.Stderr
+	}
+
 	// Parse all share files
-	fmt.Printf("Reading %d share files...\n", len(args))
+	fmt.Fprintf(out, "Reading %d share files...\n", len(args))
 
-	shares := make([]*core.Share, len(args))
+	shares := make([]*rememory.Share, len(args))
 	for i, path := range args {
 		content, err := os.ReadFile(path)
 		if err != nil {
 			return fmt.Errorf("reading share %s: %w", path, err)
 		}
 
-		share, err := core.ParseShare(content)
+		share, err := rememory.ParseShare(content)
 		if err != nil {
-			return fmt.Errorf("parsing share %s: %w", path, err)
-		}
-
-		// Verify checksum
-		if err := share.Verify(); err != nil {
-			return fmt.Errorf("share %s: %w", path, err)
+			return newError(CodeInvalidShare, "share %s: %w", path, err)
 		}
-
 		shares[i] = share
 	}
 
-	// Validate shares are compatible
-	if len(shares) == 0 {
-		return fmt.Errorf("no shares provided")
-	}
-
-	first := shares[0]
-	for i, share := range shares[1:] {
-		if share.Version != first.Version {
-			return fmt.Errorf("share %d has different version (v%d vs v%d) — all shares must be from the same bundle", i+2, share.Version, first.Version)
-		}
-		if share.Total != first.Total {
-			return fmt.Errorf("share %d has different total (%d vs %d)", i+2, share.Total, first.Total)
-		}
-		if share.Threshold != first.Threshold {
-			return fmt.Errorf("share %d has different threshold (%d vs %d)", i+2, share.Threshold, first.Threshold)
-		}
-	}
-
-	// Check we have enough shares
-	if len(shares) < first.Threshold {
-		return fmt.Errorf("need at least %d shares to recover (you provided %d)", first.Threshold, len(shares))
-	}
-
-	// Check for duplicate indices
-	seen := make(map[int]bool)
-	for _, share := range shares {
-		if seen[share.Index] {
-			return fmt.Errorf("duplicate share index %d", share.Index)
-		}
-		seen[share.Index] = true
-	}
-
-	fmt.Printf("Combining %d shares...\n", len(shares))
-
-	// Extract raw share data
-	shareData := make([][]byte, len(shares))
-	for i, share := range shares {
-		shareData[i] = share.Data
-	}
-
-	// Reconstruct passphrase
-	recovered, err := core.Combine(shareData)
+	fmt.Fprintf(out, "Combining %d shares...\n", len(shares))
+	passphrase, err := rememory.Combine(shares)
 	if err != nil {
-		return fmt.Errorf("combining shares: %w", err)
+		return err
 	}
-
-	passphrase := core.RecoverPassphrase(recovered, first.Version)
+	defer passphrase.Wipe()
 
 	if recoverPassphrase {
-		fmt.Println()
-		fmt.Println("Recovered passphrase:")
-		fmt.Println(passphrase)
+		if isJSON() {
+			return printJSON(struct {
+				Passphrase string `json:"passphrase"`
+			}{string(passphrase.Bytes())})
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Recovered passphrase:")
+		out.Write(passphrase.Bytes())
+		fmt.Fprintln(out)
 		return nil
 	}
 
@@ -133,30 +128,47 @@ func runRecover(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Println("Decrypting manifest...")
+	fmt.Fprintln(out, "Decrypting manifest...")
 
-	// Read manifest data — either directly from .age file or extracted from .html
+	// Read manifest data — either directly from .age file or extracted from .html,
+	// repaired with its parity data if it's damaged
 	var encryptedData []byte
+	var repaired int
 	if strings.HasSuffix(strings.ToLower(manifestPath), ".html") || strings.HasSuffix(strings.ToLower(manifestPath), ".htm") {
 		htmlContent, err := os.ReadFile(manifestPath)
 		if err != nil {
 			return fmt.Errorf("reading %s: %w", manifestPath, err)
 		}
-		encryptedData, err = html.ExtractManifestFromHTML(htmlContent)
+		encryptedData, repaired, err = rememory.RepairManifest(htmlContent)
 		if err != nil {
 			return fmt.Errorf("extracting manifest from %s: %w", manifestPath, err)
 		}
-		fmt.Printf("Extracted manifest from %s\n", manifestPath)
+		fmt.Fprintf(out, "Extracted manifest from %s\n", manifestPath)
 	} else {
-		encryptedData, err = os.ReadFile(manifestPath)
+		encryptedData, repaired, err = bundle.ReadManifestFile(manifestPath)
 		if err != nil {
 			return fmt.Errorf("reading manifest: %w", err)
 		}
 	}
+	if repaired > 0 {
+		fmt.Fprintf(out, "Repaired %d damaged block%s of MANIFEST.age from its parity data\n", repaired, plural(repaired))
+	}
 
 	var decryptedBuf bytes.Buffer
-	if err := core.Decrypt(&decryptedBuf, bytes.NewReader(encryptedData), passphrase); err != nil {
-		return fmt.Errorf("decryption failed (shares may be corrupted or from different operation): %w", err)
+	if err := rememory.Decrypt(&decryptedBuf, bytes.NewReader(encryptedData), passphrase); err != nil {
+		return fmt.Errorf("%w (shares may be corrupted or from a different seal)", err)
+	}
+	passphrase.Wipe()
+
+	if recoverList {
+		return listRecovered(out, &decryptedBuf, filter)
+	}
+
+	if recoverStdout != "" {
+		if _, err := manifest.ExtractFile(&decryptedBuf, recoverStdout, cmd.OutOrStdout()); err != nil {
+			return fmt.Errorf("extracting %s: %w", recoverStdout, err)
+		}
+		return nil
 	}
 
 	// Determine output directory
@@ -166,19 +178,30 @@ func runRecover(cmd *cobra.Command, args []string) error {
 	}
 
 	// Extract archive
-	extractResult, err := manifest.Extract(&decryptedBuf, outputDir)
+	extractResult, err := manifest.ExtractFiltered(&decryptedBuf, outputDir, filter)
 	if err != nil {
 		return fmt.Errorf("extracting manifest: %w", err)
 	}
 
 	// Warn about any skipped files (symlinks, etc.)
 	for _, warning := range extractResult.Warnings {
-		fmt.Printf("  Warning: %s\n", warning)
+		fmt.Fprintf(out, "  Warning: %s\n", warning)
+	}
+
+	if extractResult.Files == 0 && !filter.IsEmpty() {
+		return fmt.Errorf("no files in the manifest matched --include/--exclude")
 	}
 
 	// List recovered files
-	fmt.Println()
-	fmt.Printf("Recovered to: %s/\n", extractResult.Path)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Recovered to: %s/\n", extractResult.Path)
+
+	result := recoverResult{
+		OutputDir:      extractResult.Path,
+		Files:          []string{},
+		Warnings:       append([]string{}, extractResult.Warnings...),
+		RepairedBlocks: repaired,
+	}
 
 	err = filepath.Walk(extractResult.Path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -189,9 +212,10 @@ func runRecover(cmd *cobra.Command, args []string) error {
 		}
 		relPath, _ := filepath.Rel(extractResult.Path, path)
 		if info.IsDir() {
-			fmt.Printf("  %s/\n", relPath)
+			fmt.Fprintf(out, "  %s/\n", relPath)
 		} else {
-			fmt.Printf("  %s\n", relPath)
+			fmt.Fprintf(out, "  %s\n", relPath)
+			result.Files = append(result.Files, filepath.ToSlash(relPath))
 		}
 		return nil
 	})
@@ -199,5 +223,54 @@ func runRecover(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing recovered files: %w", err)
 	}
 
+	if isJSON() {
+		return printJSON(result)
+	}
+	return nil
+}
+
+// recoverResult is the JSON output of the recover command.
+type recoverResult struct {
+	OutputDir string   `json:"output_dir"`
+	Files     []string `json:"files"`
+	Warnings  []string `json:"warnings"`
+	// RepairedBlocks counts the damaged blocks of MANIFEST.age that were
+	// rebuilt from its parity data
+	RepairedBlocks int `json:"repaired_blocks,omitempty"`
+}
+
+// listRecovered prints the manifest tree with sizes and modification times.
+func listRecovered(w io.Writer, r io.Reader, filter manifest.Filter) error {
+	entries, err := manifest.List(r, filter)
+	if err != nil {
+		return fmt.Errorf("listing manifest: %w", err)
+	}
+
+	if isJSON() {
+		if entries == nil {
+			entries = []manifest.Entry{}
+		}
+		return printJSON(struct {
+			Entries []manifest.Entry `json:"entries"`
+		}{entries})
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Manifest contents:")
+
+	var fileCount int
+	var totalSize int64
+	for _, e := range entries {
+		if e.IsDir {
+			fmt.Fprintf(w, "  %9s  %s  %s/\n", "-", e.ModTime.Local().Format("2006-01-02 15:04"), e.Name)
+			continue
+		}
+		fmt.Fprintf(w, "  %9s  %s  %s\n", formatSize(e.Size), e.ModTime.Local().Format("2006-01-02 15:04"), e.Name)
+		fileCount++
+		totalSize += e.Size
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%d file%s, %s (nothing was written to disk)\n", fileCount, plural(fileCount), formatSize(totalSize))
 	return nil
 }
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/eljojo/rememory/pkg/rememory"
+	"github.com/spf13/cobra"
+)
+
+var reshareCmd = &cobra.Command{
+	Use:   "reshare <bundle>... --threshold N --friends FILE",
+	Short: "Re-split the passphrase for a new group, using enough current shares",
+	Long: `Reshare lets enough share holders change the threshold or the group of
+holders without the owner, and without re-encrypting anything.
+
+Given enough shares from the current seal (bundles, README.txt or share
+files), the passphrase is reconstructed in memory and split again for the
+friends in the new friends file (CSV, vCard, JSON or YAML, e.g. a
+project.yml). New share files and bundles are created with the same
+MANIFEST.age, along with a transcript of who took part, signed with a key
+derived from the passphrase.
+
+Run inside the sealed project, the reshare is recorded as a new seal. When
+the project isn't available (say the owner is incapacitated), pass --dir to
+create a new project directory holding the new shares, bundles and
+transcript.
+
+The passphrase doesn't change, so the old bundles still work together with
+the old threshold. Ask the old holders to destroy theirs once the new
+bundles are handed out.
+
+Example:
+  rememory reshare bundle-alice.zip bundle-bob.zip bundle-camila.zip \
+    --threshold 2 --friends new.yml --dir ../reshared`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReshare,
+}
+
+func init() {
+	reshareCmd.Flags().Int("threshold", 0, "Number of new shares needed to recover")
+	reshareCmd.Flags().String("friends", "", "The new share holders: a CSV, vCard, JSON or YAML file")
+	reshareCmd.Flags().String("dir", "", "Create a new project here instead of resealing the current one")
+	reshareCmd.Flags().String("name", "", "Name of the new project (default: the name in the bundles)")
+	reshareCmd.Flags().StringP("manifest", "m", "", "MANIFEST.age or recover.html, if none of the bundles contains it")
+	reshareCmd.Flags().String("recovery-url", core.DefaultRecoveryURL, "Base URL for QR code in PDF")
+	reshareCmd.Flags().Bool("no-embed-manifest", false, "Do not embed MANIFEST.age in recover.html (it is embedded by default when 5 MB or less)")
+	reshareCmd.Flags().Bool("paper-manifest", false, "Also print MANIFEST.age in README.pdf, as QR codes and typed lines (8 KB or less)")
+	reshareCmd.MarkFlagRequired("threshold")
+	reshareCmd.MarkFlagRequired("friends")
+	rootCmd.AddCommand(reshareCmd)
+}
+
+// reshareResult is the JSON output of the reshare command.
+type reshareResult struct {
+	Project        string                 `json:"project"`
+	Path           string                 `json:"path"`
+	Seal           int                    `json:"seal"`
+	Transcript     core.ReshareTranscript `json:"transcript"`
+	TranscriptFile string                 `json:"transcript_file"`
+	Shares         []project.ShareInfo    `json:"shares"`
+	Bundles        []bundleFile           `json:"bundles"`
+}
+
+func runReshare(cmd *cobra.Command, args []string) error {
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	friendsFile, _ := cmd.Flags().GetString("friends")
+	dir, _ := cmd.Flags().GetString("dir")
+	name, _ := cmd.Flags().GetString("name")
+	manifestPath, _ := cmd.Flags().GetString("manifest")
+	recoveryURL, _ := cmd.Flags().GetString("recovery-url")
+	noEmbedManifest, _ := cmd.Flags().GetBool("no-embed-manifest")
+	paperManifest, _ := cmd.Flags().GetBool("paper-manifest")
+
+	imp, err := loadFriendsFile(friendsFile)
+	if err != nil {
+		return err
+	}
+
+	// Read the shares, and MANIFEST.age from the first bundle that has one
+	shares := make([]*core.Share, len(args))
+	var manifestData []byte
+	projectName := ""
+	for i, path := range args {
+		share, ins, err := readShareFrom(path)
+		if err != nil {
+			return err
+		}
+		shares[i] = share
+		if ins.Readme != nil && projectName == "" {
+			projectName = ins.Readme.Project
+		}
+		if manifestData == nil && ins.Manifest != nil {
+			if data, err := os.ReadFile(path); err == nil {
+				manifestData, _ = bundle.ExtractManifest(data)
+			}
+		}
+	}
+
+	fmt.Fprintf(textOut, "Combining %d shares...\n", len(shares))
+	passphrase, err := rememory.Combine(shares)
+	if err != nil {
+		return err
+	}
+	defer passphrase.Wipe()
+
+	// In the current project the shares must belong to the current seal;
+	// for a new project they must decrypt the MANIFEST.age that was found.
+	inProject := dir == ""
+	var p *project.Project
+	var previous *project.Sealed
+	if inProject {
+		if p, err = loadSealedProject(); err != nil {
+			return err
+		}
+		previous = p.CurrentSeal()
+		if !core.VerifyHash(core.HashBytes(passphrase.Bytes()), previous.VerificationHash) {
+			return newError(CodeVerificationFailed, "these shares don't reconstruct the passphrase of the current seal (#%d)", p.CurrentSealIndex()+1)
+		}
+		if manifestData, _, err = bundle.ReadManifestFile(p.ManifestAgePath()); err != nil {
+			return fmt.Errorf("reading manifest: %w", err)
+		}
+	} else {
+		if _, err := os.Stat(filepath.Join(dir, project.ProjectFileName)); err == nil {
+			return newError(CodeUsage, "%s already contains a project", dir)
+		}
+		if manifestPath != "" {
+			content, err := os.ReadFile(manifestPath)
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", manifestPath, err)
+			}
+			if manifestData, err = bundle.ExtractManifest(content); err != nil {
+				return fmt.Errorf("%s: %w", manifestPath, err)
+			}
+		}
+		if manifestData == nil {
+			return newError(CodeUsage, "none of the files contain MANIFEST.age; pass it with --manifest")
+		}
+		fmt.Fprint(textOut, "Checking the passphrase decrypts MANIFEST.age... ")
+		if err := rememory.Decrypt(io.Discard, bytes.NewReader(manifestData), passphrase); err != nil {
+			fmt.Fprintln(textOut, "FAILED")
+			return fmt.Errorf("the shares don't decrypt this MANIFEST.age: %w", err)
+		}
+		fmt.Fprintln(textOut, "OK")
+
+		if name == "" {
+			name = projectName
+		}
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		p = &project.Project{Name: name, Created: time.Now().Format("2006-01-02"), Path: dir}
+	}
+
+	// The new group replaces the old one
+	p.Friends = imp.FriendList()
+	p.Threshold = threshold
+	if imp.Language != "" {
+		p.Language = imp.Language
+	}
+	if err := p.Validate(); err != nil {
+		return &Error{Code: CodeUsage, Err: fmt.Errorf("new group: %w", err)}
+	}
+	if paperManifest && len(manifestData) > core.MaxPaperManifestSize {
+		return newError(CodeUsage, "MANIFEST.age is %s, too large for --paper-manifest (at most %s)", formatSize(int64(len(manifestData))), formatSize(core.MaxPaperManifestSize))
+	}
+
+	wasmBytes := html.GetRecoverWASMBytes()
+	if len(wasmBytes) == 0 {
+		return fmt.Errorf("recover.wasm not embedded - rebuild with 'make build'")
+	}
+
+	fmt.Fprintf(textOut, "Splitting into %d shares (threshold: %d)...\n", len(p.Friends), p.Threshold)
+	friendShares, err := splitPassphrase(passphrase, shares[0].Version, p)
+	if err != nil {
+		return err
+	}
+
+	// Generate bundles first: until every bundle is written, the previous
+	// seal stays current and its files stay in place
+	now := time.Now().UTC()
+	fmt.Fprintf(textOut, "\nGenerating bundles for %d friends...\n", len(p.Friends))
+	cfg := bundle.Config{
+		Version:          version,
+		GitHubReleaseURL: fmt.Sprintf("https://github.com/eljojo/rememory/releases/tag/%s", version),
+		WASMBytes:        wasmBytes,
+		RecoveryURL:      recoveryURL,
+		NoEmbedManifest:  noEmbedManifest,
+		PaperManifest:    paperManifest,
+	}
+	paths, err := bundle.GenerateBundles(p, friendShares, manifestData, filepath.Join(p.OutputPath(), "bundles"), now, cfg)
+	if err != nil {
+		return fmt.Errorf("generating bundles: %w", err)
+	}
+
+	if inProject {
+		if n, err := removePreviousSeal(p, paths); err != nil {
+			return err
+		} else if n > 0 {
+			fmt.Fprintf(textOut, "Removed %d share and bundle file%s from the previous seal\n", n, plural(n))
+		}
+	}
+	// An ephemeral project stays ephemeral: the new shares only go in the bundles
+	ephemeral := previous != nil && previous.Ephemeral
+	if err := os.MkdirAll(p.SharesPath(), 0755); err != nil {
+		return fmt.Errorf("creating output directories: %w", err)
+	}
+	if !inProject {
+		if err := bundle.WriteManifestFile(p.ManifestAgePath(), manifestData); err != nil {
+			return fmt.Errorf("writing encrypted manifest: %w", err)
+		}
+	}
+
+	shareInfos, err := writeShares(p, friendShares, ephemeral)
+	if err != nil {
+		return err
+	}
+
+	transcript := core.ReshareTranscript{
+		Project:          p.Name,
+		At:               now,
+		ManifestChecksum: core.HashBytes(manifestData),
+		OldThreshold:     shares[0].Threshold,
+		OldTotal:         shares[0].Total,
+		Participants:     reshareParticipants(shares),
+		NewThreshold:     p.Threshold,
+	}
+	for _, f := range p.Friends {
+		transcript.NewHolders = append(transcript.NewHolders, f.Name)
+	}
+	transcript.Sign(passphrase)
+
+	sealed := project.Sealed{
+		At:               now,
+		ManifestChecksum: transcript.ManifestChecksum,
+		VerificationHash: core.HashBytes(passphrase.Bytes()),
+		Threshold:        p.Threshold,
+		Shares:           shareInfos,
+		Version:          version,
+		RecoveryURL:      recoveryURL,
+		Ephemeral:        ephemeral,
+		Reshare:          &transcript,
+	}
+	if previous != nil {
+		// Same MANIFEST.age, so the same files
+		sealed.Files = previous.Files
+	}
+	p.AddSeal(sealed)
+
+	transcriptPath := filepath.Join(p.OutputPath(), fmt.Sprintf("RESHARE-%s.txt", now.Format("20060102-150405")))
+	if err := os.WriteFile(transcriptPath, []byte(transcript.Encode()), 0644); err != nil {
+		return fmt.Errorf("writing transcript: %w", err)
+	}
+	if err := p.Save(); err != nil {
+		return fmt.Errorf("saving project: %w", err)
+	}
+
+	bundles := statBundleFiles(paths)
+
+	relTranscript, _ := filepath.Rel(p.Path, transcriptPath)
+	if isJSON() {
+		return printJSON(reshareResult{
+			Project:        p.Name,
+			Path:           p.Path,
+			Seal:           len(p.Seals),
+			Transcript:     transcript,
+			TranscriptFile: relTranscript,
+			Shares:         shareInfos,
+			Bundles:        bundles,
+		})
+	}
+
+	fmt.Fprintln(textOut)
+	fmt.Fprintf(textOut, "Reshared (seal #%d): %d of %d → %d of %d\n", len(p.Seals), transcript.OldThreshold, transcript.OldTotal, p.Threshold, len(p.Friends))
+	fmt.Fprintf(textOut, "  Participants: %s\n", strings.Join(transcript.Participants, ", "))
+	fmt.Fprintf(textOut, "  %s %s (signed transcript)\n", green("✓"), relTranscript)
+	for _, si := range shareInfos {
+		if si.File != "" {
+			fmt.Fprintf(textOut, "  %s %s\n", green("✓"), si.File)
+		}
+	}
+	for _, b := range bundles {
+		fmt.Fprintf(textOut, "  %s %s (%s)\n", green("✓"), filepath.Base(b.Path), formatSize(b.Size))
+	}
+	fmt.Fprintf(textOut, "\nSaved to: %s\n", p.OutputPath())
+	fmt.Fprintln(textOut, yellow("The old bundles still work with the old threshold; ask their holders to destroy them."))
+	return nil
+}
+
+// splitPassphrase splits a recovered passphrase again for the friends of p,
+// in the share format of the given version.
+func splitPassphrase(passphrase *core.Secret, shareVersion int, p *project.Project) ([]*core.Share, error) {
+	secret := passphrase.Bytes()
+	if shareVersion >= 2 {
+		raw := make([]byte, base64.RawURLEncoding.DecodedLen(passphrase.Len()))
+		defer core.Wipe(raw)
+		n, err := base64.RawURLEncoding.Decode(raw, passphrase.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("decoding passphrase: %w", err)
+		}
+		secret = raw[:n]
+	}
+	parts, err := core.Split(secret, len(p.Friends), p.Threshold)
+	if err != nil {
+		return nil, fmt.Errorf("splitting passphrase: %w", err)
+	}
+	shares := make([]*core.Share, len(parts))
+	for i, data := range parts {
+		shares[i] = core.NewShare(shareVersion, i+1, len(p.Friends), p.Threshold, p.Friends[i].Name, data)
+	}
+	return shares, nil
+}
+
+// reshareParticipants names the holders whose shares were combined.
+func reshareParticipants(shares []*core.Share) []string {
+	names := make([]string, len(shares))
+	for i, s := range shares {
+		if s.Holder != "" {
+			names[i] = fmt.Sprintf("%s (share %d)", s.Holder, s.Index)
+		} else {
+			names[i] = fmt.Sprintf("share %d", s.Index)
+		}
+	}
+	return names
+}
@@ -1,12 +1,28 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
 // version is set at build time via -ldflags
 var version = "dev"
 
+// outputFormat is the global --format flag ("text" or "json")
+var outputFormat string
+
+// textOut receives human-readable output. In JSON mode it is discarded,
+// so stdout only carries the JSON document.
+var textOut io.Writer = os.Stdout
+
+// jsonOut receives the JSON document in JSON mode.
+var jsonOut io.Writer = os.Stdout
+
 var rootCmd = &cobra.Command{
 	Use:   "rememory",
 	Short: "🧠 Encrypt secrets and split access among trusted friends",
@@ -15,13 +31,84 @@ using Shamir's Secret Sharing, and creates recovery bundles for trusted friends.
 
 Create a project:    rememory init my-recovery
 Seal the manifest:   rememory seal
-Recover from shares: rememory recover share1.txt share2.txt share3.txt`,
+Recover from shares: rememory recover share1.txt share2.txt share3.txt
+
+Use --format json for machine-readable output.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch outputFormat {
+		case "text":
+			textOut = os.Stdout
+		case "json":
+			textOut = io.Discard
+			silenceForJSON(cmd)
+		default:
+			return newError(CodeUsage, "unsupported format %q (supported: text, json)", outputFormat)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "Output format: text or json")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		if isJSON() {
+			silenceForJSON(cmd)
+		}
+		return &Error{Code: CodeUsage, Err: err}
+	})
 }
 
 func Execute(v string) error {
 	version = v
 	rootCmd.Version = v
-	return rootCmd.Execute()
+
+	err := rootCmd.Execute()
+	if err != nil && isJSON() {
+		printJSONError(err)
+	}
+	return err
+}
+
+// silenceForJSON stops cobra from printing errors and usage text,
+// since failures are reported as JSON instead.
+func silenceForJSON(cmd *cobra.Command) {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+}
+
+// isJSON reports whether --format json was requested.
+func isJSON() bool {
+	return outputFormat == "json"
+}
+
+// printJSON writes v as indented JSON to jsonOut.
+func printJSON(v any) error {
+	enc := json.NewEncoder(jsonOut)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+// jsonError is the JSON document printed when a command fails.
+type jsonError struct {
+	Error struct {
+		Code    ErrorCode `json:"code"`
+		Message string    `json:"message"`
+	} `json:"error"`
+}
+
+func printJSONError(err error) {
+	var e *Error
+	if errors.As(err, &e) && e.Reported {
+		return
+	}
+
+	var doc jsonError
+	doc.Error.Code = errorCode(err)
+	doc.Error.Message = err.Error()
+	printJSON(doc)
 }
 
 // Color helpers (ANSI escape codes)
@@ -2,10 +2,11 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/base64"
+	stdcrypto "crypto"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/eljojo/rememory/internal/bundle"
@@ -14,6 +15,7 @@ import (
 	"github.com/eljojo/rememory/internal/html"
 	"github.com/eljojo/rememory/internal/manifest"
 	"github.com/eljojo/rememory/internal/project"
+	"github.com/eljojo/rememory/pkg/rememory"
 	"github.com/spf13/cobra"
 )
 
@@ -31,222 +33,444 @@ This command:
   5. Generates ZIP bundles for distribution
   6. Writes checksums to project.yml
 
-Run this command inside a project directory (created with 'rememory init').`,
+Run this command inside a project directory (created with 'rememory init').
+
+By default the shares are also kept in output/shares/ and every bundle in
+output/bundles/, so anyone who copies your project directory can recover
+everything alone. With --ephemeral, shares only exist in memory and inside
+the bundles, which you can write straight to each friend's USB stick:
+
+  rememory seal --ephemeral --dest Alice=/media/usb1 --dest Bob=/media/usb2 --out ~/handover
+
+With --sign-key, every bundle is signed with your Ed25519 SSH key, and its
+fingerprint is printed in README.txt and README.pdf. Anyone with your public
+key can then check that a bundle wasn't altered:
+
+  rememory verify-bundle --pubkey ~/.ssh/id_ed25519.pub bundle-alice.zip`,
 	RunE: runSeal,
 }
 
 func init() {
 	sealCmd.Flags().String("recovery-url", core.DefaultRecoveryURL, "Base URL for QR code in PDF")
 	sealCmd.Flags().Bool("no-embed-manifest", false, "Do not embed MANIFEST.age in recover.html (it is embedded by default when 5 MB or less)")
+	sealCmd.Flags().Bool("paper-manifest", false, "Also print MANIFEST.age in README.pdf, as QR codes and typed lines (8 KB or less)")
+	sealCmd.Flags().Bool("ephemeral", false, "Keep shares in memory only: don't write share files, only bundles")
+	sealCmd.Flags().String("out", "", "Directory for the bundles (default output/bundles)")
+	sealCmd.Flags().StringArray("dest", nil, "Write one friend's bundle to a directory, as NAME=DIR (repeatable)")
+	sealCmd.Flags().String("sign-key", "", "Sign the bundles with this Ed25519 SSH private key (e.g. ~/.ssh/id_ed25519)")
 	rootCmd.AddCommand(sealCmd)
 }
 
+// sealResult is the JSON output of the seal and demo commands.
+type sealResult struct {
+	Project   string              `json:"project"`
+	SealedAt  time.Time           `json:"sealed_at"`
+	Seal      int                 `json:"seal"` // number of this seal in the project's history, from 1
+	Threshold int                 `json:"threshold"`
+	Total     int                 `json:"total"`
+	Manifest  sealManifest        `json:"manifest"`
+	Shares    []project.ShareInfo `json:"shares"`
+	Bundles   []bundleFile        `json:"bundles"`
+	Warnings  []string            `json:"warnings"`
+	SignedBy  string              `json:"signed_by,omitempty"` // fingerprint of the key that signed the bundles
+}
+
+type sealManifest struct {
+	File     string `json:"file"`
+	Checksum string `json:"checksum"`
+	Size     int64  `json:"size"`
+	Files    int    `json:"files"`
+}
+
+// sealOptions controls what sealProject writes and where.
+type sealOptions struct {
+	RecoveryURL     string // base URL for QR codes in the PDF; empty uses the production URL
+	NoEmbedManifest bool   // don't embed MANIFEST.age in recover.html
+	PaperManifest   bool   // print MANIFEST.age in README.pdf
+	// Ephemeral keeps the shares in memory: no share files are written, and
+	// each share only leaves the process inside its friend's bundle.
+	Ephemeral    bool
+	OutDir       string            // bundles directory; empty means output/bundles
+	Destinations map[string]string // per-friend bundle directories, by friend name
+	Signer       stdcrypto.Signer  // signs every bundle when set
+}
+
+// bundleFile describes a generated bundle ZIP.
+type bundleFile struct {
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
 func runSeal(cmd *cobra.Command, args []string) error {
-	// Find and load the project
-	cwd, err := os.Getwd()
+	p, err := loadProject()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return err
 	}
 
-	projectDir, err := project.FindProjectDir(cwd)
-	if err != nil {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("invalid project: %w", err)
+	}
+
+	opts := sealOptions{}
+	opts.RecoveryURL, _ = cmd.Flags().GetString("recovery-url")
+	opts.NoEmbedManifest, _ = cmd.Flags().GetBool("no-embed-manifest")
+	opts.PaperManifest, _ = cmd.Flags().GetBool("paper-manifest")
+	opts.Ephemeral, _ = cmd.Flags().GetBool("ephemeral")
+	opts.OutDir, _ = cmd.Flags().GetString("out")
+	dests, _ := cmd.Flags().GetStringArray("dest")
+	if opts.Destinations, err = parseDestinations(p.Friends, dests); err != nil {
 		return err
 	}
+	if keyPath, _ := cmd.Flags().GetString("sign-key"); keyPath != "" {
+		if opts.Signer, err = loadSigningKey(keyPath); err != nil {
+			return newError(CodeUsage, "%v", err)
+		}
+	}
+	if opts.Ephemeral && opts.OutDir == "" && len(opts.Destinations) < len(p.Friends) {
+		return newError(CodeUsage, "with --ephemeral, choose where the bundles go with --out DIR or --dest NAME=DIR for every friend")
+	}
 
-	p, err := project.Load(projectDir)
+	if opts.Ephemeral {
+		dirs := []string{opts.OutDir}
+		for _, f := range p.Friends {
+			dirs = append(dirs, opts.Destinations[f.Name])
+		}
+		warned := make(map[string]bool)
+		for _, dir := range dirs {
+			if dir != "" && !warned[dir] && isInside(p.Path, dir) {
+				warned[dir] = true
+				fmt.Fprintf(textOut, "%s %s is inside the project directory; move the bundles off this computer once they're delivered.\n", yellow("Warning:"), dir)
+			}
+		}
+	}
+
+	result, err := sealProject(p, opts)
 	if err != nil {
-		return fmt.Errorf("loading project: %w", err)
+		return err
 	}
 
-	if err := p.Validate(); err != nil {
-		return fmt.Errorf("invalid project: %w", err)
+	if isJSON() {
+		return printJSON(result)
 	}
 
-	recoveryURL, _ := cmd.Flags().GetString("recovery-url")
-	noEmbedManifest, _ := cmd.Flags().GetBool("no-embed-manifest")
+	fmt.Fprintln(textOut)
+	for _, dir := range bundleDirs(result.Bundles) {
+		fmt.Fprintf(textOut, "Saved to: %s\n", dir)
+	}
+	if opts.Ephemeral {
+		fmt.Fprintln(textOut, "\nNo share files were kept: these bundles hold the only copies of the shares.")
+	}
 
-	if err := sealProject(p, recoveryURL, noEmbedManifest); err != nil {
-		return err
+	return nil
+}
+
+// parseDestinations parses --dest NAME=DIR values into directories by friend name.
+func parseDestinations(friends []project.Friend, values []string) (map[string]string, error) {
+	dests := make(map[string]string)
+	for _, v := range values {
+		name, dir, ok := strings.Cut(v, "=")
+		if !ok || name == "" || dir == "" {
+			return nil, newError(CodeUsage, "invalid --dest %q (expected NAME=DIR)", v)
+		}
+		idx, err := findFriend(friends, name)
+		if err != nil {
+			return nil, err
+		}
+		dests[friends[idx].Name] = dir
 	}
+	return dests, nil
+}
 
-	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
-	fmt.Printf("\nSaved to: %s\n", bundlesDir)
+// isInside reports whether path is base or a directory below it.
+func isInside(base, path string) bool {
+	absBase, err1 := filepath.Abs(base)
+	absPath, err2 := filepath.Abs(path)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absBase, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
 
-	return nil
+// bundleDirs returns the distinct directories bundles were written to, in order.
+func bundleDirs(bundles []bundleFile) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, b := range bundles {
+		dir := filepath.Dir(b.Path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
 }
 
 // sealProject archives, encrypts, splits, verifies, saves, and generates bundles
 // for an already-loaded project. Both runSeal and runDemo share this logic.
-// recoveryURL is the base URL for QR codes in the PDF. If empty, the PDF defaults to the production URL.
-// noEmbedManifest controls whether MANIFEST.age is embedded in recover.html.
-func sealProject(p *project.Project, recoveryURL string, noEmbedManifest bool) error {
+func sealProject(p *project.Project, opts sealOptions) (*sealResult, error) {
 	// Check manifest directory exists and has content
 	manifestDir := p.ManifestPath()
 	fileCount, err := manifest.CountFiles(manifestDir)
 	if err != nil {
-		return fmt.Errorf("checking manifest directory: %w", err)
+		return nil, fmt.Errorf("checking manifest directory: %w", err)
 	}
 	if fileCount == 0 {
-		return fmt.Errorf("manifest directory is empty: %s", manifestDir)
+		return nil, fmt.Errorf("manifest directory is empty: %s", manifestDir)
 	}
 
 	dirSize, err := manifest.DirSize(manifestDir)
 	if err != nil {
-		return fmt.Errorf("calculating manifest size: %w", err)
+		return nil, fmt.Errorf("calculating manifest size: %w", err)
 	}
 
-	fmt.Printf("Archiving manifest/ (%d files, %s)...\n", fileCount, formatSize(dirSize))
-
-	// Archive the manifest directory
-	var archiveBuf bytes.Buffer
-	archiveResult, err := manifest.Archive(&archiveBuf, manifestDir)
+	holders := make([]string, len(p.Friends))
+	for i, f := range p.Friends {
+		holders[i] = f.Name
+	}
+	verifying := false
+	var encryptedBuf bytes.Buffer
+	sealedManifest, err := rememory.SealDir(&encryptedBuf, manifestDir, rememory.SealOptions{
+		Holders:   holders,
+		Threshold: p.Threshold,
+		Progress: func(e rememory.Event) {
+			switch e.Stage {
+			case rememory.StageArchive:
+				fmt.Fprintf(textOut, "Archiving manifest/ (%d files, %s)...\n", fileCount, formatSize(dirSize))
+			case rememory.StageEncrypt:
+				fmt.Fprintln(textOut, "Encrypting with age...")
+			case rememory.StageSplit:
+				fmt.Fprintf(textOut, "Splitting into %d shares (threshold: %d)...\n", len(p.Friends), p.Threshold)
+			case rememory.StageVerify:
+				fmt.Fprint(textOut, "Verifying reconstruction... ")
+				verifying = true
+			}
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("archiving manifest: %w", err)
+		if verifying {
+			fmt.Fprintln(textOut, "FAILED")
+		}
+		return nil, err
 	}
-
-	for _, warning := range archiveResult.Warnings {
-		fmt.Printf("  Warning: %s\n", warning)
+	fmt.Fprintln(textOut, "OK")
+	for _, warning := range sealedManifest.Warnings {
+		fmt.Fprintf(textOut, "  Warning: %s\n", warning)
+	}
+	if opts.PaperManifest && encryptedBuf.Len() > core.MaxPaperManifestSize {
+		return nil, newError(CodeUsage, "MANIFEST.age is %s, too large for --paper-manifest (at most %s); keep the manifest to a few KB of text", formatSize(int64(encryptedBuf.Len())), formatSize(core.MaxPaperManifestSize))
 	}
 
-	// Generate passphrase (v2: split raw bytes, not the base64 string)
-	raw, passphrase, err := crypto.GenerateRawPassphrase(crypto.DefaultPassphraseBytes)
-	if err != nil {
-		return fmt.Errorf("generating passphrase: %w", err)
+	wasmBytes := html.GetRecoverWASMBytes()
+	if len(wasmBytes) == 0 {
+		return nil, fmt.Errorf("recover.wasm not embedded - rebuild with 'make build'")
 	}
 
-	fmt.Println("Encrypting with age...")
+	// Generate bundles first: until every bundle is written, the previous
+	// seal stays current and its files stay in place
+	fmt.Fprintln(textOut)
+	fmt.Fprintf(textOut, "Generating bundles for %d friends...\n", len(p.Friends))
 
-	// Encrypt the archive
-	var encryptedBuf bytes.Buffer
-	archiveReader := bytes.NewReader(archiveBuf.Bytes())
-	if err := core.Encrypt(&encryptedBuf, archiveReader, passphrase); err != nil {
-		return fmt.Errorf("encrypting: %w", err)
+	cfg := bundle.Config{
+		Version:          version,
+		GitHubReleaseURL: fmt.Sprintf("https://github.com/eljojo/rememory/releases/tag/%s", version),
+		WASMBytes:        wasmBytes,
+		RecoveryURL:      opts.RecoveryURL,
+		NoEmbedManifest:  opts.NoEmbedManifest,
+		PaperManifest:    opts.PaperManifest,
+		Destinations:     opts.Destinations,
+		Signer:           opts.Signer,
+	}
+
+	outDir := opts.OutDir
+	if outDir == "" {
+		outDir = filepath.Join(p.OutputPath(), "bundles")
+	}
+	sealedAt := time.Now().UTC()
+	friendShares := sealedManifest.Shares
+	paths, err := bundle.GenerateBundles(p, friendShares, encryptedBuf.Bytes(), outDir, sealedAt, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("generating bundles: %w", err)
 	}
 
 	// Create output directories
 	sharesDir := p.SharesPath()
 	if err := os.MkdirAll(sharesDir, 0755); err != nil {
-		return fmt.Errorf("creating output directories: %w", err)
+		return nil, fmt.Errorf("creating output directories: %w", err)
 	}
 
 	// Write encrypted manifest
 	manifestAgePath := p.ManifestAgePath()
-	if err := os.WriteFile(manifestAgePath, encryptedBuf.Bytes(), 0644); err != nil {
-		return fmt.Errorf("writing encrypted manifest: %w", err)
+	if err := bundle.WriteManifestFile(manifestAgePath, encryptedBuf.Bytes()); err != nil {
+		return nil, fmt.Errorf("writing encrypted manifest: %w", err)
 	}
 
-	fmt.Printf("Splitting into %d shares (threshold: %d)...\n", len(p.Friends), p.Threshold)
-
-	// Split the raw bytes (v2: 32 bytes instead of 43-byte base64 string)
-	shares, err := core.Split(raw, len(p.Friends), p.Threshold)
-	if err != nil {
-		return fmt.Errorf("splitting passphrase: %w", err)
-	}
-
-	// Create share files
-	shareInfos := make([]project.ShareInfo, len(shares))
-	for i, shareData := range shares {
-		friend := p.Friends[i]
-		share := core.NewShare(2, i+1, len(p.Friends), p.Threshold, friend.Name, shareData)
-
-		filename := share.Filename()
-		sharePath := filepath.Join(sharesDir, filename)
-
-		if err := os.WriteFile(sharePath, []byte(share.Encode()), 0600); err != nil {
-			return fmt.Errorf("writing share for %s: %w", friend.Name, err)
-		}
-
-		fileChecksum, err := crypto.HashFile(sharePath)
-		if err != nil {
-			return fmt.Errorf("computing checksum: %w", err)
-		}
-
-		relPath, _ := filepath.Rel(p.Path, sharePath)
-		shareInfos[i] = project.ShareInfo{
-			Friend:   friend.Name,
-			File:     relPath,
-			Checksum: fileChecksum,
+	// Shares from an earlier seal would be left behind next to the new ones
+	if opts.Ephemeral {
+		if n, err := removePreviousSeal(p, paths); err != nil {
+			return nil, err
+		} else if n > 0 {
+			fmt.Fprintf(textOut, "  Removed %d share and bundle file%s from the previous seal\n", n, plural(n))
 		}
 	}
 
-	// Verify reconstruction
-	fmt.Print("Verifying reconstruction... ")
-	testShares := make([][]byte, p.Threshold)
-	for i := 0; i < p.Threshold; i++ {
-		testShares[i] = shares[i]
-	}
-	recovered, err := core.Combine(testShares)
-	if err != nil {
-		fmt.Println("FAILED")
-		return fmt.Errorf("verification failed: %w", err)
-	}
-	if base64.RawURLEncoding.EncodeToString(recovered) != passphrase {
-		fmt.Println("FAILED")
-		return fmt.Errorf("verification failed: reconstructed passphrase doesn't match")
-	}
-	fmt.Println("OK")
-
-	// Update project with seal information
-	manifestChecksum, err := crypto.HashFile(manifestAgePath)
+	// Create share files (ephemeral seals only checksum them)
+	shareInfos, err := writeShares(p, friendShares, opts.Ephemeral)
 	if err != nil {
-		return fmt.Errorf("computing manifest checksum: %w", err)
+		return nil, err
 	}
+	manifestChecksum := sealedManifest.ManifestChecksum
 
-	p.Sealed = &project.Sealed{
-		At:               time.Now().UTC(),
+	// Older seals stay in the history so their bundles can still be recognized
+	sealed := project.Sealed{
+		At:               sealedAt,
 		ManifestChecksum: manifestChecksum,
-		VerificationHash: core.HashString(passphrase),
+		VerificationHash: sealedManifest.VerificationHash,
+		Threshold:        p.Threshold,
 		Shares:           shareInfos,
+		Version:          version,
+		RecoveryURL:      opts.RecoveryURL,
+		Ephemeral:        opts.Ephemeral,
+		Files:            sealedManifest.Files,
+		SignedBy:         signerFingerprint(opts.Signer),
 	}
+	p.AddSeal(sealed)
 
 	if err := p.Save(); err != nil {
-		return fmt.Errorf("saving project: %w", err)
+		return nil, fmt.Errorf("saving project: %w", err)
 	}
 
 	// Print seal summary
-	fmt.Println()
-	fmt.Println("Sealed:")
+	fmt.Fprintln(textOut)
+	fmt.Fprintf(textOut, "Sealed (seal #%d):\n", len(p.Seals))
 	relManifest, _ := filepath.Rel(p.Path, manifestAgePath)
-	fmt.Printf("  %s %s\n", green("✓"), relManifest)
+	fmt.Fprintf(textOut, "  %s %s (and %s)\n", green("✓"), relManifest, core.ParityFileName)
+	if opts.Ephemeral {
+		fmt.Fprintf(textOut, "  %s %d shares (in memory only)\n", green("✓"), len(shareInfos))
+	}
 	for _, si := range shareInfos {
-		fmt.Printf("  %s %s\n", green("✓"), si.File)
+		if si.File != "" {
+			fmt.Fprintf(textOut, "  %s %s\n", green("✓"), si.File)
+		}
 	}
 
-	// Generate bundles
-	fmt.Println()
-	fmt.Printf("Generating bundles for %d friends...\n", len(p.Friends))
+	// Print bundle listing
+	bundles := statBundleFiles(paths)
 
-	wasmBytes := html.GetRecoverWASMBytes()
-	if len(wasmBytes) == 0 {
-		return fmt.Errorf("recover.wasm not embedded - rebuild with 'make build'")
+	fmt.Fprintln(textOut)
+	fmt.Fprintln(textOut, "Bundles ready:")
+	for _, b := range bundles {
+		fmt.Fprintf(textOut, "  %s %s (%s)\n", green("✓"), filepath.Base(b.Path), formatSize(b.Size))
+	}
+	if sealed.SignedBy != "" {
+		fmt.Fprintf(textOut, "  Signed by %s\n", sealed.SignedBy)
 	}
 
-	cfg := bundle.Config{
-		Version:          version,
-		GitHubReleaseURL: fmt.Sprintf("https://github.com/eljojo/rememory/releases/tag/%s", version),
-		WASMBytes:        wasmBytes,
-		RecoveryURL:      recoveryURL,
-		NoEmbedManifest:  noEmbedManifest,
+	return &sealResult{
+		Project:   p.Name,
+		SealedAt:  sealed.At,
+		Seal:      len(p.Seals),
+		Threshold: p.Threshold,
+		Total:     len(p.Friends),
+		Manifest: sealManifest{
+			File:     relManifest,
+			Checksum: manifestChecksum,
+			Size:     int64(encryptedBuf.Len()),
+			Files:    len(sealedManifest.Files),
+		},
+		Shares:   shareInfos,
+		Bundles:  bundles,
+		Warnings: append([]string{}, sealedManifest.Warnings...),
+		SignedBy: sealed.SignedBy,
+	}, nil
+}
+
+// writeShares writes each friend's share to output/shares, unless ephemeral,
+// and returns their records for project.yml in friend order.
+func writeShares(p *project.Project, shares []*core.Share, ephemeral bool) ([]project.ShareInfo, error) {
+	shareInfos := bundle.ShareInfos(p, shares)
+	if ephemeral {
+		return shareInfos, nil
 	}
+	for i, share := range shares {
+		friend := p.Friends[i]
+		sharePath := filepath.Join(p.SharesPath(), share.Filename())
+		if err := os.WriteFile(sharePath, []byte(share.Encode()), 0600); err != nil {
+			return nil, fmt.Errorf("writing share for %s: %w", friend.Name, err)
+		}
 
-	if err := bundle.GenerateAll(p, cfg); err != nil {
-		return fmt.Errorf("generating bundles: %w", err)
+		fileChecksum, err := crypto.HashFile(sharePath)
+		if err != nil {
+			return nil, fmt.Errorf("computing checksum: %w", err)
+		}
+
+		relPath, _ := filepath.Rel(p.Path, sharePath)
+		shareInfos[i].File = relPath
+		shareInfos[i].Checksum = fileChecksum
 	}
+	return shareInfos, nil
+}
 
-	// Print bundle listing
-	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
-	entries, _ := os.ReadDir(bundlesDir)
+// removePreviousSeal deletes share files and bundles left in output/ by an
+// earlier seal, except the new bundles in keep. Share files are overwritten
+// before they're deleted.
+func removePreviousSeal(p *project.Project, keep []string) (int, error) {
+	n := 0
+	shares, _ := filepath.Glob(filepath.Join(p.SharesPath(), "SHARE-*.txt"))
+	for _, path := range shares {
+		if err := wipeFile(path); err != nil {
+			return n, fmt.Errorf("wiping old share: %w", err)
+		}
+		n++
+	}
+	bundles, _ := filepath.Glob(filepath.Join(p.OutputPath(), "bundles", "bundle-*.zip"))
+	kept := make(map[string]bool)
+	for _, path := range keep {
+		if abs, err := filepath.Abs(path); err == nil {
+			kept[abs] = true
+		}
+	}
+	for _, path := range bundles {
+		if abs, err := filepath.Abs(path); err == nil && kept[abs] {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return n, fmt.Errorf("removing old bundle: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
 
-	fmt.Println()
-	fmt.Println("Bundles ready:")
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			info, _ := entry.Info()
-			fmt.Printf("  %s %s (%s)\n", green("✓"), entry.Name(), formatSize(info.Size()))
+// statBundleFiles returns the given bundle ZIPs with their sizes.
+func statBundleFiles(paths []string) []bundleFile {
+	bundles := []bundleFile{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
 		}
+		bundles = append(bundles, bundleFile{Path: path, Size: info.Size()})
 	}
+	return bundles
+}
 
-	return nil
+// listBundleFiles returns the bundle ZIPs in dir with their sizes.
+func listBundleFiles(dir string) []bundleFile {
+	bundles := []bundleFile{}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		bundles = append(bundles, bundleFile{Path: filepath.Join(dir, entry.Name()), Size: info.Size()})
+	}
+	return bundles
 }
 
 func formatSize(bytes int64) string {
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/eljojo/rememory/internal/core"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// loadSigningKey reads the owner's Ed25519 key from an OpenSSH private key
+// file. A key protected by a passphrase is used through ssh-agent instead,
+// so it has to be loaded there (ssh-add).
+func loadSigningKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	key, err := ssh.ParseRawPrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		if missing.PublicKey == nil {
+			return nil, fmt.Errorf("%s is protected by a passphrase; add it to ssh-agent with ssh-add", path)
+		}
+		return agentSigner(missing.PublicKey, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key %s: %w", path, err)
+	}
+	ed, ok := key.(*ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 key (create one with: ssh-keygen -t ed25519)", path)
+	}
+	return *ed, nil
+}
+
+// agentSigner finds pub in ssh-agent and signs with it there.
+func agentSigner(pub ssh.PublicKey, path string) (crypto.Signer, error) {
+	if pub.Type() != ssh.KeyAlgoED25519 {
+		return nil, fmt.Errorf("%s is not an Ed25519 key (create one with: ssh-keygen -t ed25519)", path)
+	}
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, fmt.Errorf("%s is protected by a passphrase and ssh-agent isn't running; start it and run ssh-add", path)
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to ssh-agent: %w", err)
+	}
+	signers, err := agent.NewClient(conn).Signers()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("listing ssh-agent keys: %w", err)
+	}
+	want := pub.Marshal()
+	for _, s := range signers {
+		if string(s.PublicKey().Marshal()) == string(want) {
+			edPub := pub.(ssh.CryptoPublicKey).CryptoPublicKey().(ed25519.PublicKey)
+			return &sshSigner{signer: s, pub: edPub}, nil
+		}
+	}
+	conn.Close()
+	return nil, fmt.Errorf("%s is protected by a passphrase and isn't in ssh-agent; run: ssh-add %s", path, path)
+}
+
+// sshSigner adapts a key held by ssh-agent to crypto.Signer. For Ed25519,
+// the agent's signature is the plain Ed25519 signature of the message.
+type sshSigner struct {
+	signer ssh.Signer
+	pub    ed25519.PublicKey
+}
+
+func (s *sshSigner) Public() crypto.PublicKey { return s.pub }
+
+func (s *sshSigner) Sign(rand io.Reader, message []byte, _ crypto.SignerOpts) ([]byte, error) {
+	sig, err := s.signer.Sign(rand, message)
+	if err != nil {
+		return nil, err
+	}
+	if sig.Format != ssh.KeyAlgoED25519 {
+		return nil, fmt.Errorf("ssh-agent returned a %s signature", sig.Format)
+	}
+	return sig.Blob, nil
+}
+
+// signerFingerprint returns the fingerprint of signer's key, or "" for nil.
+func signerFingerprint(signer crypto.Signer) string {
+	if signer == nil {
+		return ""
+	}
+	pub, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return ""
+	}
+	return core.KeyFingerprint(pub)
+}
+
+// readPublicKey reads the key bundles must be signed with: a fingerprint
+// (SHA256:...), a public key ("ssh-ed25519 AAAA..."), or the path of a .pub
+// file. It returns the key's fingerprint.
+func readPublicKey(value string) (string, error) {
+	if strings.HasPrefix(value, "SHA256:") {
+		return value, nil
+	}
+	if !strings.HasPrefix(value, "ssh-") {
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return "", fmt.Errorf("reading public key: %w", err)
+		}
+		value = string(data)
+	}
+	pub, err := core.ParseSSHPublicKey(value)
+	if err != nil {
+		return "", err
+	}
+	return core.KeyFingerprint(pub), nil
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/manifest"
 	"github.com/eljojo/rememory/internal/project"
 	"github.com/spf13/cobra"
 )
@@ -22,84 +24,292 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 }
 
-func runStatus(cmd *cobra.Command, args []string) error {
-	// Find project
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
-	}
+// statusResult is the JSON output of the status command.
+type statusResult struct {
+	Project   string         `json:"project"`
+	Path      string         `json:"path"`
+	Sealed    *statusSealed  `json:"sealed"`
+	Seals     []statusSeal   `json:"seals"`
+	Threshold int            `json:"threshold"`
+	Total     int            `json:"total"`
+	Friends   []statusFriend `json:"friends"`
+	Bundles   statusBundles  `json:"bundles"`
+	LastDrill *project.Drill `json:"last_drill"`
+	// LastChallenge is the most recent proof-of-possession check
+	LastChallenge *project.ChallengeRound `json:"last_challenge"`
+}
+
+type statusSealed struct {
+	Seal             int               `json:"seal"`
+	Ephemeral        bool              `json:"ephemeral"`
+	At               time.Time         `json:"at"`
+	ManifestChecksum string            `json:"manifest_checksum"`
+	AgeDays          int               `json:"age_days"`
+	RotationDue      bool              `json:"rotation_due"`
+	ManifestChanged  bool              `json:"manifest_changed"`
+	Changes          *manifest.Changes `json:"changes,omitempty"`
+	// Reshare is set when the seal was made by 'rememory reshare'
+	Reshare *core.ReshareTranscript `json:"reshare,omitempty"`
+}
+
+// statusSeal is one entry of the seal history.
+type statusSeal struct {
+	Seal             int        `json:"seal"`
+	At               time.Time  `json:"at"`
+	Current          bool       `json:"current"`
+	SupersededAt     *time.Time `json:"superseded_at,omitempty"`
+	ManifestChecksum string     `json:"manifest_checksum"`
+	Threshold        int        `json:"threshold,omitempty"`
+	Holders          []string   `json:"holders"`
+	Version          string     `json:"version,omitempty"`
+	RecoveryURL      string     `json:"recovery_url,omitempty"`
+}
+
+type statusFriend struct {
+	Name        string `json:"name"`
+	Contact     string `json:"contact,omitempty"`
+	Language    string `json:"language,omitempty"`
+	ShareExists bool   `json:"share_exists"`
+	// Delivery is how the friend's bundle from the current seal was handed over
+	Delivery *project.Delivery `json:"delivery"`
+}
+
+type statusBundles struct {
+	Count int    `json:"count"`
+	Dir   string `json:"dir"`
+}
 
-	projectDir, err := project.FindProjectDir(cwd)
+// rotationAge is how long after sealing status suggests rotating.
+const rotationAge = 2 * 365 * 24 * time.Hour
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	p, err := loadProject()
 	if err != nil {
-		return fmt.Errorf("no rememory project found (run 'rememory init' first)")
+		return err
 	}
 
-	// Load project
-	p, err := project.Load(projectDir)
-	if err != nil {
-		return fmt.Errorf("loading project: %w", err)
+	result := statusResult{
+		Project:   p.Name,
+		Path:      p.Path,
+		Threshold: p.Threshold,
+		Total:     len(p.Friends),
+		Friends:   []statusFriend{},
+		Seals:     []statusSeal{},
 	}
 
 	// Print status
-	fmt.Printf("Project: %s\n", p.Name)
-	fmt.Printf("Path: %s\n\n", p.Path)
+	fmt.Fprintf(textOut, "Project: %s\n", p.Name)
+	fmt.Fprintf(textOut, "Path: %s\n\n", p.Path)
 
 	// Sealed status
-	if p.Sealed != nil {
-		fmt.Printf("Sealed: %s (%s)\n", green("Yes"), p.Sealed.At.Format("2006-01-02 15:04:05 UTC"))
-		fmt.Printf("Manifest Checksum: %s\n", truncateHash(p.Sealed.ManifestChecksum))
+	sealed := p.CurrentSeal()
+	if sealed != nil {
+		age := time.Since(sealed.At)
+		result.Sealed = &statusSealed{
+			Seal:             p.CurrentSealIndex() + 1,
+			Ephemeral:        sealed.Ephemeral,
+			At:               sealed.At,
+			ManifestChecksum: sealed.ManifestChecksum,
+			AgeDays:          int(age.Hours() / 24),
+			RotationDue:      age > rotationAge,
+			Reshare:          sealed.Reshare,
+		}
+
+		fmt.Fprintf(textOut, "Sealed: %s (%s, seal #%d)\n", green("Yes"), sealed.At.Format("2006-01-02 15:04:05 UTC"), result.Sealed.Seal)
+		fmt.Fprintf(textOut, "Manifest Checksum: %s\n", truncateHash(sealed.ManifestChecksum))
+		if sealed.Ephemeral {
+			fmt.Fprintln(textOut, "Shares: not kept on disk (ephemeral seal)")
+		}
+		if r := sealed.Reshare; r != nil {
+			fmt.Fprintf(textOut, "Reshared: from %d of %d by %s\n", r.OldThreshold, r.OldTotal, strings.Join(r.Participants, ", "))
+		}
+		if len(sealed.Files) > 0 {
+			if changes, err := manifestChanges(p); err == nil && !changes.IsEmpty() {
+				result.Sealed.ManifestChanged = true
+				result.Sealed.Changes = &changes
+				n := len(changes.Added) + len(changes.Removed) + len(changes.Modified)
+				fmt.Fprintf(textOut, "Manifest: %s\n", yellow(fmt.Sprintf("changed since seal (%d file%s) — reseal needed", n, plural(n))))
+				fmt.Fprintln(textOut, "  Run 'rememory diff' to see what changed")
+			}
+		}
 	} else {
-		fmt.Printf("Sealed: %s\n", yellow("No"))
-		fmt.Println("  Run 'rememory seal' to encrypt and split the passphrase")
+		fmt.Fprintf(textOut, "Sealed: %s\n", yellow("No"))
+		fmt.Fprintln(textOut, "  Run 'rememory seal' to encrypt and split the passphrase")
+	}
+
+	// Seal history
+	for i, s := range p.Seals {
+		result.Seals = append(result.Seals, statusSeal{
+			Seal:             i + 1,
+			At:               s.At,
+			Current:          s.Current,
+			SupersededAt:     p.SupersededAt(i),
+			ManifestChecksum: s.ManifestChecksum,
+			Threshold:        s.Threshold,
+			Holders:          s.Holders(),
+			Version:          s.Version,
+			RecoveryURL:      s.RecoveryURL,
+		})
+	}
+	if len(p.Seals) > 1 {
+		fmt.Fprintln(textOut, "\nSeal history:")
+		for _, s := range result.Seals {
+			state := "current"
+			if s.SupersededAt != nil {
+				state = "superseded " + s.SupersededAt.Format("2006-01-02")
+			}
+			fmt.Fprintf(textOut, "  #%d %s  %s (%s)\n", s.Seal, s.At.Format("2006-01-02"), strings.Join(s.Holders, ", "), state)
+		}
 	}
 
 	// Threshold
-	fmt.Printf("\nThreshold: %d of %d\n", p.Threshold, len(p.Friends))
+	fmt.Fprintf(textOut, "\nThreshold: %d of %d\n", p.Threshold, len(p.Friends))
 
 	// Friends
-	fmt.Println("\nShare holders:")
+	fmt.Fprintln(textOut, "\nShare holders:")
 	for i, friend := range p.Friends {
 		shareExists := checkShareExists(p, friend)
+		var delivery *project.Delivery
+		if sealed != nil {
+			delivery = sealed.Delivery(friend.Name)
+		}
+		result.Friends = append(result.Friends, statusFriend{
+			Name:        friend.Name,
+			Contact:     friend.Contact,
+			Language:    friend.Language,
+			ShareExists: shareExists,
+			Delivery:    delivery,
+		})
+
 		status := green("✓")
-		if !shareExists {
+		shareExpected := sealed == nil || !sealed.Ephemeral
+		if !shareExists && shareExpected && (delivery == nil || delivery.ShareWipedAt == nil) {
 			status = yellow("○")
 		}
 		contactInfo := friend.Contact
 		if contactInfo == "" {
 			contactInfo = "no contact info"
 		}
-		fmt.Printf("  %d. %s %s (%s)\n", i+1, status, friend.Name, contactInfo)
+		fmt.Fprintf(textOut, "  %d. %s %s (%s)%s\n", i+1, status, friend.Name, contactInfo, describeDelivery(delivery))
+	}
+
+	// Outstanding deliveries
+	if sealed != nil {
+		var undelivered, unconfirmed []string
+		for _, f := range result.Friends {
+			switch {
+			case f.Delivery == nil:
+				undelivered = append(undelivered, f.Name)
+			case f.Delivery.AckedAt == nil:
+				unconfirmed = append(unconfirmed, f.Name)
+			}
+		}
+		if len(undelivered) > 0 {
+			fmt.Fprintf(textOut, "  %s %s\n", yellow("Not delivered:"), strings.Join(undelivered, ", "))
+		}
+		if len(unconfirmed) > 0 {
+			fmt.Fprintf(textOut, "  %s %s\n", yellow("Awaiting confirmation:"), strings.Join(unconfirmed, ", "))
+		}
 	}
 
 	// Bundles status
 	bundlesDir := filepath.Join(p.OutputPath(), "bundles")
 	bundleCount := countBundles(bundlesDir)
-	fmt.Println()
+	result.Bundles = statusBundles{Count: bundleCount, Dir: bundlesDir}
+	fmt.Fprintln(textOut)
 	if bundleCount > 0 {
-		fmt.Printf("Bundles: %s (%d bundles in %s)\n", green("Generated"), bundleCount, bundlesDir)
-	} else if p.Sealed != nil {
-		fmt.Printf("Bundles: %s\n", yellow("Not yet generated"))
-		fmt.Println("  Run 'rememory bundle' to create distribution bundles")
+		fmt.Fprintf(textOut, "Bundles: %s (%d bundles in %s)\n", green("Generated"), bundleCount, bundlesDir)
+	} else if sealed != nil && sealed.Ephemeral {
+		fmt.Fprintln(textOut, "Bundles: written to the destinations chosen at seal time (ephemeral seal)")
+	} else if sealed != nil {
+		fmt.Fprintf(textOut, "Bundles: %s\n", yellow("Not yet generated"))
+		fmt.Fprintln(textOut, "  Run 'rememory bundle' to create distribution bundles")
+	} else {
+		fmt.Fprintf(textOut, "Bundles: %s (seal first)\n", yellow("Not available"))
+	}
+
+	// Last recovery drill
+	result.LastDrill = p.LastDrill()
+	fmt.Fprintln(textOut)
+	if d := result.LastDrill; d != nil {
+		ago := formatDuration(time.Since(d.At))
+		if ago != "just now" {
+			ago += " ago"
+		}
+		when := fmt.Sprintf("%s (%s)", d.At.Format("2006-01-02"), ago)
+		if d.CompletedAt != nil {
+			fmt.Fprintf(textOut, "Last drill: %s — recovered by %s\n", when, strings.Join(d.Participants, ", "))
+		} else {
+			fmt.Fprintf(textOut, "Last drill: %s — %s\n", when, yellow("not completed yet"))
+			fmt.Fprintln(textOut, "  Run 'rememory drill complete' once friends have recovered the practice files")
+		}
 	} else {
-		fmt.Printf("Bundles: %s (seal first)\n", yellow("Not available"))
+		fmt.Fprintln(textOut, "Last drill: never")
+		fmt.Fprintln(textOut, "  Run 'rememory drill' to rehearse recovery with practice bundles")
+	}
+
+	// Last proof-of-possession check
+	if sealed != nil {
+		result.LastChallenge = p.LastChallenge()
+		if r := result.LastChallenge; r != nil {
+			var proved, waiting, outdated []string
+			for _, e := range r.Holders {
+				switch {
+				case e.ProvedAt != nil:
+					proved = append(proved, e.Friend)
+				case e.OutdatedSeal > 0:
+					outdated = append(outdated, fmt.Sprintf("%s (seal #%d)", e.Friend, e.OutdatedSeal))
+				default:
+					waiting = append(waiting, e.Friend)
+				}
+			}
+			fmt.Fprintf(textOut, "Last challenge: %s (seal #%d) — %d of %d proved they have their share\n", r.At.Format("2006-01-02"), r.Seal, len(proved), len(r.Holders))
+			if len(outdated) > 0 {
+				fmt.Fprintf(textOut, "  %s %s\n", yellow("Holding an old bundle:"), strings.Join(outdated, ", "))
+			}
+			if len(waiting) > 0 {
+				fmt.Fprintf(textOut, "  Waiting for: %s\n", strings.Join(waiting, ", "))
+			}
+		} else {
+			fmt.Fprintln(textOut, "Last challenge: never")
+			fmt.Fprintln(textOut, "  Run 'rememory challenge' to check friends still have their bundle")
+		}
 	}
 
 	// Rotation reminder
-	if p.Sealed != nil {
-		age := time.Since(p.Sealed.At)
-		fmt.Println()
-		if age > 2*365*24*time.Hour { // 2 years
-			fmt.Printf("Rotation: %s\n", yellow("Consider rotating - sealed over 2 years ago"))
+	if sealed != nil {
+		age := time.Since(sealed.At)
+		fmt.Fprintln(textOut)
+		if age > rotationAge {
+			fmt.Fprintf(textOut, "Rotation: %s\n", yellow("Consider rotating - sealed over 2 years ago"))
 		} else if age > 365*24*time.Hour { // 1 year
-			fmt.Printf("Rotation: Last sealed %s ago\n", formatDuration(age))
+			fmt.Fprintf(textOut, "Rotation: Last sealed %s ago\n", formatDuration(age))
 		} else {
-			fmt.Printf("Rotation: Last sealed %s ago (consider rotating every 2-3 years)\n", formatDuration(age))
+			fmt.Fprintf(textOut, "Rotation: Last sealed %s ago (consider rotating every 2-3 years)\n", formatDuration(age))
 		}
 	}
 
+	if isJSON() {
+		return printJSON(result)
+	}
 	return nil
 }
 
+// describeDelivery summarizes a friend's delivery for the share holder list.
+func describeDelivery(d *project.Delivery) string {
+	if d == nil {
+		return ""
+	}
+	s := fmt.Sprintf(" — delivered %s via %s (%s)", d.DeliveredAt.Format("2006-01-02"), d.Method, d.Format)
+	if d.AckedAt != nil {
+		s += ", confirmed"
+	}
+	if d.ShareWipedAt != nil {
+		s += ", share wiped"
+	}
+	return s
+}
+
 func checkShareExists(p *project.Project, friend project.Friend) bool {
 	sharesDir := p.SharesPath()
 	filename := fmt.Sprintf("SHARE-%s.txt", core.SanitizeFilename(friend.Name))
@@ -1,17 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"math/big"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/eljojo/rememory/internal/core"
 	"github.com/eljojo/rememory/internal/crypto"
+	"github.com/eljojo/rememory/internal/manifest"
 	"github.com/eljojo/rememory/internal/project"
 	"github.com/spf13/cobra"
 )
 
 var verifyCmd = &cobra.Command{
-	Use:   "verify",
+	Use:   "verify [bundle.zip...]",
 	Short: "Verify the integrity of sealed files",
 	Long: `Verify checks that the encrypted manifest and share files match
 the checksums stored in project.yml.
@@ -20,92 +29,432 @@ Run this command inside a project directory to verify:
   - MANIFEST.age exists and matches its checksum
   - All share files exist and match their checksums
 
-This helps detect if files have been corrupted or modified.`,
+This helps detect if files have been corrupted or modified. Share files
+wiped after delivery ('rememory ack --wipe-share') are not a failure.
+
+With --deep, verify also proves the project actually recovers:
+  - Every share file on disk parses and matches its own checksum
+  - Every combination of threshold shares reconstructs the passphrase
+    (a random sample is used when there are too many combinations)
+  - The passphrase decrypts MANIFEST.age
+  - The decrypted files match the current manifest/ directory
+
+If the shares aren't on disk (an ephemeral seal, or shares wiped after
+delivery), pass bundles from the current seal for --deep to use:
+
+  rememory verify --deep /media/usb1/bundle-alice.zip /media/usb2/bundle-bob.zip`,
 	RunE: runVerify,
 }
 
+// maxDeepCombinations caps how many share combinations --deep tries.
+// Projects with more combinations are checked with a random sample.
+const maxDeepCombinations = 500
+
 func init() {
+	verifyCmd.Flags().Bool("deep", false, "Also reconstruct the passphrase from share combinations and decrypt the manifest")
 	rootCmd.AddCommand(verifyCmd)
 }
 
+// verifyResult is the JSON output of the verify command.
+type verifyResult struct {
+	OK        bool               `json:"ok"`
+	Ephemeral bool               `json:"ephemeral,omitempty"` // no share files were kept, so none were checked
+	Files     []verifyFileResult `json:"files"`
+	Deep      *deepResult        `json:"deep,omitempty"`
+	// Transcript is the signature check of a reshared seal's transcript: "ok" or "invalid"
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// verifyFileResult is the outcome of checking one sealed file.
+type verifyFileResult struct {
+	File     string `json:"file"`
+	Status   string `json:"status"` // "ok", "missing", "wiped", "mismatch", "outdated" or "error"
+	Expected string `json:"expected"`
+	Actual   string `json:"actual,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// For outdated files: the earlier seal the file belongs to
+	Seal         int        `json:"seal,omitempty"`
+	SupersededAt *time.Time `json:"superseded_at,omitempty"`
+}
+
+// deepResult is the outcome of verify --deep.
+type deepResult struct {
+	OK                 bool              `json:"ok"`
+	Threshold          int               `json:"threshold"`
+	ValidShares        int               `json:"valid_shares"`
+	Combinations       int               `json:"combinations_checked"`
+	TotalCombinations  string            `json:"combinations_total"`
+	FailedCombinations [][]string        `json:"failed_combinations"`
+	ShareErrors        []string          `json:"share_errors,omitempty"` // share files that couldn't be used, and why
+	Decrypted          bool              `json:"decrypted"`
+	SealedFiles        int               `json:"sealed_files"`
+	Changes            *manifest.Changes `json:"changes,omitempty"`
+	Error              string            `json:"error,omitempty"`
+}
+
 func runVerify(cmd *cobra.Command, args []string) error {
-	// Find and load the project
-	cwd, err := os.Getwd()
+	p, err := loadSealedProject()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return err
 	}
 
-	projectDir, err := project.FindProjectDir(cwd)
+	deep, _ := cmd.Flags().GetBool("deep")
+	if len(args) > 0 && !deep {
+		return newError(CodeUsage, "bundles can only be passed with --deep")
+	}
+	bundleShares, err := sharesFromBundles(p, args)
 	if err != nil {
 		return err
 	}
 
-	p, err := project.Load(projectDir)
+	// Verify manifest file, then share files
+	sealed := p.CurrentSeal()
+	result := verifyResult{OK: true, Ephemeral: sealed.Ephemeral}
+	result.Files = append(result.Files, checkSealedFile(p, p.ManifestAgePath(), sealed.ManifestChecksum))
+	for _, shareInfo := range sealed.Shares {
+		if shareInfo.File == "" {
+			continue
+		}
+		f := checkSealedFile(p, filepath.Join(p.Path, shareInfo.File), shareInfo.Checksum)
+		if d := sealed.Delivery(shareInfo.Friend); f.Status == "missing" && d != nil && d.ShareWipedAt != nil {
+			f.Status = "wiped"
+		}
+		result.Files = append(result.Files, f)
+	}
+
+	for _, f := range result.Files {
+		fmt.Fprintf(textOut, "Checking %s... ", f.File)
+		switch f.Status {
+		case "ok":
+			fmt.Fprintln(textOut, "OK")
+		case "missing":
+			fmt.Fprintln(textOut, "MISSING")
+		case "wiped":
+			fmt.Fprintln(textOut, "WIPED (after delivery)")
+		case "outdated":
+			fmt.Fprintf(textOut, "OUTDATED (from seal #%d, superseded on %s)\n", f.Seal, f.SupersededAt.Format("2006-01-02"))
+		case "mismatch":
+			fmt.Fprintln(textOut, "CHECKSUM MISMATCH")
+			fmt.Fprintf(textOut, "  Expected: %s\n", f.Expected)
+			fmt.Fprintf(textOut, "  Got:      %s\n", f.Actual)
+		default:
+			fmt.Fprintf(textOut, "ERROR: %s\n", f.Error)
+		}
+		if f.Status != "ok" && f.Status != "wiped" {
+			result.OK = false
+		}
+	}
+	if sealed.Ephemeral {
+		fmt.Fprintln(textOut, "Shares: not kept on disk (ephemeral seal)")
+	}
+	if sealed.Reshare != nil {
+		fmt.Fprint(textOut, "Checking reshare transcript... ")
+		if err := sealed.Reshare.Verify(); err != nil {
+			result.Transcript = "invalid"
+			result.OK = false
+			fmt.Fprintf(textOut, "INVALID (%v)\n", err)
+		} else {
+			result.Transcript = "ok"
+			fmt.Fprintln(textOut, "OK")
+		}
+	}
+
+	if deep {
+		fmt.Fprintln(textOut)
+		fmt.Fprintln(textOut, "Deep verification:")
+		result.Deep = verifyDeep(p, bundleShares)
+		if !result.Deep.OK {
+			result.OK = false
+		}
+	}
+
+	if isJSON() {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(textOut)
+	if result.OK {
+		fmt.Fprintln(textOut, "All files verified.")
+		return nil
+	}
+
+	return &Error{Code: CodeVerificationFailed, Err: errors.New("verification failed"), Reported: isJSON()}
+}
+
+// checkSealedFile compares a file's checksum with the one recorded at seal time.
+// A file that doesn't match but belongs to an earlier seal is reported as outdated.
+func checkSealedFile(p *project.Project, path, expected string) verifyFileResult {
+	result := verifyFileResult{File: filepath.Base(path), Expected: expected}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		result.Status = "missing"
+		return result
+	}
+
+	checksum, err := crypto.HashFile(path)
 	if err != nil {
-		return fmt.Errorf("loading project: %w", err)
+		result.Status = "error"
+		result.Error = err.Error()
+		return result
 	}
 
-	if p.Sealed == nil {
-		return fmt.Errorf("project has not been sealed yet; run 'rememory seal' first")
+	result.Actual = checksum
+	if checksum == expected {
+		result.Status = "ok"
+		return result
 	}
+	result.Status = "mismatch"
+	if i := p.FindSeal(checksum); i >= 0 && p.SupersededAt(i) != nil {
+		result.Status = "outdated"
+		result.Seal = i + 1
+		result.SupersededAt = p.SupersededAt(i)
+	}
+	return result
+}
 
-	allOK := true
+// verifyDeep reconstructs the passphrase from combinations of the project's
+// shares, decrypts MANIFEST.age, and compares the result with manifest/.
+// Shares read from bundles (nil entries are skipped) are used alongside
+// the share files. Drift between the sealed and current manifest is
+// reported but is not a failure.
+func verifyDeep(p *project.Project, bundleShares []*core.Share) *deepResult {
+	result := &deepResult{FailedCombinations: [][]string{}}
 
-	// Verify manifest file
-	manifestPath := p.ManifestAgePath()
-	fmt.Printf("Checking %s... ", filepath.Base(manifestPath))
+	// Load every share that can still be read
+	sealed := p.CurrentSeal()
+	var shares []*core.Share
+	seen := make(map[int]bool)
+	for _, share := range bundleShares {
+		if share != nil {
+			shares = append(shares, share)
+			seen[share.Index] = true
+		}
+	}
+	for _, shareInfo := range sealed.Shares {
+		if shareInfo.File == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(p.Path, shareInfo.File))
+		if d := sealed.Delivery(shareInfo.Friend); os.IsNotExist(err) && d != nil && d.ShareWipedAt != nil {
+			continue
+		}
+		var share *core.Share
+		if err == nil {
+			share, err = core.ParseShare(content)
+		}
+		if err == nil {
+			err = share.Verify()
+		}
+		if err != nil {
+			result.ShareErrors = append(result.ShareErrors, fmt.Sprintf("%s: %v", filepath.Base(shareInfo.File), err))
+			continue
+		}
+		if !seen[share.Index] {
+			shares = append(shares, share)
+		}
+	}
+	if len(result.ShareErrors) > 0 {
+		fmt.Fprintln(textOut, "Reading share files... FAILED")
+		for _, e := range result.ShareErrors {
+			fmt.Fprintf(textOut, "  %s\n", red("✗ "+e))
+		}
+	}
+
+	result.Threshold = p.Threshold
+	if len(shares) > 0 {
+		result.Threshold = shares[0].Threshold
+	}
+	result.ValidShares = len(shares)
+
+	fmt.Fprint(textOut, "Checking share combinations... ")
+	if len(shares) < result.Threshold {
+		result.Error = fmt.Sprintf("only %d valid shares found, need %d", len(shares), result.Threshold)
+		fmt.Fprintln(textOut, "FAILED")
+		fmt.Fprintf(textOut, "  Only %d valid shares found, need %d\n", len(shares), result.Threshold)
+		if sealed.Ephemeral {
+			fmt.Fprintln(textOut, "  This seal kept no share files. Pass bundles: rememory verify --deep bundle-alice.zip ...")
+		}
+		return result
+	}
+
+	subsets, total := shareCombinations(len(shares), result.Threshold, maxDeepCombinations)
+	result.Combinations = len(subsets)
+	result.TotalCombinations = total.String()
+
+	var passphrase *core.Secret
+	defer func() { passphrase.Wipe() }()
+	for _, subset := range subsets {
+		data := make([][]byte, len(subset))
+		for i, idx := range subset {
+			data[i] = shares[idx].Data
+		}
+		recovered, err := core.Combine(data)
+		if err == nil {
+			candidate := core.RecoverSecret(recovered, shares[subset[0]].Version)
+			core.Wipe(recovered)
+			if core.VerifyHash(core.HashBytes(candidate.Bytes()), sealed.VerificationHash) {
+				passphrase.Wipe()
+				passphrase = candidate
+				continue
+			}
+			candidate.Wipe()
+		}
+		result.FailedCombinations = append(result.FailedCombinations, shareHolders(shares, subset))
+	}
 
-	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
-		fmt.Println("MISSING")
-		allOK = false
+	if len(result.FailedCombinations) > 0 {
+		fmt.Fprintln(textOut, "FAILED")
+		for _, holders := range result.FailedCombinations {
+			fmt.Fprintf(textOut, "  %s\n", red("✗ "+strings.Join(holders, " + ")))
+		}
+		return result
+	}
+	if total.IsInt64() && int64(len(subsets)) == total.Int64() {
+		fmt.Fprintf(textOut, "OK (all %d combinations of %d shares)\n", len(subsets), result.Threshold)
 	} else {
-		checksum, err := crypto.HashFile(manifestPath)
-		if err != nil {
-			fmt.Printf("ERROR: %v\n", err)
-			allOK = false
-		} else if checksum != p.Sealed.ManifestChecksum {
-			fmt.Println("CHECKSUM MISMATCH")
-			fmt.Printf("  Expected: %s\n", p.Sealed.ManifestChecksum)
-			fmt.Printf("  Got:      %s\n", checksum)
-			allOK = false
-		} else {
-			fmt.Println("OK")
+		fmt.Fprintf(textOut, "OK (%d sampled of %s combinations of %d shares)\n", len(subsets), total.String(), result.Threshold)
+	}
+
+	fmt.Fprintf(textOut, "Decrypting %s... ", filepath.Base(p.ManifestAgePath()))
+	sealedFiles, err := decryptAndIndex(p.ManifestAgePath(), passphrase)
+	if err != nil {
+		result.Error = err.Error()
+		fmt.Fprintln(textOut, "FAILED")
+		fmt.Fprintf(textOut, "  %v\n", err)
+		return result
+	}
+	result.Decrypted = true
+	result.SealedFiles = len(sealedFiles)
+	fmt.Fprintf(textOut, "OK (%d files)\n", len(sealedFiles))
+
+	// A valid signature only proves the transcript is intact; this proves it
+	// was signed by someone holding the passphrase.
+	if sealed.Reshare != nil {
+		fmt.Fprint(textOut, "Checking reshare transcript key... ")
+		if sealed.Reshare.PublicKey != core.TranscriptPublicKey(passphrase) {
+			result.Error = "reshare transcript wasn't signed with this project's passphrase"
+			fmt.Fprintln(textOut, "FAILED")
+			fmt.Fprintf(textOut, "  %s\n", result.Error)
+			return result
 		}
+		fmt.Fprintln(textOut, "OK")
 	}
+	// The seal recovers, but a damaged share file on disk still fails it
+	result.OK = len(result.ShareErrors) == 0
 
-	// Verify share files
-	for _, shareInfo := range p.Sealed.Shares {
-		sharePath := filepath.Join(p.Path, shareInfo.File)
-		fmt.Printf("Checking %s... ", filepath.Base(sharePath))
+	// Reshared projects created with --dir have no manifest/ to compare with
+	if _, err := os.Stat(p.ManifestPath()); os.IsNotExist(err) {
+		return result
+	}
 
-		if _, err := os.Stat(sharePath); os.IsNotExist(err) {
-			fmt.Println("MISSING")
-			allOK = false
-			continue
+	fmt.Fprint(textOut, "Comparing with manifest/... ")
+	currentFiles, err := manifest.HashDir(p.ManifestPath())
+	if err != nil {
+		fmt.Fprintln(textOut, "ERROR")
+		fmt.Fprintf(textOut, "  %v\n", err)
+		return result
+	}
+	changes := manifest.Compare(sealedFiles, currentFiles)
+	result.Changes = &changes
+	if changes.IsEmpty() {
+		fmt.Fprintln(textOut, "OK")
+		return result
+	}
+
+	fmt.Fprintln(textOut, yellow("CHANGED"))
+	printChanges(changes)
+	fmt.Fprintln(textOut, "  The bundles still recover, but they hold an older manifest. Run 'rememory seal' to update them.")
+	return result
+}
+
+// decryptAndIndex decrypts an encrypted manifest and indexes the files inside.
+func decryptAndIndex(path string, passphrase *core.Secret) ([]manifest.FileHash, error) {
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var decrypted bytes.Buffer
+	if err := core.DecryptSecret(&decrypted, bytes.NewReader(encrypted), passphrase); err != nil {
+		return nil, err
+	}
+	return manifest.HashArchive(&decrypted)
+}
+
+// printChanges lists added, removed and modified manifest files.
+func printChanges(c manifest.Changes) {
+	for _, path := range c.Added {
+		fmt.Fprintf(textOut, "  %s %s\n", green("+"), path)
+	}
+	for _, path := range c.Removed {
+		fmt.Fprintf(textOut, "  %s %s\n", red("-"), path)
+	}
+	for _, path := range c.Modified {
+		fmt.Fprintf(textOut, "  %s %s\n", yellow("~"), path)
+	}
+}
+
+// shareHolders returns the holders of a subset of shares.
+func shareHolders(shares []*core.Share, subset []int) []string {
+	names := make([]string, len(subset))
+	for i, idx := range subset {
+		names[i] = shares[idx].Holder
+		if names[i] == "" {
+			names[i] = fmt.Sprintf("share %d", shares[idx].Index)
 		}
+	}
+	return names
+}
 
-		checksum, err := crypto.HashFile(sharePath)
-		if err != nil {
-			fmt.Printf("ERROR: %v\n", err)
-			allOK = false
-			continue
+// shareCombinations returns k-sized subsets of the indices 0..n-1, along with
+// the total number of possible subsets. If there are more than limit subsets,
+// a random sample of limit subsets is returned instead; the sample always
+// includes every index at least once.
+func shareCombinations(n, k, limit int) ([][]int, *big.Int) {
+	total := new(big.Int).Binomial(int64(n), int64(k))
+
+	if total.Cmp(big.NewInt(int64(limit))) <= 0 {
+		var subsets [][]int
+		subset := make([]int, k)
+		var walk func(start, depth int)
+		walk = func(start, depth int) {
+			if depth == k {
+				subsets = append(subsets, append([]int(nil), subset...))
+				return
+			}
+			for i := start; i <= n-(k-depth); i++ {
+				subset[depth] = i
+				walk(i+1, depth+1)
+			}
 		}
+		walk(0, 0)
+		return subsets, total
+	}
 
-		if checksum != shareInfo.Checksum {
-			fmt.Println("CHECKSUM MISMATCH")
-			fmt.Printf("  Expected: %s\n", shareInfo.Checksum)
-			fmt.Printf("  Got:      %s\n", checksum)
-			allOK = false
-		} else {
-			fmt.Println("OK")
+	seen := make(map[string]bool)
+	var subsets [][]int
+	add := func(subset []int) {
+		sort.Ints(subset)
+		key := fmt.Sprint(subset)
+		if !seen[key] {
+			seen[key] = true
+			subsets = append(subsets, subset)
 		}
 	}
 
-	fmt.Println()
-	if allOK {
-		fmt.Println("All files verified.")
-		return nil
+	// Cover every share: walk a random permutation in chunks of k
+	perm := rand.Perm(n)
+	for start := 0; start < n; start += k {
+		subset := make([]int, 0, k)
+		for i := 0; i < k; i++ {
+			subset = append(subset, perm[(start+i)%n])
+		}
+		add(subset)
 	}
 
-	return fmt.Errorf("verification failed")
+	for len(subsets) < limit {
+		add(rand.Perm(n)[:k])
+	}
+	return subsets, total
 }
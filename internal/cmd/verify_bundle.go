@@ -1,41 +1,456 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/project"
+	"github.com/eljojo/rememory/pkg/rememory"
 	"github.com/spf13/cobra"
 )
 
 var verifyBundleCmd = &cobra.Command{
-	Use:   "verify-bundle <bundle.zip>",
-	Short: "Verify the integrity of a bundle ZIP file",
+	Use:   "verify-bundle <bundle.zip>...",
+	Short: "Verify the integrity of bundle ZIP files",
 	Long: `Verify-bundle checks that a distribution bundle is valid and intact.
 
 This command verifies:
   - All required files are present (README.txt, README.pdf, MANIFEST.age, recover.html)
   - Checksums match the values embedded in README.txt
   - The embedded share is valid and parseable
+  - If the bundle is signed, the signature covers every file in it
+  - recover.html is the one an official release makes (see verify-html)
+
+The checksums in README.txt travel in the same ZIP as the files they check,
+so they catch damage but not deliberate tampering. A signed bundle can only
+be made with the owner's key: pass the owner's public key (a .pub file,
+"ssh-ed25519 AAAA..." or its SHA256: fingerprint) with --pubkey to require
+that the bundle was signed with it.
+
+Given several bundles, or a directory with --all, it also checks that they
+belong together: the same MANIFEST.age, a different share in each, the same
+threshold, total and share version, and the same signing key. Inside a
+project, each holder must match the seal in project.yml.
+
+  rememory verify-bundle --all output/bundles/
+  rememory verify-bundle alice.zip bob.zip camila.zip
 
 Use this to verify bundles before distributing them, or to check bundles
 you've received from others.`,
-	Args: cobra.ExactArgs(1),
 	RunE: runVerifyBundle,
 }
 
 func init() {
+	verifyBundleCmd.Flags().String("pubkey", "", "Require a signature by this key: a .pub file, a public key, or a SHA256: fingerprint")
+	verifyBundleCmd.Flags().String("all", "", "Verify every bundle ZIP in this directory, and that they belong together")
 	rootCmd.AddCommand(verifyBundleCmd)
 }
 
+// verifyBundleResult is the JSON output of the verify-bundle command.
+type verifyBundleResult struct {
+	OK       bool   `json:"ok"`
+	Bundle   string `json:"bundle"`
+	Error    string `json:"error,omitempty"`
+	SignedBy string `json:"signed_by,omitempty"` // fingerprint of the key that signed the bundle
+	// RecoverHTML tells whether its recover.html is an official release's
+	RecoverHTML *html.Provenance `json:"recover_html,omitempty"`
+	// RepairedBlocks counts the damaged blocks of MANIFEST.age that its
+	// parity data had to rebuild
+	RepairedBlocks int `json:"repaired_blocks,omitempty"`
+	// Share is set when verifying several bundles
+	Share *bundleShare `json:"share,omitempty"`
+}
+
+// bundleShare is what verify-bundle shows of a bundle's share.
+type bundleShare struct {
+	Index     int    `json:"index"`
+	Total     int    `json:"total"`
+	Threshold int    `json:"threshold"`
+	Version   int    `json:"version"`
+	Holder    string `json:"holder,omitempty"`
+}
+
 func runVerifyBundle(cmd *cobra.Command, args []string) error {
-	bundlePath := args[0]
+	var want string
+	if value, _ := cmd.Flags().GetString("pubkey"); value != "" {
+		var err error
+		if want, err = readPublicKey(value); err != nil {
+			return newError(CodeUsage, "--pubkey: %v", err)
+		}
+	}
+
+	paths := args
+	dir, _ := cmd.Flags().GetString("all")
+	if dir != "" {
+		found, err := filepath.Glob(filepath.Join(dir, "*.zip"))
+		if err != nil || len(found) == 0 {
+			return newError(CodeUsage, "no bundle ZIPs found in %s", dir)
+		}
+		sort.Strings(found)
+		paths = append(paths, found...)
+	}
+	switch {
+	case len(paths) == 0:
+		return newError(CodeUsage, "give a bundle ZIP to verify, or a directory of them with --all")
+	case len(paths) > 1 || dir != "":
+		return verifyBundleSet(paths, want)
+	}
+
+	bundlePath := paths[0]
+	result := verifyBundleResult{Bundle: bundlePath}
+	fmt.Fprintf(textOut, "Verifying bundle: %s\n", bundlePath)
+
+	verifyErr := verifyBundleFile(bundlePath, &result)
+	if verifyErr == nil {
+		verifyErr = checkBundleOrigin(result.SignedBy, result.RecoverHTML, want)
+	}
+	result.OK = verifyErr == nil
+	if verifyErr != nil {
+		result.Error = verifyErr.Error()
+	}
+
+	if isJSON() {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	}
+
+	if verifyErr != nil {
+		return &Error{Code: CodeVerificationFailed, Err: fmt.Errorf("verification failed: %w", verifyErr), Reported: isJSON()}
+	}
+
+	if result.SignedBy != "" {
+		fmt.Fprintf(textOut, "Signed by %s\n", result.SignedBy)
+	}
+	fmt.Fprintf(textOut, "recover.html: %s\n", describeProvenance(result.RecoverHTML))
+	if result.RepairedBlocks > 0 {
+		fmt.Fprintln(textOut, describeRepair(result.RepairedBlocks))
+	}
+	fmt.Fprintln(textOut, "Bundle verified successfully.")
+	return nil
+}
+
+// checkBundleOrigin fails a verified bundle whose recover.html claims a
+// release it doesn't match, or that isn't signed by want (if given).
+func checkBundleOrigin(signedBy string, prov *html.Provenance, want string) error {
+	if prov != nil && prov.Origin == html.OriginModified {
+		return fmt.Errorf("recover.html claims to be from ReMemory %s, but doesn't match that release", prov.Claimed)
+	}
+	switch {
+	case want == "":
+	case signedBy == "":
+		return fmt.Errorf("bundle isn't signed, expected a signature by %s", want)
+	case signedBy != want:
+		return fmt.Errorf("bundle is signed by %s, not by %s: %w", signedBy, want, rememory.ErrBadSignature)
+	}
+	return nil
+}
+
+// verifyBundleFile checks the bundle ZIP at path with
+// rememory.VerifyBundleSignature, and its recover.html against the official
+// releases. It fills in what it finds in result.
+func verifyBundleFile(path string, result *verifyBundleResult) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("opening bundle: %w", err)
+	}
+	sig, err := rememory.VerifyBundleSignature(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	if sig != nil {
+		result.SignedBy = sig.Fingerprint()
+	}
+	if _, result.RepairedBlocks, err = rememory.RepairManifest(data); err != nil {
+		return err
+	}
+	result.RecoverHTML, err = bundle.RecoverProvenance(bytes.NewReader(data), int64(len(data)))
+	return err
+}
+
+// describeRepair warns that a bundle's MANIFEST.age was damaged.
+func describeRepair(blocks int) string {
+	return yellow(fmt.Sprintf("MANIFEST.age: %d damaged block%s repaired from its parity data; replace this copy of the bundle", blocks, plural(blocks)))
+}
+
+// verifyBundlesResult is the JSON output of verify-bundle with several bundles.
+type verifyBundlesResult struct {
+	OK      bool                 `json:"ok"`
+	Bundles []verifyBundleResult `json:"bundles"`
+	Checks  []bundleSetCheck     `json:"checks"`
+}
+
+// bundleSetCheck is one check that bundles belong together.
+type bundleSetCheck struct {
+	Name   string `json:"name"` // manifest, shares, parameters, signer or holders
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// bundleSetEntry is a verified bundle and what was read from it.
+type bundleSetEntry struct {
+	Path             string
+	Share            *core.Share
+	ManifestChecksum string
+	SignedBy         string
+}
+
+func verifyBundleSet(paths []string, want string) error {
+	fmt.Fprintf(textOut, "Verifying %d bundles...\n\n", len(paths))
+
+	result := verifyBundlesResult{OK: true}
+	var entries []bundleSetEntry
+	for _, path := range paths {
+		r, entry := inspectBundleFile(path, want)
+		result.Bundles = append(result.Bundles, r)
+		if !r.OK {
+			result.OK = false
+			fmt.Fprintf(textOut, "  %s %s: %s\n", red("✗"), filepath.Base(path), r.Error)
+			continue
+		}
+		entries = append(entries, *entry)
+		line := fmt.Sprintf("share %d of %d", r.Share.Index, r.Share.Total)
+		if r.Share.Holder != "" {
+			line += ", " + r.Share.Holder
+		}
+		if r.SignedBy != "" {
+			line += ", signed by " + r.SignedBy
+		}
+		fmt.Fprintf(textOut, "  %s %s (%s)\n", green("✓"), filepath.Base(path), line)
+		if r.RepairedBlocks > 0 {
+			fmt.Fprintf(textOut, "      %s\n", describeRepair(r.RepairedBlocks))
+		}
+	}
 
-	fmt.Printf("Verifying bundle: %s\n", bundlePath)
+	var p *project.Project
+	if loaded, err := loadProject(); err == nil && len(loaded.Seals) > 0 {
+		p = loaded
+	}
+	result.Checks = checkBundleSet(entries, p)
 
-	if err := bundle.VerifyBundle(bundlePath); err != nil {
-		return fmt.Errorf("verification failed: %w", err)
+	fmt.Fprintln(textOut, "\nConsistency:")
+	for _, c := range result.Checks {
+		if c.OK {
+			fmt.Fprintf(textOut, "  %s %s\n", green("✓"), c.Detail)
+		} else {
+			result.OK = false
+			fmt.Fprintf(textOut, "  %s %s\n", red("✗"), c.Detail)
+		}
 	}
 
-	fmt.Println("Bundle verified successfully.")
+	if isJSON() {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	}
+	if !result.OK {
+		return &Error{Code: CodeVerificationFailed, Err: fmt.Errorf("verification failed: the bundles have problems"), Reported: isJSON()}
+	}
+	fmt.Fprintf(textOut, "\nAll %d bundles verified.\n", len(paths))
 	return nil
 }
+
+// inspectBundleFile verifies one of several bundles, and reads its share.
+func inspectBundleFile(path, want string) (verifyBundleResult, *bundleSetEntry) {
+	r := verifyBundleResult{Bundle: path}
+	fail := func(err error) (verifyBundleResult, *bundleSetEntry) {
+		r.Error = err.Error()
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fail(fmt.Errorf("opening bundle: %w", err))
+	}
+	ins, err := bundle.Inspect(data)
+	if err != nil {
+		return fail(err)
+	}
+	if ins.Kind != bundle.KindBundle {
+		return fail(fmt.Errorf("not a bundle ZIP"))
+	}
+	r.SignedBy = ins.SignedBy
+	if ins.HTML != nil {
+		r.RecoverHTML = ins.HTML.Provenance
+	}
+	if !ins.Verified {
+		return fail(errors.New(ins.VerifyError))
+	}
+	if err := checkBundleOrigin(r.SignedBy, r.RecoverHTML, want); err != nil {
+		return fail(err)
+	}
+	if ins.Share == nil || ins.Manifest == nil {
+		return fail(fmt.Errorf("bundle has no share or MANIFEST.age"))
+	}
+
+	s := ins.Share
+	r.OK = true
+	r.RepairedBlocks = ins.ManifestRepaired
+	r.Share = &bundleShare{Index: s.Index, Total: s.Total, Threshold: s.Threshold, Version: s.Version, Holder: s.Holder}
+	return r, &bundleSetEntry{Path: path, Share: s, ManifestChecksum: ins.Manifest.Checksum, SignedBy: ins.SignedBy}
+}
+
+// checkBundleSet checks that verified bundles belong together. With p, the
+// holders are also checked against the seal the bundles come from.
+func checkBundleSet(entries []bundleSetEntry, p *project.Project) []bundleSetCheck {
+	if len(entries) == 0 {
+		return nil
+	}
+	first := entries[0]
+	var checks []bundleSetCheck
+	add := func(name string, ok bool, format string, args ...any) {
+		checks = append(checks, bundleSetCheck{Name: name, OK: ok, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	// Same MANIFEST.age
+	var other []string
+	for _, e := range entries[1:] {
+		if e.ManifestChecksum != first.ManifestChecksum {
+			other = append(other, filepath.Base(e.Path))
+		}
+	}
+	if len(other) == 0 {
+		add("manifest", true, "same MANIFEST.age (%s)", truncateHash(first.ManifestChecksum))
+	} else {
+		add("manifest", false, "%s: different MANIFEST.age than %s", strings.Join(other, ", "), filepath.Base(first.Path))
+	}
+
+	// A different share in each
+	byIndex := make(map[int][]string)
+	var indices []int
+	for _, e := range entries {
+		if byIndex[e.Share.Index] == nil {
+			indices = append(indices, e.Share.Index)
+		}
+		byIndex[e.Share.Index] = append(byIndex[e.Share.Index], filepath.Base(e.Path))
+	}
+	sort.Ints(indices)
+	var dups []string
+	for _, i := range indices {
+		if len(byIndex[i]) > 1 {
+			dups = append(dups, fmt.Sprintf("share %d is in %s", i, strings.Join(byIndex[i], " and ")))
+		}
+	}
+	if len(dups) == 0 {
+		add("shares", true, "%d different shares of %d (%d needed to recover)", len(indices), first.Share.Total, first.Share.Threshold)
+	} else {
+		add("shares", false, "%s", strings.Join(dups, "; "))
+	}
+
+	// Same threshold, total and share version
+	other = nil
+	for _, e := range entries[1:] {
+		s := e.Share
+		if s.Threshold != first.Share.Threshold || s.Total != first.Share.Total || s.Version != first.Share.Version {
+			other = append(other, fmt.Sprintf("%s (%d of %d, v%d)", filepath.Base(e.Path), s.Threshold, s.Total, s.Version))
+		}
+	}
+	if len(other) == 0 {
+		add("parameters", true, "same threshold (%d of %d) and share version (v%d)", first.Share.Threshold, first.Share.Total, first.Share.Version)
+	} else {
+		add("parameters", false, "%s: don't match %s (%d of %d, v%d)", strings.Join(other, ", "), filepath.Base(first.Path), first.Share.Threshold, first.Share.Total, first.Share.Version)
+	}
+
+	// Same signing key, if any are signed
+	signers := make(map[string]bool)
+	for _, e := range entries {
+		signers[e.SignedBy] = true
+	}
+	switch {
+	case len(signers) > 1:
+		add("signer", false, "signed by different keys, or only some are signed")
+	case first.SignedBy != "":
+		add("signer", true, "all signed by %s", first.SignedBy)
+	}
+
+	if p != nil {
+		checks = append(checks, checkBundleHolders(entries, p)...)
+	}
+	return checks
+}
+
+// checkBundleHolders checks that the bundles all come from the current seal of
+// p, and that each share's holder is the friend it was sealed for.
+func checkBundleHolders(entries []bundleSetEntry, p *project.Project) []bundleSetCheck {
+	// A reshare keeps MANIFEST.age, so each bundle's seal is found by its
+	// share, and by its MANIFEST.age only if the share wasn't recorded
+	bySeal := make(map[int][]string)
+	var seals []int
+	for _, e := range entries {
+		i := p.FindSeal(e.Share.Checksum, core.HashBytes([]byte(e.Share.Encode())))
+		if i < 0 {
+			i = p.FindSeal(e.ManifestChecksum)
+		}
+		if i < 0 {
+			return []bundleSetCheck{{Name: "holders", Detail: fmt.Sprintf("%s isn't from any seal of this project", filepath.Base(e.Path))}}
+		}
+		if bySeal[i] == nil {
+			seals = append(seals, i)
+		}
+		bySeal[i] = append(bySeal[i], filepath.Base(e.Path))
+	}
+	if len(seals) > 1 {
+		sort.Ints(seals)
+		var groups []string
+		for _, i := range seals {
+			state := "superseded"
+			if p.Seals[i].Current {
+				state = "current"
+			}
+			groups = append(groups, fmt.Sprintf("%s from seal #%d (%s)", strings.Join(bySeal[i], ", "), i+1, state))
+		}
+		return []bundleSetCheck{{Name: "holders", Detail: "the bundles come from different seals: " + strings.Join(groups, "; ")}}
+	}
+	i := seals[0]
+	seal := p.Seals[i]
+	if !seal.Current {
+		detail := fmt.Sprintf("the bundles are from seal #%d, which is no longer current", i+1)
+		if at := p.SupersededAt(i); at != nil {
+			detail = fmt.Sprintf("the bundles are from seal #%d, superseded on %s", i+1, at.Format("2006-01-02"))
+		}
+		return []bundleSetCheck{{Name: "holders", Detail: detail}}
+	}
+
+	var problems []string
+	for _, e := range entries {
+		idx := e.Share.Index - 1
+		if idx < 0 || idx >= len(seal.Shares) {
+			problems = append(problems, fmt.Sprintf("%s has share %d, but seal #%d has %d", filepath.Base(e.Path), e.Share.Index, i+1, len(seal.Shares)))
+			continue
+		}
+		sealed := seal.Shares[idx]
+		if !strings.EqualFold(e.Share.Holder, sealed.Friend) {
+			problems = append(problems, fmt.Sprintf("%s is for %q, but share %d was sealed for %s", filepath.Base(e.Path), e.Share.Holder, e.Share.Index, sealed.Friend))
+		} else if sealed.ShareChecksum != "" && e.Share.Checksum != sealed.ShareChecksum {
+			problems = append(problems, fmt.Sprintf("%s: %s's share doesn't match the one sealed", filepath.Base(e.Path), sealed.Friend))
+		}
+	}
+	if len(problems) > 0 {
+		return []bundleSetCheck{{Name: "holders", Detail: strings.Join(problems, "; ")}}
+	}
+
+	// Who's missing is only reported: bundles gathered back may be a subset
+	have := make(map[int]bool)
+	for _, e := range entries {
+		have[e.Share.Index] = true
+	}
+	var missing []string
+	for j, si := range seal.Shares {
+		if !have[j+1] {
+			missing = append(missing, si.Friend)
+		}
+	}
+	detail := fmt.Sprintf("holders match seal #%d in project.yml", i+1)
+	if len(missing) > 0 {
+		detail += fmt.Sprintf(" (no bundle from %s)", strings.Join(missing, ", "))
+	}
+	return []bundleSetCheck{{Name: "holders", OK: true, Detail: detail}}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/spf13/cobra"
+)
+
+var verifyHTMLCmd = &cobra.Command{
+	Use:   "verify-html <recover.html|bundle.zip>",
+	Short: "Check that a recover.html comes from an official release",
+	Long: `Verify-html checks that a recover.html is the one an official release of
+ReMemory makes. A modified recover.html could send the pieces it's given
+anywhere, so check it before opening someone else's copy.
+
+The WASM embedded in the page and the rest of the page are hashed, leaving
+out what differs between bundles (the friend's details, the security nonce),
+and compared with the hashes of every release this copy of rememory knows.
+Only releases made since verify-html was added are known: a page from an
+earlier release reports as unknown. A bundle ZIP can be given instead, to
+check its recover.html.
+
+The result is one of:
+  official     matches an official release
+  this-build   matches this copy of rememory (a development or newer build)
+  modified     claims to be from a known release, but doesn't match it
+  unknown      matches no release known here; it may be from a newer release,
+               or one made before verify-html`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerifyHTML,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyHTMLCmd)
+}
+
+// verifyHTMLResult is the JSON output of the verify-html command.
+type verifyHTMLResult struct {
+	OK   bool   `json:"ok"`
+	File string `json:"file"`
+	*html.Provenance
+}
+
+func runVerifyHTML(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var prov *html.Provenance
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		prov, err = bundle.RecoverProvenance(bytes.NewReader(data), int64(len(data)))
+	} else if html.IsRecoverHTML(data) {
+		prov, err = html.CheckRecoverHTML(data)
+	} else {
+		return newError(CodeUsage, "%s is not a recover.html or bundle ZIP", path)
+	}
+	if err != nil {
+		return &Error{Code: CodeVerificationFailed, Err: fmt.Errorf("%s: %w", path, err)}
+	}
+
+	result := verifyHTMLResult{OK: prov.Official(), File: path, Provenance: prov}
+	if isJSON() {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(textOut, "Checking recover.html: %s\n", path)
+		if prov.Claimed != "" {
+			fmt.Fprintf(textOut, "  Claims:     ReMemory %s\n", prov.Claimed)
+		}
+		fmt.Fprintf(textOut, "  WASM:       %s\n", prov.WASM)
+		fmt.Fprintf(textOut, "  Page:       %s\n", prov.Page)
+		fmt.Fprintf(textOut, "  Origin:     %s\n", describeProvenance(prov))
+	}
+
+	if !result.OK {
+		return &Error{Code: CodeVerificationFailed, Err: fmt.Errorf("%s doesn't match an official release", path), Reported: isJSON()}
+	}
+	return nil
+}
+
+// describeProvenance explains where a recover.html comes from, in color.
+func describeProvenance(p *html.Provenance) string {
+	switch p.Origin {
+	case html.OriginOfficial:
+		return green("✓ official release " + p.Release)
+	case html.OriginThisBuild:
+		return green(fmt.Sprintf("✓ made by this copy of rememory (%s)", version))
+	case html.OriginModified:
+		return red(fmt.Sprintf("✗ modified: claims to be from %s, but doesn't match it", p.Claimed))
+	default:
+		return yellow("? unknown: matches no release this copy of rememory knows; it may be from a release made before verify-html, or from a newer one: update rememory and check again")
+	}
+}
@@ -41,24 +41,36 @@ func Encrypt(dst io.Writer, src io.Reader, passphrase string) error {
 
 // Decrypt decrypts age-encrypted data using a passphrase.
 func Decrypt(dst io.Writer, src io.Reader, passphrase string) error {
+	reader, err := DecryptReader(src, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, reader); err != nil {
+		return fmt.Errorf("reading decrypted data: %w", err)
+	}
+
+	return nil
+}
+
+// DecryptReader returns a reader that decrypts src as it's read. age
+// decrypts and authenticates in 64 KiB chunks, so only a chunk is held in
+// memory; a truncated or tampered file is only reported by the Read that
+// reaches it, so data read before an error must not be trusted.
+func DecryptReader(src io.Reader, passphrase string) (io.Reader, error) {
 	if passphrase == "" {
-		return ErrEmptyPassphrase
+		return nil, ErrEmptyPassphrase
 	}
 	identity, err := age.NewScryptIdentity(passphrase)
 	if err != nil {
-		return fmt.Errorf("creating identity: %w", err)
+		return nil, fmt.Errorf("creating identity: %w", err)
 	}
 
 	reader, err := age.Decrypt(src, identity)
 	if err != nil {
-		return fmt.Errorf("decrypting: %w", err)
-	}
-
-	if _, err := io.Copy(dst, reader); err != nil {
-		return fmt.Errorf("reading decrypted data: %w", err)
+		return nil, fmt.Errorf("decrypting: %w", err)
 	}
-
-	return nil
+	return reader, nil
 }
 
 // DecryptBytes is a convenience function that decrypts data and returns bytes.
@@ -83,3 +95,27 @@ func DecryptBytes(encryptedData []byte, passphrase string) ([]byte, error) {
 
 	return decrypted, nil
 }
+
+// The Secret variants below pass the passphrase to age without copying it
+// into a Go string. age still keeps its own copy while encrypting or
+// decrypting, which is left to the garbage collector.
+
+// EncryptSecret is Encrypt with a passphrase held in a Secret.
+func EncryptSecret(dst io.Writer, src io.Reader, passphrase *Secret) error {
+	return Encrypt(dst, src, passphrase.view())
+}
+
+// DecryptSecret is Decrypt with a passphrase held in a Secret.
+func DecryptSecret(dst io.Writer, src io.Reader, passphrase *Secret) error {
+	return Decrypt(dst, src, passphrase.view())
+}
+
+// DecryptReaderSecret is DecryptReader with a passphrase held in a Secret.
+func DecryptReaderSecret(src io.Reader, passphrase *Secret) (io.Reader, error) {
+	return DecryptReader(src, passphrase.view())
+}
+
+// DecryptBytesSecret is DecryptBytes with a passphrase held in a Secret.
+func DecryptBytesSecret(encryptedData []byte, passphrase *Secret) ([]byte, error) {
+	return DecryptBytes(encryptedData, passphrase.view())
+}
@@ -31,18 +31,43 @@ func ExtractTarGz(tarGzData []byte) ([]ExtractedFile, error) {
 
 // ExtractTarGzReader extracts files from a tar.gz reader.
 func ExtractTarGzReader(r io.Reader) ([]ExtractedFile, error) {
+	var files []ExtractedFile
+	err := WalkTarGz(r, func(name string, size int64, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("reading file %s from archive: %w", name, err)
+		}
+		files = append(files, ExtractedFile{
+			Name: name,
+			Data: data,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// pathTraversal detects ".." path elements in archive entries.
+var pathTraversal = regexp.MustCompile(`(^|/)\.\.(/|$)`)
+
+// WalkTarGz reads a tar.gz stream and calls fn for each regular file, in
+// archive order, with a reader for its contents that's only valid until fn
+// returns. Nothing is held in memory beyond the file being read, so large
+// archives can be extracted a file at a time. It applies the same checks as
+// ExtractTarGzReader, and reads the stream to its end so the gzip checksum
+// (and any checks of the reader underneath) are verified.
+func WalkTarGz(r io.Reader, fn func(name string, size int64, r io.Reader) error) error {
 	gzr, err := gzip.NewReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("creating gzip reader: %w", err)
+		return fmt.Errorf("creating gzip reader: %w", err)
 	}
 	defer gzr.Close()
 
 	tr := tar.NewReader(gzr)
-	var files []ExtractedFile
 	var totalSize int64
-
-	// Regex to detect path traversal
-	pathTraversal := regexp.MustCompile(`(^|/)\.\.(/|$)`)
+	var count int
 
 	for {
 		header, err := tr.Next()
@@ -50,12 +75,12 @@ func ExtractTarGzReader(r io.Reader) ([]ExtractedFile, error) {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("reading tar: %w", err)
+			return fmt.Errorf("reading tar: %w", err)
 		}
 
 		// Security: reject path traversal
 		if pathTraversal.MatchString(header.Name) {
-			return nil, fmt.Errorf("archive contains invalid path: %s", header.Name)
+			return fmt.Errorf("archive contains invalid path: %s", header.Name)
 		}
 
 		// Skip directories, symlinks, and other special files
@@ -65,29 +90,28 @@ func ExtractTarGzReader(r io.Reader) ([]ExtractedFile, error) {
 
 		// Security: enforce file size limits
 		if header.Size > MaxFileSize {
-			return nil, fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", header.Name, MaxFileSize)
+			return fmt.Errorf("file %s exceeds maximum allowed size (%d bytes)", header.Name, MaxFileSize)
 		}
 		totalSize += header.Size
 		if totalSize > MaxTotalSize {
-			return nil, fmt.Errorf("archive exceeds maximum total size (%d bytes)", MaxTotalSize)
+			return fmt.Errorf("archive exceeds maximum total size (%d bytes)", MaxTotalSize)
 		}
 
 		// Use LimitReader for additional safety
-		limitedReader := io.LimitReader(tr, MaxFileSize)
-		data, err := io.ReadAll(limitedReader)
-		if err != nil {
-			return nil, fmt.Errorf("reading file %s from archive: %w", header.Name, err)
+		if err := fn(header.Name, header.Size, io.LimitReader(tr, MaxFileSize)); err != nil {
+			return err
 		}
+		count++
+	}
 
-		files = append(files, ExtractedFile{
-			Name: header.Name,
-			Data: data,
-		})
+	if count == 0 {
+		return fmt.Errorf("empty archive")
 	}
 
-	if len(files) == 0 {
-		return nil, fmt.Errorf("empty archive")
+	// Read past the tar end marker to the gzip trailer
+	if _, err := io.Copy(io.Discard, gzr); err != nil {
+		return fmt.Errorf("reading gzip: %w", err)
 	}
 
-	return files, nil
+	return nil
 }
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Proof-of-possession challenges let the owner check that a friend still has
+// their share, without the share ever being sent. When sealing, the owner
+// records a key derived from each share. A friend answers a random challenge
+// with an HMAC under the same key, computed from their own copy of the share.
+
+// ChallengeWords is the number of words in a challenge and in its response.
+const ChallengeWords = 4
+
+// challengeKeyLabel separates challenge keys from other uses of share data.
+const challengeKeyLabel = "rememory challenge key v1"
+
+// NewChallenge returns a random challenge of ChallengeWords English words.
+func NewChallenge() (string, error) {
+	b := make([]byte, (ChallengeWords*11+7)/8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating challenge: %w", err)
+	}
+	return strings.Join(EncodeWords(b)[:ChallengeWords], " "), nil
+}
+
+// NormalizeChallenge lowercases words and collapses spacing, so challenges
+// and responses compare equal however they were typed.
+func NormalizeChallenge(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// ChallengeKey derives the key recorded for a share at seal time. It reveals
+// nothing about the share data.
+func ChallengeKey(data []byte) string {
+	mac := hmac.New(sha256.New, data)
+	mac.Write([]byte(challengeKeyLabel))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ChallengeResponse answers a challenge with a key from ChallengeKey.
+// Returns ChallengeWords English words.
+func ChallengeResponse(key, challenge string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(NormalizeChallenge(challenge)))
+	sum := mac.Sum(nil)
+	return strings.Join(EncodeWords(sum[:(ChallengeWords*11+7)/8])[:ChallengeWords], " ")
+}
+
+// VerifyChallengeResponse checks a response against the key recorded for a share.
+func VerifyChallengeResponse(key, challenge, response string) bool {
+	return VerifyHash(NormalizeChallenge(response), ChallengeResponse(key, challenge))
+}
+
+// RespondToChallenge answers a challenge with this share.
+func (s *Share) RespondToChallenge(challenge string) string {
+	return ChallengeResponse(ChallengeKey(s.Data), challenge)
+}
@@ -4,8 +4,15 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"strings"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 func TestHashString(t *testing.T) {
@@ -494,6 +501,49 @@ func TestExtractTarGzPathTraversal(t *testing.T) {
 	})
 }
 
+func TestDecryptReaderWalkTarGz(t *testing.T) {
+	entries := map[string]string{
+		"manifest/a.txt": "hello",
+		"manifest/b.txt": strings.Repeat("x", 200000),
+	}
+	var encrypted bytes.Buffer
+	if err := Encrypt(&encrypted, bytes.NewReader(createTarGz(t, entries)), "test-passphrase"); err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	walk := func(ciphertext []byte) (map[string]string, error) {
+		r, err := DecryptReader(bytes.NewReader(ciphertext), "test-passphrase")
+		if err != nil {
+			return nil, err
+		}
+		got := make(map[string]string)
+		err = WalkTarGz(r, func(name string, size int64, r io.Reader) error {
+			data, err := io.ReadAll(r)
+			if int64(len(data)) != size {
+				t.Errorf("file %q: read %d bytes, header says %d", name, len(data), size)
+			}
+			got[name] = string(data)
+			return err
+		})
+		return got, err
+	}
+
+	got, err := walk(encrypted.Bytes())
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	for name, want := range entries {
+		if got[name] != want {
+			t.Errorf("file %q: got %d bytes, want %d", name, len(got[name]), len(want))
+		}
+	}
+
+	// A truncated file is only noticed at its end, after some files were read
+	if _, err := walk(encrypted.Bytes()[:encrypted.Len()-16]); err == nil {
+		t.Error("expected error for truncated ciphertext")
+	}
+}
+
 func TestSanitizeFilename(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -531,3 +581,355 @@ func TestSanitizeFilename(t *testing.T) {
 		}
 	}
 }
+
+func TestChallengeResponse(t *testing.T) {
+	challenge, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Fields(challenge)); n != ChallengeWords {
+		t.Fatalf("challenge %q has %d words, want %d", challenge, n, ChallengeWords)
+	}
+
+	share := NewShare(2, 1, 3, 2, "Alice", []byte("share data for alice"))
+	key := ChallengeKey(share.Data)
+	response := share.RespondToChallenge(challenge)
+	if n := len(strings.Fields(response)); n != ChallengeWords {
+		t.Errorf("response %q has %d words, want %d", response, n, ChallengeWords)
+	}
+
+	// Case and spacing don't matter
+	if !VerifyChallengeResponse(key, strings.ToUpper(challenge), "  "+strings.ToUpper(response)+"\n") {
+		t.Error("valid response rejected")
+	}
+
+	other := NewShare(2, 2, 3, 2, "Bob", []byte("share data for bob"))
+	if VerifyChallengeResponse(key, challenge, other.RespondToChallenge(challenge)) {
+		t.Error("response from another share accepted")
+	}
+	if VerifyChallengeResponse(key, challenge+" extra", response) {
+		t.Error("response to another challenge accepted")
+	}
+}
+
+func TestReshareTranscript(t *testing.T) {
+	tr := ReshareTranscript{
+		Project:          "test",
+		At:               time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ManifestChecksum: "sha256:abc",
+		OldThreshold:     3,
+		OldTotal:         5,
+		Participants:     []string{"Alice (share 1)", "Bob (share 2)", "Camila (share 3)"},
+		NewThreshold:     2,
+		NewHolders:       []string{"Alice", "Bob", "Dana", "Eve"},
+	}
+	tr.Sign(NewSecret([]byte("passphrase")))
+
+	if err := tr.Verify(); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if tr.PublicKey != TranscriptPublicKey(NewSecret([]byte("passphrase"))) {
+		t.Error("public key doesn't match the passphrase")
+	}
+	if tr.PublicKey == TranscriptPublicKey(NewSecret([]byte("other passphrase"))) {
+		t.Error("different passphrases gave the same key")
+	}
+	if !strings.HasSuffix(tr.Encode(), "Signature: "+tr.Signature+"\n") {
+		t.Errorf("Encode doesn't end with the signature:\n%s", tr.Encode())
+	}
+
+	tampered := tr
+	tampered.Participants = []string{"Mallory (share 1)", "Bob (share 2)", "Camila (share 3)"}
+	if tampered.Verify() == nil {
+		t.Error("tampered transcript verified")
+	}
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSecretWipe(t *testing.T) {
+	src := []byte("correct horse battery staple")
+	s := NewSecret(src)
+	if !isZero(src) {
+		t.Error("NewSecret should wipe its input")
+	}
+	if string(s.Bytes()) != "correct horse battery staple" {
+		t.Errorf("secret holds %q", s.Bytes())
+	}
+	if fmt.Sprint(s) != "[secret]" || strings.Contains(fmt.Sprintf("%v", s), "horse") {
+		t.Errorf("secret printed as %v", s)
+	}
+
+	held := s.Bytes()
+	s.Wipe()
+	if !isZero(held) {
+		t.Error("Wipe should zero the secret's memory")
+	}
+	if s.Len() != 0 || s.Bytes() != nil {
+		t.Error("a wiped secret should be empty")
+	}
+	s.Wipe()
+
+	var none *Secret
+	none.Wipe()
+	if none.Len() != 0 {
+		t.Error("nil secret should be empty")
+	}
+}
+
+func TestSecretPages(t *testing.T) {
+	// mlock doesn't nest, so two small secrets must not share a page:
+	// wiping one would unlock the other.
+	raw := NewSecret([]byte("raw"))
+	passphrase := NewSecret([]byte("passphrase"))
+	defer passphrase.Wipe()
+	if raw.locked == nil || passphrase.locked == nil {
+		raw.Wipe()
+		t.Skip("memory locking isn't available here")
+	}
+	page := uintptr(os.Getpagesize())
+	start := func(b []byte) uintptr { return uintptr(unsafe.Pointer(unsafe.SliceData(b))) }
+	for _, s := range []*Secret{raw, passphrase} {
+		if start(s.locked)%page != 0 || uintptr(len(s.locked))%page != 0 {
+			t.Error("locked memory should cover whole pages")
+		}
+		if start(s.b) != start(s.locked) || len(s.b) > len(s.locked) {
+			t.Error("the secret should live in its locked pages")
+		}
+	}
+	a, b := start(raw.locked), start(passphrase.locked)
+	if a < b+uintptr(len(passphrase.locked)) && b < a+uintptr(len(raw.locked)) {
+		t.Error("two secrets share a locked page")
+	}
+	raw.Wipe()
+	if raw.locked != nil {
+		t.Error("Wipe should unlock the secret's pages")
+	}
+}
+
+func TestRecoverSecret(t *testing.T) {
+	raw := []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01, 0xfe, 0xff}
+	for _, version := range []int{1, 2} {
+		s := RecoverSecret(raw, version)
+		if got, want := string(s.Bytes()), RecoverPassphrase(raw, version); got != want {
+			t.Errorf("v%d: got %q, want %q", version, got, want)
+		}
+		if !s.Equal(RecoverSecret(raw, version)) {
+			t.Errorf("v%d: equal secrets should compare equal", version)
+		}
+		held := s.Bytes()
+		s.Wipe()
+		if !isZero(held) {
+			t.Errorf("v%d: passphrase not wiped", version)
+		}
+	}
+	if isZero(raw) {
+		t.Error("RecoverSecret should leave recovered bytes to the caller")
+	}
+
+	// Encrypting with a Secret matches encrypting with the same string
+	var encrypted bytes.Buffer
+	if err := EncryptSecret(&encrypted, strings.NewReader("data"), RecoverSecret(raw, 2)); err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+	decrypted, err := DecryptBytes(encrypted.Bytes(), RecoverPassphrase(raw, 2))
+	if err != nil || string(decrypted) != "data" {
+		t.Errorf("decrypting: %q, %v", decrypted, err)
+	}
+	if err := EncryptSecret(&encrypted, strings.NewReader("data"), nil); err != ErrEmptyPassphrase {
+		t.Errorf("expected ErrEmptyPassphrase for a wiped secret, got %v", err)
+	}
+}
+
+func TestBundleSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"README.txt":   []byte("readme"),
+		"recover.html": []byte("<html>"),
+	}
+	sig, err := SignBundle(priv, "sha256:abc", []SignedFile{
+		{Name: "README.txt", Checksum: HashBytes(files["README.txt"])},
+		{Name: "recover.html", Checksum: HashBytes(files["recover.html"])},
+	})
+	if err != nil {
+		t.Fatalf("SignBundle: %v", err)
+	}
+
+	parsed, err := ParseBundleSignature([]byte(sig.Encode()))
+	if err != nil {
+		t.Fatalf("ParseBundleSignature: %v", err)
+	}
+	if err := parsed.VerifyFiles(files); err != nil {
+		t.Fatalf("VerifyFiles: %v", err)
+	}
+	if parsed.Fingerprint() != KeyFingerprint(pub) {
+		t.Errorf("fingerprint %s, want %s", parsed.Fingerprint(), KeyFingerprint(pub))
+	}
+	if got, err := ParseSSHPublicKey(parsed.PublicKey + " owner@laptop"); err != nil || !pub.Equal(got) {
+		t.Errorf("ParseSSHPublicKey: %v", err)
+	}
+
+	tampered := map[string][]byte{"README.txt": files["README.txt"], "recover.html": []byte("<html>evil")}
+	if err := parsed.VerifyFiles(tampered); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("changed file: expected ErrBadSignature, got %v", err)
+	}
+	extra := map[string][]byte{"README.txt": files["README.txt"], "recover.html": files["recover.html"], "evil.exe": nil}
+	if err := parsed.VerifyFiles(extra); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("unsigned file: expected ErrBadSignature, got %v", err)
+	}
+
+	// Re-signing the changed checksums with the same key isn't possible
+	// without it; editing them in SIGNATURE.txt breaks the signature
+	parsed.Files[1].Checksum = HashBytes(tampered["recover.html"])
+	if err := parsed.Verify(); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("edited signature: expected ErrBadSignature, got %v", err)
+	}
+}
+
+func TestParity(t *testing.T) {
+	for _, size := range []int{0, 100, 3000, 200000} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i*7 + i/251)
+		}
+		par := NewParity(data)
+		blockSize, k, m := parityLayout(size)
+
+		got, fixed, err := Repair(data, par)
+		if err != nil || fixed != 0 || !bytes.Equal(got, data) {
+			t.Fatalf("size %d: intact data: fixed %d, err %v", size, fixed, err)
+		}
+		if size == 0 {
+			continue
+		}
+
+		// Flip a bit in as many blocks as there are parity blocks
+		damaged := bytes.Clone(data)
+		for i := 0; i < min(m, k); i++ {
+			damaged[(i*blockSize+17)%size] ^= 0x04
+		}
+		got, fixed, err = Repair(damaged, par)
+		if err != nil {
+			t.Fatalf("size %d: repairing %d blocks: %v", size, min(m, k), err)
+		}
+		if fixed != min(m, k) || !bytes.Equal(got, data) {
+			t.Errorf("size %d: fixed %d blocks, want %d", size, fixed, min(m, k))
+		}
+
+		// A truncated file loses its last block
+		if got, fixed, err = Repair(data[:size-1], par); err != nil || fixed != 1 || !bytes.Equal(got, data) {
+			t.Errorf("size %d: truncated: fixed %d, err %v", size, fixed, err)
+		}
+
+		if k > m {
+			for i := 0; i <= m; i++ {
+				damaged[i*blockSize] ^= 0xff
+			}
+			if _, _, err := Repair(damaged, par); !errors.Is(err, ErrUnrepairable) {
+				t.Errorf("size %d: too damaged: got %v, want ErrUnrepairable", size, err)
+			}
+		}
+	}
+
+	// A damaged parity block is skipped, and the others still repair
+	data := bytes.Repeat([]byte("rememory "), 20000)
+	par := NewParity(data)
+	par[len(par)-1] ^= 0x01
+	damaged := bytes.Clone(data)
+	damaged[1000] ^= 0x80
+	if got, fixed, err := Repair(damaged, par); err != nil || fixed != 1 || !bytes.Equal(got, data) {
+		t.Errorf("damaged parity block: fixed %d, err %v", fixed, err)
+	}
+
+	header := bytes.Clone(par)
+	header[30] ^= 0x01
+	if _, _, err := Repair(damaged, header); err == nil {
+		t.Error("expected an error for a damaged parity header")
+	}
+
+	// The Check line isn't keyed, so a forged header has a valid one; its
+	// layout must match the one NewParity picks for its size
+	forge := func(size, block, k, m int) []byte {
+		h := fmt.Sprintf("%s\nManifest: %s\nSize: %d\nBlock: %d\nData: %d\nParity: %d\n", parityHeader, HashBytes(data), size, block, k, m)
+		return []byte(h + "Check: " + HashString(h) + "\n\n")
+	}
+	for _, forged := range [][]byte{
+		forge(10, 1<<40, 1, 2),
+		forge(1<<40, 1<<33, 128, 7),
+		forge(len(data), 1<<20, 2, 2),
+	} {
+		if _, err := parseParity(forged); err == nil {
+			t.Errorf("forged parity header accepted: %q", forged)
+		}
+	}
+}
+
+func TestPaperManifest(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i*31 + i/7)
+	}
+	lines := PaperLines(data)
+	if len(lines) != 1+40 {
+		t.Fatalf("got %d lines, want 41", len(lines))
+	}
+
+	// Scanned QR codes, in any order
+	codes := PaperQRCodes(data)
+	r := NewPaperReader()
+	for i := len(codes) - 1; i >= 0; i-- {
+		if err := r.Add(codes[i]); err != nil {
+			t.Fatalf("adding QR code %d: %v", i, err)
+		}
+	}
+	if got, err := r.Read(); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("reading QR codes: %v", err)
+	}
+
+	// Typed by hand: lowercase, extra spaces, 0 for O and 1 for I
+	typed := strings.NewReplacer("O", "0", "I", "1").Replace(strings.Join(lines[1:], "\n"))
+	typed = strings.ToLower(lines[0] + "\n" + typed)
+	typed = strings.ReplaceAll(typed, " ", "  ")
+	r = NewPaperReader()
+	if err := r.Add(typed); err != nil {
+		t.Fatalf("adding typed lines: %v", err)
+	}
+	if got, err := r.Read(); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("reading typed lines: %v", err)
+	}
+
+	// A typo is caught by its line's check code
+	r = NewPaperReader()
+	typo := []byte(lines[3])
+	typo[7] = map[bool]byte{true: 'B', false: 'C'}[typo[7] != 'B']
+	err := r.Add(strings.Join(append(append([]string{}, lines[:3]...), string(typo)), "\n"))
+	var lineErr *PaperLineError
+	if !errors.As(err, &lineErr) || len(lineErr.Lines) != 1 || lineErr.Lines[0] != 3 {
+		t.Errorf("typo on line 3: got %v", err)
+	}
+	if missing := r.Missing(); len(missing) != 38 || missing[0] != 3 {
+		t.Errorf("missing lines: got %v", missing)
+	}
+	if _, err := r.Read(); err == nil || !strings.Contains(err.Error(), "3-40") {
+		t.Errorf("reading with missing lines: got %v", err)
+	}
+
+	// Lines of another manifest are refused
+	other := PaperLines([]byte("another manifest"))
+	if err := r.Add(other[0]); err == nil {
+		t.Error("expected an error for another manifest's header")
+	}
+	if _, err := NewPaperReader().Read(); err == nil {
+		t.Error("expected an error without a header")
+	}
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A small MANIFEST.age can be printed in README.pdf, so a friend who only
+// kept the paper can still recover. It's printed as numbered lines of
+// base32, each with a check code that catches typos, and as QR codes that
+// each hold a header and a few of those lines, as text. Typed lines and
+// scanned QR codes are read the same way, in any order, and the result is
+// checked against the header's checksum.
+//
+// Paper text looks like this:
+//
+//	MANIFEST.AGE 1234 SHA256:9F86D081...
+//	0001 ABCDE FGHIJ KLMNO PQRST UVWXY Z2345 67ABC DEFGH  K3XQ
+//	0002 ...
+
+const (
+	// MaxPaperManifestSize is the largest MANIFEST.age that's printed on
+	// paper: about six pages of lines and QR codes.
+	MaxPaperManifestSize = 8 << 10
+
+	// PaperLinesPerQR is how many lines each QR code holds.
+	PaperLinesPerQR = 8
+
+	// paperBytesPerLine is the data on each line: 40 base32 characters.
+	paperBytesPerLine = 25
+
+	// paperHeaderPrefix starts the header line.
+	paperHeaderPrefix = "MANIFEST.AGE"
+
+	paperGroupSize = 5
+	paperCheckSize = 4
+)
+
+var (
+	paperBase32   = base32.StdEncoding.WithPadding(base32.NoPadding)
+	paperHeaderRe = regexp.MustCompile(`^MANIFEST\.AGE\s+(\d+)\s+SHA256:([0-9A-F]{64})$`)
+	paperLineRe   = regexp.MustCompile(`^(\d{1,4})\s+([A-Z0-9\s]+)$`)
+)
+
+// PaperLines returns the lines MANIFEST.age is printed as: the header,
+// then one numbered line for every 25 bytes.
+func PaperLines(data []byte) []string {
+	lines := []string{paperHeader(data)}
+	for n := 1; (n-1)*paperBytesPerLine < len(data); n++ {
+		chunk := data[(n-1)*paperBytesPerLine : min(n*paperBytesPerLine, len(data))]
+		encoded := paperBase32.EncodeToString(chunk)
+		var groups []string
+		for i := 0; i < len(encoded); i += paperGroupSize {
+			groups = append(groups, encoded[i:min(i+paperGroupSize, len(encoded))])
+		}
+		lines = append(lines, fmt.Sprintf("%04d %s  %s", n, strings.Join(groups, " "), paperCheck(n, chunk)))
+	}
+	return lines
+}
+
+// PaperQRCodes returns the text of each QR code MANIFEST.age is printed as:
+// the header, and PaperLinesPerQR lines, so every code can be scanned on
+// its own and in any order.
+func PaperQRCodes(data []byte) []string {
+	lines := PaperLines(data)
+	header, lines := lines[0], lines[1:]
+	var codes []string
+	for i := 0; i < len(lines); i += PaperLinesPerQR {
+		chunk := lines[i:min(i+PaperLinesPerQR, len(lines))]
+		codes = append(codes, header+"\n"+strings.Join(chunk, "\n"))
+	}
+	return codes
+}
+
+func paperHeader(data []byte) string {
+	hash := sha256.Sum256(data)
+	return fmt.Sprintf("%s %d SHA256:%X", paperHeaderPrefix, len(data), hash)
+}
+
+// paperCheck returns the check code of line n: 20 bits of a hash over the
+// line number and its data, so a line typed in the wrong place also fails.
+func paperCheck(n int, chunk []byte) string {
+	h := sha256.Sum256(append([]byte(strconv.Itoa(n)+":"), chunk...))
+	return paperBase32.EncodeToString(h[:3])[:paperCheckSize]
+}
+
+// PaperLineError names the lines whose check code doesn't match: typos,
+// usually.
+type PaperLineError struct {
+	Lines []int
+}
+
+func (e *PaperLineError) Error() string {
+	if len(e.Lines) == 1 {
+		return fmt.Sprintf("line %d doesn't match its check code; look for a typo", e.Lines[0])
+	}
+	return fmt.Sprintf("lines %s don't match their check codes; look for typos", formatLineRanges(e.Lines))
+}
+
+// PaperReader puts MANIFEST.age back together from its printed lines, read
+// a few at a time: typed by hand, or scanned from the QR codes.
+type PaperReader struct {
+	size     int
+	checksum string // uppercase hex, from the header
+	lines    map[int][]byte
+}
+
+// NewPaperReader returns an empty PaperReader.
+func NewPaperReader() *PaperReader {
+	return &PaperReader{size: -1, lines: make(map[int][]byte)}
+}
+
+// Add reads the header and lines in text; anything else is ignored. Letters
+// may be in either case, and 0, 1 and 8 are read as O, I and B, which they
+// are easily mistaken for. Good lines are kept even when others are bad,
+// which are named by a *PaperLineError.
+func (r *PaperReader) Add(text string) error {
+	var bad []int
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ToUpper(strings.TrimSpace(line))
+		if m := paperHeaderRe.FindStringSubmatch(strings.Join(strings.Fields(line), " ")); m != nil {
+			size, err := strconv.Atoi(m[1])
+			if err != nil || size > MaxPaperManifestSize {
+				return fmt.Errorf("invalid header: %s", line)
+			}
+			if r.size >= 0 && (size != r.size || m[2] != r.checksum) {
+				return fmt.Errorf("these lines are from a different MANIFEST.age than the ones already read")
+			}
+			r.size, r.checksum = size, m[2]
+			continue
+		}
+		m := paperLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		chars := paperCharReplacer.Replace(strings.Join(strings.Fields(m[2]), ""))
+		if n == 0 || len(chars) <= paperCheckSize {
+			continue
+		}
+		encoded, check := chars[:len(chars)-paperCheckSize], chars[len(chars)-paperCheckSize:]
+		chunk, err := paperBase32.DecodeString(encoded)
+		if err != nil || len(chunk) > paperBytesPerLine || paperCheck(n, chunk) != check {
+			bad = append(bad, n)
+			continue
+		}
+		r.lines[n] = chunk
+	}
+	if len(bad) > 0 {
+		slices.Sort(bad)
+		return &PaperLineError{Lines: slices.Compact(bad)}
+	}
+	return nil
+}
+
+// paperCharReplacer reads digits that aren't in base32 as the letters they
+// look like. It's used after the line number, so only data is changed.
+var paperCharReplacer = strings.NewReplacer("0", "O", "1", "I", "8", "B")
+
+// Lines returns how many lines MANIFEST.age is printed as, or 0 before the
+// header is read.
+func (r *PaperReader) Lines() int {
+	if r.size < 0 {
+		return 0
+	}
+	return (r.size + paperBytesPerLine - 1) / paperBytesPerLine
+}
+
+// Missing returns the numbers of the lines not read yet.
+func (r *PaperReader) Missing() []int {
+	var missing []int
+	for n := 1; n <= r.Lines(); n++ {
+		if _, ok := r.lines[n]; !ok {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
+
+// Read returns MANIFEST.age once every line is read, checked against the
+// header's checksum.
+func (r *PaperReader) Read() ([]byte, error) {
+	if r.size < 0 {
+		return nil, fmt.Errorf("the header line (%s ...) hasn't been read", paperHeaderPrefix)
+	}
+	if missing := r.Missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("%d of %d lines are missing (%s)", len(missing), r.Lines(), formatLineRanges(missing))
+	}
+	var data []byte
+	for n := 1; n <= r.Lines(); n++ {
+		data = append(data, r.lines[n]...)
+	}
+	if len(data) != r.size || fmt.Sprintf("%X", sha256.Sum256(data)) != r.checksum {
+		return nil, fmt.Errorf("the lines don't match the header's checksum")
+	}
+	return data, nil
+}
+
+// formatLineRanges writes sorted line numbers as ranges: "3, 7-9".
+func formatLineRanges(numbers []int) string {
+	var parts []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(numbers[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,327 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bundles sit on USB sticks and old laptops for years, and a single flipped
+// bit makes MANIFEST.age undecryptable. Parity data, stored next to it,
+// repairs that: MANIFEST.age is cut into blocks, each with its hash, and
+// Reed-Solomon parity blocks are computed over them. A damaged block is
+// found by its hash and rebuilt from the others and the parity blocks, as
+// long as no more blocks are damaged than there are parity blocks.
+//
+// Parity data needs no checksum or signature of its own: a repair only
+// succeeds if it gives back exactly the MANIFEST.age that was sealed.
+
+const (
+	// ParityFileName is the parity data of MANIFEST.age, next to it.
+	ParityFileName = "MANIFEST.age.par"
+
+	// parityHeader is the first line of parity data.
+	parityHeader = "rememory parity v1"
+
+	minParityBlockSize = 512
+	maxDataBlocks      = 128
+)
+
+// ErrUnrepairable is returned when a file is too damaged for its parity
+// data to repair. Match it with errors.Is.
+var ErrUnrepairable = errors.New("too damaged to repair")
+
+// parity describes parity data: the layout of the file it protects, the
+// hash of each data and parity block, and the parity blocks themselves.
+type parity struct {
+	checksum  string // of the whole file
+	size      int
+	blockSize int
+	data      int // number of data blocks
+	hashes    [][]byte
+	blocks    [][]byte // parity blocks; nil when missing
+}
+
+// parityLayout picks the block size and the number of data and parity
+// blocks for a file of size bytes: about 5% of parity, and at least two
+// blocks of it.
+func parityLayout(size int) (blockSize, data, parityBlocks int) {
+	blockSize = max(minParityBlockSize, (size+maxDataBlocks-1)/maxDataBlocks)
+	data = max(1, (size+blockSize-1)/blockSize)
+	parityBlocks = max(2, (data+19)/20)
+	return blockSize, data, parityBlocks
+}
+
+// NewParity returns parity data for data, to be stored next to it.
+func NewParity(data []byte) []byte {
+	blockSize, k, m := parityLayout(len(data))
+	blocks := splitBlocks(data, blockSize, k)
+
+	parityBlocks := make([][]byte, m)
+	for r := range parityBlocks {
+		parityBlocks[r] = make([]byte, blockSize)
+		for c, block := range blocks {
+			gfMulAdd(parityBlocks[r], block, cauchy(r, c, m))
+		}
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "%s\n", parityHeader)
+	fmt.Fprintf(&header, "Manifest: %s\n", HashBytes(data))
+	fmt.Fprintf(&header, "Size: %d\n", len(data))
+	fmt.Fprintf(&header, "Block: %d\n", blockSize)
+	fmt.Fprintf(&header, "Data: %d\n", k)
+	fmt.Fprintf(&header, "Parity: %d\n", m)
+
+	var out bytes.Buffer
+	out.WriteString(header.String())
+	fmt.Fprintf(&out, "Check: %s\n\n", HashString(header.String()))
+	for _, block := range append(blocks, parityBlocks...) {
+		h := sha256.Sum256(block)
+		out.Write(h[:])
+	}
+	for _, block := range parityBlocks {
+		out.Write(block)
+	}
+	return out.Bytes()
+}
+
+// Repair checks data against its parity data, and rebuilds the blocks that
+// are damaged. It returns the repaired data and how many blocks were
+// rebuilt; intact data is returned as is. A file with more damaged blocks
+// than the parity can rebuild fails with ErrUnrepairable.
+func Repair(data, parityData []byte) ([]byte, int, error) {
+	p, err := parseParity(parityData)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading parity data: %w", err)
+	}
+	if VerifyHash(HashBytes(data), p.checksum) {
+		return data, 0, nil
+	}
+
+	blocks := splitBlocks(data, p.blockSize, p.data)
+	var damaged []int
+	for c, block := range blocks {
+		h := sha256.Sum256(block)
+		if !bytes.Equal(h[:], p.hashes[c]) {
+			damaged = append(damaged, c)
+		}
+	}
+	var rows []int
+	for r, block := range p.blocks {
+		if block != nil && len(rows) < len(damaged) {
+			rows = append(rows, r)
+		}
+	}
+	if len(rows) < len(damaged) {
+		return nil, 0, fmt.Errorf("%d of %d blocks are damaged and only %d can be rebuilt: %w", len(damaged), p.data, len(rows), ErrUnrepairable)
+	}
+
+	if len(damaged) > 0 {
+		m := len(p.blocks)
+		isDamaged := make(map[int]bool, len(damaged))
+		for _, c := range damaged {
+			isDamaged[c] = true
+		}
+		// Each parity block, less the intact blocks' part of it, is the
+		// damaged blocks' part: a square system of equations
+		syndromes := make([][]byte, len(rows))
+		matrix := make([][]byte, len(rows))
+		for i, r := range rows {
+			syndromes[i] = bytes.Clone(p.blocks[r])
+			for c, block := range blocks {
+				if !isDamaged[c] {
+					gfMulAdd(syndromes[i], block, cauchy(r, c, m))
+				}
+			}
+			matrix[i] = make([]byte, len(damaged))
+			for j, c := range damaged {
+				matrix[i][j] = cauchy(r, c, m)
+			}
+		}
+		inverse, err := gfInvert(matrix)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", ErrUnrepairable, err)
+		}
+		for j, c := range damaged {
+			clear(blocks[c])
+			for i := range rows {
+				gfMulAdd(blocks[c], syndromes[i], inverse[j][i])
+			}
+		}
+	}
+
+	repaired := make([]byte, 0, p.data*p.blockSize)
+	for _, block := range blocks {
+		repaired = append(repaired, block...)
+	}
+	repaired = repaired[:p.size]
+	if !VerifyHash(HashBytes(repaired), p.checksum) {
+		return nil, 0, fmt.Errorf("rebuilt file doesn't match its checksum: %w", ErrUnrepairable)
+	}
+	return repaired, len(damaged), nil
+}
+
+// splitBlocks copies data into k blocks of blockSize bytes, padded with
+// zeros. Data missing from a truncated file is left as zeros.
+func splitBlocks(data []byte, blockSize, k int) [][]byte {
+	blocks := make([][]byte, k)
+	for c := range blocks {
+		blocks[c] = make([]byte, blockSize)
+		if start := c * blockSize; start < len(data) {
+			copy(blocks[c], data[start:])
+		}
+	}
+	return blocks
+}
+
+// parseParity reads parity data. Parity blocks that are missing or damaged
+// are left nil.
+func parseParity(data []byte) (*parity, error) {
+	end := bytes.Index(data, []byte("\n\n"))
+	if end < 0 {
+		return nil, fmt.Errorf("no parity header found")
+	}
+	lines := strings.Split(string(data[:end]), "\n")
+	if len(lines) < 2 || lines[0] != parityHeader {
+		return nil, fmt.Errorf("unsupported parity format")
+	}
+	check, ok := strings.CutPrefix(lines[len(lines)-1], "Check: ")
+	header := strings.Join(lines[:len(lines)-1], "\n") + "\n"
+	if !ok || !VerifyHash(HashString(header), check) {
+		return nil, fmt.Errorf("parity header is damaged")
+	}
+
+	p := &parity{}
+	m := 0
+	for _, line := range lines[1 : len(lines)-1] {
+		key, value, _ := strings.Cut(line, ": ")
+		n, _ := strconv.Atoi(value)
+		switch key {
+		case "Manifest":
+			p.checksum = value
+		case "Size":
+			p.size = n
+		case "Block":
+			p.blockSize = n
+		case "Data":
+			p.data = n
+		case "Parity":
+			m = n
+		}
+	}
+	// The header isn't authenticated, so its layout is checked against the
+	// one NewParity would pick before anything is allocated from it
+	if p.checksum == "" || p.size < 0 || p.size > MaxTotalSize {
+		return nil, fmt.Errorf("invalid parity header")
+	}
+	if blockSize, k, parityBlocks := parityLayout(p.size); p.blockSize != blockSize || p.data != k || m != parityBlocks {
+		return nil, fmt.Errorf("invalid parity header")
+	}
+
+	body := data[end+2:]
+	if len(body) < (p.data+m)*sha256.Size {
+		return nil, fmt.Errorf("parity data is truncated")
+	}
+	for i := 0; i < p.data+m; i++ {
+		p.hashes = append(p.hashes, body[i*sha256.Size:(i+1)*sha256.Size])
+	}
+	body = body[(p.data+m)*sha256.Size:]
+	p.blocks = make([][]byte, m)
+	for r := range p.blocks {
+		if len(body) < (r+1)*p.blockSize {
+			break
+		}
+		block := body[r*p.blockSize : (r+1)*p.blockSize]
+		if h := sha256.Sum256(block); bytes.Equal(h[:], p.hashes[p.data+r]) {
+			p.blocks[r] = block
+		}
+	}
+	return p, nil
+}
+
+// cauchy returns the coefficient of data block c in parity block r, out of
+// m parity blocks. Every square submatrix of a Cauchy matrix can be
+// inverted, so any damaged blocks can be rebuilt from as many parity blocks.
+func cauchy(r, c, m int) byte {
+	return gfInv(byte(r) ^ byte(m+c))
+}
+
+// GF(2^8) arithmetic, with the polynomial x^8 + x^4 + x^3 + x^2 + 1.
+var gfExp, gfLog = func() (exp [510]byte, log [256]byte) {
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = byte(x), byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	return exp, log
+}()
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
+
+// gfMulAdd adds c times src to dst.
+func gfMulAdd(dst, src []byte, c byte) {
+	if c == 0 {
+		return
+	}
+	var table [256]byte
+	for i := range table {
+		table[i] = gfMul(byte(i), c)
+	}
+	for i, b := range src {
+		dst[i] ^= table[b]
+	}
+}
+
+// gfInvert inverts a square matrix by Gauss-Jordan elimination.
+func gfInvert(matrix [][]byte) ([][]byte, error) {
+	n := len(matrix)
+	a := make([][]byte, n)
+	inv := make([][]byte, n)
+	for i := range a {
+		a[i] = bytes.Clone(matrix[i])
+		inv[i] = make([]byte, n)
+		inv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && a[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil, fmt.Errorf("singular matrix")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		scale := gfInv(a[col][col])
+		for j := 0; j < n; j++ {
+			a[col][j] = gfMul(a[col][j], scale)
+			inv[col][j] = gfMul(inv[col][j], scale)
+		}
+		for row := 0; row < n; row++ {
+			if row != col && a[row][col] != 0 {
+				f := a[row][col]
+				gfMulAdd(a[row], a[col], f)
+				gfMulAdd(inv[row], inv[col], f)
+			}
+		}
+	}
+	return inv, nil
+}
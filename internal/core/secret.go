@@ -0,0 +1,106 @@
+package core
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"runtime"
+	"unsafe"
+)
+
+// Secret holds key material (a passphrase, or the raw bytes it's made from)
+// in a byte slice that can be wiped, unlike a Go string. Where the platform
+// allows it (Linux), the memory is also locked so it isn't swapped to disk;
+// each Secret then gets pages of its own, so unlocking one never unlocks
+// another.
+//
+// Call Wipe as soon as the secret is no longer needed. A nil *Secret is
+// treated as an empty, already wiped secret.
+type Secret struct {
+	b      []byte
+	locked []byte // the locked pages holding b, if any
+}
+
+// NewSecret moves b into a new Secret: b is copied into locked memory, then
+// wiped. Keep using the Secret, not b.
+func NewSecret(b []byte) *Secret {
+	s := newSecret(len(b))
+	copy(s.b, b)
+	Wipe(b)
+	return s
+}
+
+// newSecret allocates an empty Secret of n bytes.
+func newSecret(n int) *Secret {
+	s := &Secret{}
+	s.b, s.locked = allocSecret(n)
+	return s
+}
+
+// RecoverSecret converts raw bytes from Combine() into the age passphrase,
+// like RecoverPassphrase, but keeps it in a Secret. recovered is left as
+// is; wipe it when done.
+func RecoverSecret(recovered []byte, version int) *Secret {
+	if version < 2 {
+		s := newSecret(len(recovered))
+		copy(s.b, recovered)
+		return s
+	}
+	s := newSecret(base64.RawURLEncoding.EncodedLen(len(recovered)))
+	base64.RawURLEncoding.Encode(s.b, recovered)
+	return s
+}
+
+// Bytes returns the secret itself. The slice is only valid until Wipe, and
+// must not be kept or copied into a string.
+func (s *Secret) Bytes() []byte {
+	if s == nil {
+		return nil
+	}
+	return s.b
+}
+
+// Len returns the length of the secret in bytes.
+func (s *Secret) Len() int {
+	return len(s.Bytes())
+}
+
+// Equal reports whether both secrets hold the same bytes, in constant time.
+func (s *Secret) Equal(other *Secret) bool {
+	return subtle.ConstantTimeCompare(s.Bytes(), other.Bytes()) == 1
+}
+
+// Wipe zeroes the secret and unlocks its memory. It is safe to call more
+// than once.
+func (s *Secret) Wipe() {
+	if s == nil || s.b == nil {
+		return
+	}
+	Wipe(s.b)
+	if s.locked != nil {
+		unlockMemory(s.locked)
+		s.locked = nil
+	}
+	s.b = nil
+}
+
+// String keeps secrets out of logs and error messages.
+func (s *Secret) String() string {
+	return "[secret]"
+}
+
+// view returns the secret as a string sharing its memory, for APIs that
+// only take strings. The string must not outlive the Secret.
+func (s *Secret) view() string {
+	b := s.Bytes()
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// Wipe zeroes b. Use it for share data and other key material once it's no
+// longer needed.
+func Wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// allocSecret returns n bytes for a secret, on pages of their own, and
+// locks those pages so they aren't swapped to disk. mlock doesn't nest: if
+// two secrets shared a page, wiping one would unlock the other while it's
+// still in use. locked is the locked region, to pass to unlockMemory, or
+// nil when locking failed because the process reached RLIMIT_MEMLOCK; the
+// secret is still wiped after use.
+func allocSecret(n int) (b, locked []byte) {
+	if n == 0 {
+		return make([]byte, 0), nil
+	}
+	page := os.Getpagesize()
+	size := (n + page - 1) / page * page
+	buf := make([]byte, size+page)
+	off := (page - int(uintptr(unsafe.Pointer(unsafe.SliceData(buf)))%uintptr(page))) % page
+	mem := buf[off : off+size : off+size]
+	if syscall.Mlock(mem) != nil {
+		return mem[:n:n], nil
+	}
+	return mem[:n:n], mem
+}
+
+func unlockMemory(b []byte) {
+	syscall.Munlock(b)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package core
+
+// Memory locking is only implemented on Linux. Elsewhere secrets are still
+// wiped after use.
+
+func allocSecret(n int) (b, locked []byte) { return make([]byte, n), nil }
+
+func unlockMemory(b []byte) {}
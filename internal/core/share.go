@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -22,6 +23,14 @@ const (
 	DefaultRecoveryURL = "https://eljojo.github.io/rememory/recover.html"
 )
 
+// Errors returned when checking shares. Match them with errors.Is.
+var (
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrThresholdNotMet  = errors.New("not enough shares")
+	ErrMixedSeals       = errors.New("shares are from different seals")
+	ErrDuplicateShare   = errors.New("duplicate share")
+)
+
 // Share represents a single Shamir share with metadata.
 type Share struct {
 	Version   int       // Format version (1 or 2)
@@ -206,7 +215,41 @@ func (s *Share) Verify() error {
 	}
 	computed := HashBytes(s.Data)
 	if !VerifyHash(computed, s.Checksum) {
-		return fmt.Errorf("share checksum verification failed")
+		return fmt.Errorf("share %w", ErrChecksumMismatch)
+	}
+	return nil
+}
+
+// CheckShares checks that shares come from the same seal, are enough to
+// recover, and don't repeat.
+func CheckShares(shares []*Share) error {
+	if len(shares) == 0 {
+		return fmt.Errorf("%w: no shares provided", ErrThresholdNotMet)
+	}
+
+	first := shares[0]
+	for i, share := range shares[1:] {
+		if share.Version != first.Version {
+			return fmt.Errorf("%w: share %d has different version (v%d vs v%d) — all shares must be from the same bundle", ErrMixedSeals, i+2, share.Version, first.Version)
+		}
+		if share.Total != first.Total {
+			return fmt.Errorf("%w: share %d has different total (%d vs %d)", ErrMixedSeals, i+2, share.Total, first.Total)
+		}
+		if share.Threshold != first.Threshold {
+			return fmt.Errorf("%w: share %d has different threshold (%d vs %d)", ErrMixedSeals, i+2, share.Threshold, first.Threshold)
+		}
+	}
+
+	if len(shares) < first.Threshold {
+		return fmt.Errorf("%w: need at least %d shares to recover (you provided %d)", ErrThresholdNotMet, first.Threshold, len(shares))
+	}
+
+	seen := make(map[int]bool)
+	for _, share := range shares {
+		if seen[share.Index] {
+			return fmt.Errorf("%w: index %d", ErrDuplicateShare, share.Index)
+		}
+		seen[share.Index] = true
 	}
 	return nil
 }
@@ -264,7 +307,7 @@ func ParseCompact(s string) (*Share, error) {
 	// Verify short checksum
 	expectedCheck := shortChecksum(data)
 	if parts[5] != expectedCheck {
-		return nil, fmt.Errorf("invalid compact share: checksum mismatch (got %s, want %s)", parts[5], expectedCheck)
+		return nil, fmt.Errorf("invalid compact share: %w (got %s, want %s)", ErrChecksumMismatch, parts[5], expectedCheck)
 	}
 
 	return &Share{
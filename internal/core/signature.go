@@ -0,0 +1,229 @@
+package core
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	SignatureBegin = "-----BEGIN REMEMORY SIGNATURE-----"
+	SignatureEnd   = "-----END REMEMORY SIGNATURE-----"
+
+	// SignatureFileName is the file in a bundle ZIP that holds its signature.
+	SignatureFileName = "SIGNATURE.txt"
+
+	// signatureHeader is the first line of a bundle signature's signed message.
+	signatureHeader = "rememory bundle signature v1"
+
+	sshEd25519 = "ssh-ed25519"
+)
+
+// ErrBadSignature is returned when a bundle's signature doesn't match its
+// files or its key. Match it with errors.Is.
+var ErrBadSignature = errors.New("bad signature")
+
+// SignedFile is a file covered by a bundle signature.
+type SignedFile struct {
+	Name     string
+	Checksum string // sha256:...
+}
+
+// BundleSignature is the owner's signature over every file of one bundle.
+// The README footer's checksums are in the same ZIP as the files, so anyone
+// who changes a file can change them too; the signature can only be made
+// with the owner's key.
+type BundleSignature struct {
+	PublicKey        string // OpenSSH format: "ssh-ed25519 AAAA..."
+	ManifestChecksum string // MANIFEST.age, also when it's embedded in recover.html
+	Files            []SignedFile
+	Signature        string // base64 Ed25519 signature of Message
+}
+
+// SignBundle signs the checksums of a bundle's files with an Ed25519 key.
+// signer is usually an ed25519.PrivateKey, or a key held by ssh-agent.
+func SignBundle(signer crypto.Signer, manifestChecksum string, files []SignedFile) (*BundleSignature, error) {
+	pub, ok := signer.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key must be Ed25519, got %T", signer.Public())
+	}
+	s := &BundleSignature{
+		PublicKey:        SSHPublicKey(pub),
+		ManifestChecksum: manifestChecksum,
+		Files:            files,
+	}
+	sig, err := signer.Sign(rand.Reader, []byte(s.Message()), crypto.Hash(0))
+	if err != nil {
+		return nil, fmt.Errorf("signing bundle: %w", err)
+	}
+	s.Signature = base64.StdEncoding.EncodeToString(sig)
+	return s, nil
+}
+
+// Message returns the text that is signed: every field except the signature.
+func (s *BundleSignature) Message() string {
+	var sb strings.Builder
+	sb.WriteString(signatureHeader + "\n")
+	sb.WriteString(fmt.Sprintf("Key: %s\n", s.PublicKey))
+	sb.WriteString(fmt.Sprintf("Manifest: %s\n", s.ManifestChecksum))
+	for _, f := range s.Files {
+		sb.WriteString(fmt.Sprintf("File: %s %s\n", f.Checksum, f.Name))
+	}
+	return sb.String()
+}
+
+// Encode returns the signature in the text form stored in SIGNATURE.txt.
+func (s *BundleSignature) Encode() string {
+	return SignatureBegin + "\n" + s.Message() + fmt.Sprintf("Signature: %s\n", s.Signature) + SignatureEnd + "\n"
+}
+
+// ParseBundleSignature reads a signature from the contents of SIGNATURE.txt.
+func ParseBundleSignature(content []byte) (*BundleSignature, error) {
+	text := string(content)
+	start := strings.Index(text, SignatureBegin)
+	end := strings.Index(text, SignatureEnd)
+	if start == -1 || end == -1 || end < start {
+		return nil, fmt.Errorf("no signature found")
+	}
+	lines := strings.Split(strings.TrimSpace(text[start+len(SignatureBegin):end]), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != signatureHeader {
+		return nil, fmt.Errorf("unsupported signature format")
+	}
+
+	s := &BundleSignature{}
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Key":
+			s.PublicKey = value
+		case "Manifest":
+			s.ManifestChecksum = value
+		case "File":
+			checksum, name, ok := strings.Cut(value, " ")
+			if !ok {
+				return nil, fmt.Errorf("invalid file line: %s", line)
+			}
+			s.Files = append(s.Files, SignedFile{Name: name, Checksum: checksum})
+		case "Signature":
+			s.Signature = value
+		}
+	}
+	if s.PublicKey == "" || s.Signature == "" {
+		return nil, fmt.Errorf("signature is missing its key or value")
+	}
+	return s, nil
+}
+
+// Verify checks the signature against the signature's own public key. It
+// shows the signed checksums weren't altered; who signed them is told by
+// Fingerprint, and must be compared with the owner's key.
+func (s *BundleSignature) Verify() error {
+	pub, err := ParseSSHPublicKey(s.PublicKey)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding")
+	}
+	if !ed25519.Verify(pub, []byte(s.Message()), sig) {
+		return fmt.Errorf("signature doesn't match the signed files: %w", ErrBadSignature)
+	}
+	return nil
+}
+
+// VerifyFiles checks the signature, and that files (a bundle's contents by
+// name, without SIGNATURE.txt) are exactly the signed files.
+func (s *BundleSignature) VerifyFiles(files map[string][]byte) error {
+	if err := s.Verify(); err != nil {
+		return err
+	}
+	signed := make(map[string]bool, len(s.Files))
+	for _, f := range s.Files {
+		data, ok := files[f.Name]
+		if !ok {
+			return fmt.Errorf("signed file %s is missing: %w", f.Name, ErrBadSignature)
+		}
+		if !VerifyHash(HashBytes(data), f.Checksum) {
+			return fmt.Errorf("%s was changed after signing: %w", f.Name, ErrBadSignature)
+		}
+		signed[f.Name] = true
+	}
+	var extra []string
+	for name := range files {
+		if !signed[name] {
+			extra = append(extra, name)
+		}
+	}
+	if len(extra) > 0 {
+		sort.Strings(extra)
+		return fmt.Errorf("%s wasn't signed: %w", strings.Join(extra, ", "), ErrBadSignature)
+	}
+	return nil
+}
+
+// Fingerprint returns the fingerprint of the signing key, as shown by
+// ssh-keygen -l (e.g. "SHA256:...").
+func (s *BundleSignature) Fingerprint() string {
+	pub, err := ParseSSHPublicKey(s.PublicKey)
+	if err != nil {
+		return ""
+	}
+	return KeyFingerprint(pub)
+}
+
+// SSHPublicKey returns an Ed25519 public key in OpenSSH authorized_keys
+// format, without a comment.
+func SSHPublicKey(pub ed25519.PublicKey) string {
+	return sshEd25519 + " " + base64.StdEncoding.EncodeToString(sshWireKey(pub))
+}
+
+// ParseSSHPublicKey reads an Ed25519 public key in OpenSSH authorized_keys
+// format, such as the contents of id_ed25519.pub. A trailing comment is
+// ignored.
+func ParseSSHPublicKey(s string) (ed25519.PublicKey, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	if fields[0] != sshEd25519 {
+		return nil, fmt.Errorf("unsupported key type %s (only %s keys can sign bundles)", fields[0], sshEd25519)
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding")
+	}
+	pub := ed25519.PublicKey(blob[len(blob)-min(len(blob), ed25519.PublicKeySize):])
+	if len(pub) != ed25519.PublicKeySize || !bytes.Equal(sshWireKey(pub), blob) {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	return pub, nil
+}
+
+// KeyFingerprint returns the SHA-256 fingerprint of an Ed25519 public key,
+// in the format of ssh-keygen -l.
+func KeyFingerprint(pub ed25519.PublicKey) string {
+	h := sha256.Sum256(sshWireKey(pub))
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(h[:])
+}
+
+// sshWireKey encodes an Ed25519 public key in the SSH wire format: the key
+// type and the key, each prefixed with its length.
+func sshWireKey(pub ed25519.PublicKey) []byte {
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, uint32(len(sshEd25519)))
+	b = append(b, sshEd25519...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(pub)))
+	return append(b, pub...)
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// transcriptKeyLabel separates the transcript signing key from other uses of
+// the passphrase.
+const transcriptKeyLabel = "rememory reshare signing key v1"
+
+// transcriptHeader is the first line of a transcript's signed message.
+const transcriptHeader = "rememory reshare transcript v1"
+
+// ReshareTranscript records who took part in a reshare and what it produced.
+// It is signed with a key derived from the passphrase, so only someone who
+// reconstructed the passphrase could have written it. Anyone who recovers
+// the passphrase later can confirm the key with TranscriptPublicKey.
+type ReshareTranscript struct {
+	Project          string    `yaml:"project" json:"project"`
+	At               time.Time `yaml:"at" json:"at"`
+	ManifestChecksum string    `yaml:"manifest_checksum" json:"manifest_checksum"`
+	OldThreshold     int       `yaml:"old_threshold" json:"old_threshold"`
+	OldTotal         int       `yaml:"old_total" json:"old_total"`
+	// Participants are the holders whose shares were combined, as "Name (share N)"
+	Participants []string `yaml:"participants" json:"participants"`
+	NewThreshold int      `yaml:"new_threshold" json:"new_threshold"`
+	NewHolders   []string `yaml:"new_holders" json:"new_holders"`
+	PublicKey    string   `yaml:"public_key" json:"public_key"` // hex Ed25519 key
+	Signature    string   `yaml:"signature" json:"signature"`   // base64 Ed25519 signature of Message
+}
+
+// transcriptKey derives the Ed25519 signing key for a passphrase.
+func transcriptKey(passphrase *Secret) ed25519.PrivateKey {
+	mac := hmac.New(sha256.New, passphrase.Bytes())
+	mac.Write([]byte(transcriptKeyLabel))
+	return ed25519.NewKeyFromSeed(mac.Sum(nil))
+}
+
+// TranscriptPublicKey returns the hex public key transcripts are signed with
+// for a passphrase.
+func TranscriptPublicKey(passphrase *Secret) string {
+	key := transcriptKey(passphrase)
+	defer Wipe(key)
+	return hex.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// Message returns the text that is signed: every field except the signature.
+func (t *ReshareTranscript) Message() string {
+	var sb strings.Builder
+	sb.WriteString(transcriptHeader + "\n")
+	sb.WriteString(fmt.Sprintf("Project: %s\n", t.Project))
+	sb.WriteString(fmt.Sprintf("Date: %s\n", t.At.UTC().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Manifest: %s\n", t.ManifestChecksum))
+	sb.WriteString(fmt.Sprintf("From: %d of %d\n", t.OldThreshold, t.OldTotal))
+	sb.WriteString(fmt.Sprintf("Participants: %s\n", strings.Join(t.Participants, ", ")))
+	sb.WriteString(fmt.Sprintf("To: %d of %d\n", t.NewThreshold, len(t.NewHolders)))
+	sb.WriteString(fmt.Sprintf("Holders: %s\n", strings.Join(t.NewHolders, ", ")))
+	sb.WriteString(fmt.Sprintf("Key: %s\n", t.PublicKey))
+	return sb.String()
+}
+
+// Sign sets the transcript's public key and signature using the passphrase.
+func (t *ReshareTranscript) Sign(passphrase *Secret) {
+	key := transcriptKey(passphrase)
+	defer Wipe(key)
+	t.PublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	t.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(t.Message())))
+}
+
+// Verify checks the signature against the transcript's own public key.
+// It shows the transcript wasn't altered; that the key belongs to the
+// project's passphrase can only be checked with TranscriptPublicKey.
+func (t *ReshareTranscript) Verify() error {
+	pub, err := hex.DecodeString(t.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(t.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding")
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), []byte(t.Message()), sig) {
+		return fmt.Errorf("signature doesn't match the transcript")
+	}
+	return nil
+}
+
+// Encode returns the transcript as text: the signed message followed by the signature.
+func (t *ReshareTranscript) Encode() string {
+	return t.Message() + fmt.Sprintf("Signature: %s\n", t.Signature)
+}
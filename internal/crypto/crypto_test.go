@@ -52,6 +52,31 @@ func TestGeneratePassphrase(t *testing.T) {
 	})
 }
 
+func TestGenerateRawPassphrase(t *testing.T) {
+	raw, passphrase, err := GenerateRawPassphrase(DefaultPassphraseBytes)
+	if err != nil {
+		t.Fatalf("GenerateRawPassphrase: %v", err)
+	}
+	if raw.Len() != DefaultPassphraseBytes {
+		t.Errorf("got %d raw bytes, want %d", raw.Len(), DefaultPassphraseBytes)
+	}
+	if string(passphrase.Bytes()) != core.RecoverPassphrase(raw.Bytes(), 2) {
+		t.Error("passphrase should be the base64url encoding of the raw bytes")
+	}
+
+	held := [][]byte{raw.Bytes(), passphrase.Bytes()}
+	raw.Wipe()
+	passphrase.Wipe()
+	for i, b := range held {
+		for _, c := range b {
+			if c != 0 {
+				t.Errorf("secret %d not wiped", i)
+				break
+			}
+		}
+	}
+}
+
 func TestHashFile(t *testing.T) {
 	// Create a temp file
 	dir := t.TempDir()
@@ -2,8 +2,9 @@ package crypto
 
 import (
 	"crypto/rand"
-	"encoding/base64"
 	"fmt"
+
+	"github.com/eljojo/rememory/internal/core"
 )
 
 const (
@@ -14,26 +15,34 @@ const (
 
 // GeneratePassphrase creates a cryptographically secure passphrase.
 // The passphrase is URL-safe base64 encoded (no padding) for easy handling.
+// Prefer GenerateRawPassphrase for passphrases that protect real data: a
+// string can't be wiped.
 func GeneratePassphrase(numBytes int) (string, error) {
-	_, passphrase, err := GenerateRawPassphrase(numBytes)
-	return passphrase, err
+	raw, passphrase, err := GenerateRawPassphrase(numBytes)
+	if err != nil {
+		return "", err
+	}
+	defer raw.Wipe()
+	defer passphrase.Wipe()
+	return string(passphrase.Bytes()), nil
 }
 
 // GenerateRawPassphrase creates random bytes and returns both the raw bytes
-// and the base64url-encoded passphrase string. Protocol v2 splits the raw bytes
-// via Shamir (instead of the encoded string), then base64url-encodes after
-// recombining. The passphrase string is used for age encryption.
-func GenerateRawPassphrase(numBytes int) (raw []byte, passphrase string, err error) {
+// and the base64url-encoded passphrase, each in a core.Secret. Protocol v2
+// splits the raw bytes via Shamir (instead of the encoded string), then
+// base64url-encodes after recombining. The passphrase is used for age
+// encryption. Wipe both when done.
+func GenerateRawPassphrase(numBytes int) (raw, passphrase *core.Secret, err error) {
 	if numBytes < 16 {
-		return nil, "", fmt.Errorf("passphrase must be at least 16 bytes, got %d", numBytes)
+		return nil, nil, fmt.Errorf("passphrase must be at least 16 bytes, got %d", numBytes)
 	}
 
-	raw = make([]byte, numBytes)
-	if _, err := rand.Read(raw); err != nil {
-		return nil, "", fmt.Errorf("generating random bytes: %w", err)
+	raw = core.NewSecret(make([]byte, numBytes))
+	if _, err := rand.Read(raw.Bytes()); err != nil {
+		raw.Wipe()
+		return nil, nil, fmt.Errorf("generating random bytes: %w", err)
 	}
 
 	// URL-safe base64 without padding for easy copy-paste
-	passphrase = base64.RawURLEncoding.EncodeToString(raw)
-	return raw, passphrase, nil
+	return raw, core.RecoverSecret(raw.Bytes(), 2), nil
 }
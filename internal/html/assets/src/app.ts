@@ -18,6 +18,9 @@ declare const t: TranslationFunction;
   // Import shared utilities
   const { escapeHtml, formatSize, toast, showInlineError, clearInlineError } = window.rememoryUtils;
 
+  // How much of the manifest is passed to WASM at a time while decrypting
+  const DECRYPT_CHUNK_SIZE = 1 << 20;
+
   // State
   const state: RecoveryState = {
     shares: [],
@@ -58,6 +61,17 @@ declare const t: TranslationFunction;
     qrScannerModal: HTMLElement | null;
     qrVideo: HTMLVideoElement | null;
     qrScannerClose: HTMLButtonElement | null;
+    challengeSection: HTMLElement | null;
+    challengeInput: HTMLInputElement | null;
+    challengeBtn: HTMLButtonElement | null;
+    challengeCode: HTMLElement | null;
+    qrScannerHint: HTMLElement | null;
+    paperSection: HTMLElement | null;
+    paperToggleBtn: HTMLButtonElement | null;
+    paperScanBtn: HTMLButtonElement | null;
+    paperArea: HTMLElement | null;
+    paperInput: HTMLTextAreaElement | null;
+    paperStatus: HTMLElement | null;
   }
 
   // DOM elements
@@ -89,6 +103,17 @@ declare const t: TranslationFunction;
     qrScannerModal: document.getElementById('qr-scanner-modal'),
     qrVideo: document.getElementById('qr-video') as HTMLVideoElement | null,
     qrScannerClose: document.getElementById('qr-scanner-close') as HTMLButtonElement | null,
+    challengeSection: document.getElementById('challenge-section'),
+    challengeInput: document.getElementById('challenge-input') as HTMLInputElement | null,
+    challengeBtn: document.getElementById('challenge-btn') as HTMLButtonElement | null,
+    challengeCode: document.getElementById('challenge-code'),
+    qrScannerHint: document.getElementById('qr-scanner-hint-text'),
+    paperSection: document.getElementById('paper-section'),
+    paperToggleBtn: document.getElementById('paper-toggle-btn') as HTMLButtonElement | null,
+    paperScanBtn: document.getElementById('paper-scan-btn') as HTMLButtonElement | null,
+    paperArea: document.getElementById('paper-area'),
+    paperInput: document.getElementById('paper-input') as HTMLTextAreaElement | null,
+    paperStatus: document.getElementById('paper-status'),
   };
 
   // Personalization data (embedded in HTML)
@@ -226,6 +251,7 @@ declare const t: TranslationFunction;
     setupButtons();
     setupPaste();
     setupScanner();
+    setupPaper();
 
     // Render contact list immediately (doesn't need WASM)
     if (personalization?.otherFriends && personalization.otherFriends.length > 0) {
@@ -263,23 +289,40 @@ declare const t: TranslationFunction;
 
         updateSharesUI();
         updateContactList();
+
+        // Practice bundles can't answer challenges for the real seal
+        if (!personalization.practice) {
+          elements.challengeSection?.classList.remove('hidden');
+        }
       }
     }
 
     // Load embedded manifest if available (included when MANIFEST.age is small enough)
     if (personalization.manifestB64) {
-      const binary = atob(personalization.manifestB64);
-      const bytes = new Uint8Array(binary.length);
-      for (let i = 0; i < binary.length; i++) {
-        bytes[i] = binary.charCodeAt(i);
-      }
-      state.manifest = bytes;
-      showManifestLoaded('MANIFEST.age', state.manifest.length, 'embedded');
+      const { manifest, repaired } = embeddedManifest(personalization);
+      state.manifest = manifest;
+      showManifestLoaded('MANIFEST.age', state.manifest.length, 'embedded', repaired);
     }
 
     checkRecoverReady();
   }
 
+  // Decodes the MANIFEST.age embedded in recover.html, and repairs it with
+  // its parity data if it's damaged. One that can't be repaired is kept as
+  // is, and decrypting it reports the error.
+  function embeddedManifest(data: PersonalizationData): { manifest: Uint8Array; repaired: number } {
+    const decode = (b64: string) => Uint8Array.from(atob(b64), c => c.charCodeAt(0));
+    const manifest = decode(data.manifestB64!);
+    if (!data.parityB64) {
+      return { manifest, repaired: 0 };
+    }
+    const result = window.rememoryRepairManifest(manifest, decode(data.parityB64));
+    if (result.error || !result.data) {
+      return { manifest, repaired: 0 };
+    }
+    return { manifest: result.data, repaired: result.repaired };
+  }
+
   // ============================================
   // URL Fragment Share Loading
   // ============================================
@@ -634,6 +677,9 @@ declare const t: TranslationFunction;
 
   let scannerStream: MediaStream | null = null;
   let scannerAnimFrame: number | null = null;
+  // 'share' stops at the first share found; 'paper' keeps scanning the
+  // printed MANIFEST.age until every line is read
+  let scannerMode: 'share' | 'paper' = 'share';
 
   function setupScanner(): void {
     // Only show the button if BarcodeDetector is available
@@ -651,7 +697,11 @@ declare const t: TranslationFunction;
     elements.qrScannerClose?.addEventListener('click', closeScanner);
   }
 
-  async function openScanner(): Promise<void> {
+  async function openScanner(mode: 'share' | 'paper' = 'share'): Promise<void> {
+    scannerMode = mode;
+    if (elements.qrScannerHint) {
+      elements.qrScannerHint.textContent = mode === 'paper' ? t('paper_scan_hint') : t('scan_hint');
+    }
     elements.qrScannerModal?.classList.remove('hidden');
 
     try {
@@ -682,6 +732,17 @@ declare const t: TranslationFunction;
       detector.detect(elements.qrVideo).then(barcodes => {
         if (!scannerStream) return; // Scanner was closed
 
+        if (scannerMode === 'paper') {
+          for (const barcode of barcodes) {
+            if (addPaperCode(barcode.rawValue) && state.manifest) {
+              closeScanner();
+              return;
+            }
+          }
+          scannerAnimFrame = requestAnimationFrame(scanLoop);
+          return;
+        }
+
         for (const barcode of barcodes) {
           const value = barcode.rawValue.trim();
           // Check for compact share format directly or URL with fragment
@@ -743,6 +804,125 @@ declare const t: TranslationFunction;
     elements.qrScannerModal?.classList.add('hidden');
   }
 
+  // ============================================
+  // MANIFEST.age on Paper
+  // ============================================
+
+  // QR codes scanned from the pages README.pdf prints MANIFEST.age on.
+  // Everything typed and scanned is read again each time, so codes and
+  // lines can come in any order.
+  const paperCodes: string[] = [];
+
+  function setupPaper(): void {
+    elements.paperToggleBtn?.addEventListener('click', () => {
+      const isHidden = elements.paperArea?.classList.contains('hidden');
+      elements.paperArea?.classList.toggle('hidden', !isHidden);
+      if (isHidden) {
+        elements.paperInput?.focus();
+      }
+    });
+
+    elements.paperInput?.addEventListener('input', () => {
+      readPaper();
+    });
+
+    if (!('BarcodeDetector' in window)) return;
+
+    elements.paperScanBtn?.classList.remove('hidden');
+    elements.paperScanBtn?.addEventListener('click', () => {
+      if (!state.wasmReady) {
+        toast.warning(t('error_not_ready_title'), t('error_not_ready_message'), t('error_not_ready_guidance'));
+        return;
+      }
+      elements.paperArea?.classList.remove('hidden');
+      openScanner('paper');
+    });
+  }
+
+  // addPaperCode keeps a scanned QR code of the printed MANIFEST.age, and
+  // reads everything again. Returns false for codes that aren't one, or
+  // were already scanned.
+  function addPaperCode(code: string): boolean {
+    const value = code.trim();
+    if (!value.toUpperCase().startsWith('MANIFEST.AGE') || paperCodes.includes(value)) {
+      return false;
+    }
+    paperCodes.push(value);
+    readPaper();
+    return true;
+  }
+
+  // readPaper puts MANIFEST.age together from everything typed and scanned
+  // so far, and says how far along it is.
+  function readPaper(): void {
+    if (!state.wasmReady) return;
+
+    const text = [elements.paperInput?.value || '', ...paperCodes].join('\n');
+    const result = window.rememoryReadPaperManifest(text);
+
+    const parts: string[] = [];
+    if (result.lines === 0) {
+      parts.push(t('paper_no_header'));
+    } else {
+      parts.push(t('paper_progress', result.lines - result.missing.length, result.lines));
+    }
+    if (result.badLines.length > 0) {
+      parts.push(t('paper_bad_lines', result.badLines.join(', ')));
+    }
+    if (result.mismatch) {
+      parts.push(t('paper_mismatch'));
+    }
+    if (result.error) {
+      parts.push(result.error);
+    }
+    const status = parts.join(' ');
+    if (elements.paperStatus) {
+      elements.paperStatus.textContent = status;
+    }
+    if (scannerMode === 'paper' && elements.qrScannerHint) {
+      elements.qrScannerHint.textContent = status;
+    }
+
+    if (result.data && !result.mismatch) {
+      state.manifest = result.data;
+      showManifestLoaded('MANIFEST.age', state.manifest.length, 'paper');
+      checkRecoverReady();
+    }
+  }
+
+  // handlePaperImages reads the QR codes in photos or scans of the printed
+  // MANIFEST.age.
+  async function handlePaperImages(files: File[]): Promise<void> {
+    if (!('BarcodeDetector' in window)) {
+      if (elements.manifestDropZone) {
+        showError(t('paper_images_unsupported'), {
+          title: t('error_wrong_manifest_title'),
+          inline: true,
+          targetElement: elements.manifestDropZone
+        });
+      }
+      return;
+    }
+
+    const detector = new BarcodeDetector({ formats: ['qr_code'] });
+    let found = 0;
+    for (const file of files) {
+      const bitmap = await createImageBitmap(file);
+      try {
+        for (const barcode of await detector.detect(bitmap)) {
+          if (addPaperCode(barcode.rawValue)) found++;
+        }
+      } finally {
+        bitmap.close();
+      }
+    }
+
+    elements.paperArea?.classList.remove('hidden');
+    if (found === 0 && !state.manifest && elements.paperStatus) {
+      elements.paperStatus.textContent = t('paper_no_codes');
+    }
+  }
+
   // ============================================
   // Share File Handling
   // ============================================
@@ -776,6 +956,20 @@ declare const t: TranslationFunction;
     const zipData = new Uint8Array(buffer);
 
     const result = window.rememoryExtractBundle(zipData);
+    if (result.badSignature) {
+      if (elements.shareDropZone) {
+        showError(
+          t('error_bundle_signature_message', file.name),
+          {
+            title: t('error_bundle_extract_title'),
+            guidance: t('error_bundle_signature_guidance'),
+            inline: true,
+            targetElement: elements.shareDropZone
+          }
+        );
+      }
+      return;
+    }
     if (result.error || !result.share) {
       if (elements.shareDropZone) {
         showError(
@@ -792,6 +986,9 @@ declare const t: TranslationFunction;
     }
 
     const share = result.share;
+    if (result.signedBy) {
+      share.signedBy = result.signedBy;
+    }
 
     if (state.shares.some(s => s.index === share.index)) {
       errorHandlers.duplicateShare(share.index);
@@ -808,7 +1005,7 @@ declare const t: TranslationFunction;
 
     if (result.manifest && !state.manifest) {
       state.manifest = result.manifest;
-      showManifestLoaded('MANIFEST.age', state.manifest.length, 'bundle');
+      showManifestLoaded('MANIFEST.age', state.manifest.length, 'bundle', result.repairedBlocks);
     }
 
     checkRecoverReady();
@@ -894,6 +1091,7 @@ declare const t: TranslationFunction;
         <span class="icon">&#9989;</span>
         <div class="details">
           <div class="name">${escapeHtml(displayName)}${holderLabel}</div>
+          ${share.signedBy ? `<div class="meta">${t('signed_by', escapeHtml(share.signedBy))}</div>` : ''}
         </div>
         ${showRemove ? `<button class="remove" data-idx="${idx}" title="${t('remove')}">&times;</button>` : ''}
       `;
@@ -951,6 +1149,12 @@ declare const t: TranslationFunction;
     try {
       const file = fileArray[0];
 
+      const images = fileArray.filter(f => f.type.startsWith('image/'));
+      if (images.length > 0) {
+        await handlePaperImages(images);
+        return;
+      }
+
       if (file.name.endsWith('.zip') || file.type === 'application/zip') {
         await handleBundleZip(file);
         return;
@@ -1023,13 +1227,9 @@ declare const t: TranslationFunction;
         return;
       }
 
-      const binary = atob(personalizationData.manifestB64);
-      const bytes = new Uint8Array(binary.length);
-      for (let i = 0; i < binary.length; i++) {
-        bytes[i] = binary.charCodeAt(i);
-      }
-      state.manifest = bytes;
-      showManifestLoaded('MANIFEST.age', state.manifest.length, 'html');
+      const { manifest, repaired } = embeddedManifest(personalizationData);
+      state.manifest = manifest;
+      showManifestLoaded('MANIFEST.age', state.manifest.length, 'html', repaired);
 
       // Also extract the share if present and we don't already have one
       if (personalizationData.holderShare && state.wasmReady) {
@@ -1063,8 +1263,9 @@ declare const t: TranslationFunction;
     }
   }
 
-  function showManifestLoaded(filename: string, size: number, source: 'file' | 'bundle' | 'embedded' | 'html' = 'file'): void {
+  function showManifestLoaded(filename: string, size: number, source: 'file' | 'bundle' | 'embedded' | 'html' | 'paper' = 'file', repaired = 0): void {
     elements.manifestDropZone?.classList.add('hidden');
+    elements.paperSection?.classList.add('hidden');
 
     if (elements.manifestStatus) {
       const sourceLabels: Record<string, string> = {
@@ -1072,6 +1273,7 @@ declare const t: TranslationFunction;
         bundle: t('manifest_loaded_bundle'),
         embedded: t('manifest_loaded_embedded'),
         html: t('manifest_loaded_html'),
+        paper: t('manifest_loaded_paper'),
       };
       const sourceLabel = sourceLabels[source] || t('loaded');
       elements.manifestStatus.innerHTML = `
@@ -1079,6 +1281,7 @@ declare const t: TranslationFunction;
         <div style="flex: 1;">
           <strong>${escapeHtml(filename)}</strong> ${sourceLabel}
           <div style="font-size: 0.875rem; color: #6c757d;">${formatSize(size)}</div>
+          ${repaired > 0 ? `<div class="meta">${t('manifest_repaired', repaired)}</div>` : ''}
         </div>
         <button class="clear-manifest" title="${t('remove')}">&times;</button>
       `;
@@ -1095,6 +1298,7 @@ declare const t: TranslationFunction;
     elements.manifestStatus?.classList.add('hidden');
     elements.manifestStatus?.classList.remove('loaded');
     elements.manifestDropZone?.classList.remove('hidden');
+    elements.paperSection?.classList.remove('hidden');
     checkRecoverReady();
   }
 
@@ -1105,6 +1309,33 @@ declare const t: TranslationFunction;
   function setupButtons(): void {
     elements.recoverBtn?.addEventListener('click', startRecovery);
     elements.downloadAllBtn?.addEventListener('click', downloadAll);
+    elements.challengeBtn?.addEventListener('click', respondToChallenge);
+    elements.challengeInput?.addEventListener('keydown', (e: KeyboardEvent) => {
+      if (e.key === 'Enter') respondToChallenge();
+    });
+  }
+
+  // Answers the owner's proof-of-possession challenge with the holder's own
+  // share. Only the resulting code is shown; the share never leaves the page.
+  function respondToChallenge(): void {
+    const holderShare = state.shares.find(s => s.isHolder);
+    if (!holderShare || !elements.challengeInput || !elements.challengeCode) return;
+
+    clearInlineError(elements.challengeInput);
+    const words = elements.challengeInput.value.trim().split(/\s+/).filter(w => w);
+    if (words.length !== window.rememoryChallengeWords) {
+      elements.challengeCode.classList.add('hidden');
+      showInlineError(elements.challengeInput, t('challenge_invalid', window.rememoryChallengeWords));
+      return;
+    }
+
+    const result = window.rememoryRespondChallenge(holderShare.dataB64, words.join(' '));
+    if (result.error) {
+      showInlineError(elements.challengeInput, result.error);
+      return;
+    }
+    elements.challengeCode.textContent = t('challenge_code', result.code);
+    elements.challengeCode.classList.remove('hidden');
   }
 
   function checkRecoverReady(): void {
@@ -1154,33 +1385,39 @@ declare const t: TranslationFunction;
         dataB64: s.dataB64
       }));
 
-      const combineResult = window.rememoryCombineShares(sharesForCombine);
-      if (combineResult.error || !combineResult.passphrase) {
-        throw new Error(combineResult.error || 'Failed to combine shares');
-      }
-
-      const passphrase = combineResult.passphrase;
       setProgress(30);
 
+      // Shares are combined inside WASM, so the passphrase never reaches JS.
+      // The manifest is fed in chunks and files come back one at a time, so
+      // neither the whole manifest nor the whole archive is copied into WASM.
       setStatus(t('decrypting'));
-      const decryptResult = window.rememoryDecryptManifest(state.manifest!, passphrase);
-      if (decryptResult.error || !decryptResult.data) {
-        throw new Error(decryptResult.error || 'Failed to decrypt');
+      const manifest = state.manifest!;
+      const files: { name: string; size: number }[] = [];
+      const archive: BlobPart[] = [];
+      const stream = window.rememoryDecryptStream(sharesForCombine, {
+        size: manifest.length,
+        onFile: (name, data) => {
+          if (files.length === 0) setStatus(t('reading'));
+          files.push({ name, size: data.length });
+        },
+        onProgress: (read, size) => setProgress(30 + Math.round(65 * read / size)),
+        onArchive: chunk => archive.push(chunk as BlobPart)
+      });
+      if (stream.error) {
+        throw new Error(stream.error);
       }
 
-      setProgress(60);
-
-      state.decryptedArchive = decryptResult.data;
-
-      setStatus(t('reading'));
-      const extractResult = window.rememoryExtractTarGz(decryptResult.data);
-      if (extractResult.error || !extractResult.files) {
-        throw new Error(extractResult.error || 'Failed to extract');
+      try {
+        for (let offset = 0; offset < manifest.length; offset += DECRYPT_CHUNK_SIZE) {
+          await stream.write(manifest.subarray(offset, offset + DECRYPT_CHUNK_SIZE));
+        }
+      } catch {
+        // Decryption stopped; close() reports why
       }
+      // Files are only complete, and the manifest authenticated, once close() resolves
+      await stream.close();
 
-      setProgress(90);
-
-      const files = extractResult.files;
+      state.decryptedArchive = new Blob(archive, { type: 'application/gzip' });
 
       files.forEach(file => {
         const item = document.createElement('div');
@@ -1188,7 +1425,7 @@ declare const t: TranslationFunction;
         item.innerHTML = `
           <span class="icon">&#128196;</span>
           <span class="name">${escapeHtml(file.name)}</span>
-          <span class="size">${formatSize(file.data.length)}</span>
+          <span class="size">${formatSize(file.size)}</span>
         `;
         elements.filesList?.appendChild(item);
       });
@@ -1249,8 +1486,7 @@ declare const t: TranslationFunction;
   function downloadAll(): void {
     if (!state.decryptedArchive) return;
 
-    const blob = new Blob([state.decryptedArchive as BlobPart], { type: 'application/gzip' });
-    const url = URL.createObjectURL(blob);
+    const url = URL.createObjectURL(state.decryptedArchive);
     const a = document.createElement('a');
     a.href = url;
     a.download = 'manifest.tar.gz';
@@ -2,8 +2,10 @@
 
 import type {
   CreationState,
+  BundleConfig,
   BundleFile,
   GeneratedBundle,
+  SealEvent,
   TranslationFunction
 } from './types';
 
@@ -93,6 +95,7 @@ declare let currentLang: string;
     downloadAllSection: HTMLElement | null;
     downloadAllBtn: HTMLButtonElement | null;
     downloadYamlBtn: HTMLButtonElement | null;
+    downloadProjectBtn: HTMLButtonElement | null;
   }
 
   // DOM elements
@@ -125,7 +128,8 @@ declare let currentLang: string;
     bundlesList: document.getElementById('bundles-list'),
     downloadAllSection: document.getElementById('download-all-section'),
     downloadAllBtn: document.getElementById('download-all-btn') as HTMLButtonElement | null,
-    downloadYamlBtn: document.getElementById('download-yaml-btn') as HTMLButtonElement | null
+    downloadYamlBtn: document.getElementById('download-yaml-btn') as HTMLButtonElement | null,
+    downloadProjectBtn: document.getElementById('download-project-btn') as HTMLButtonElement | null
   };
 
   // ============================================
@@ -604,6 +608,7 @@ declare let currentLang: string;
     elements.generateBtn?.addEventListener('click', generateBundles);
     elements.downloadAllBtn?.addEventListener('click', downloadAllBundles);
     elements.downloadYamlBtn?.addEventListener('click', downloadProjectYaml);
+    elements.downloadProjectBtn?.addEventListener('click', downloadProjectArchive);
   }
 
   function checkGenerateReady(): void {
@@ -710,6 +715,7 @@ declare let currentLang: string;
     state.generating = true;
     state.generationComplete = false;
     state.bundles = [];
+    state.projectArchive = undefined;
 
     if (elements.generateBtn) elements.generateBtn.disabled = true;
     elements.progressBar?.classList.remove('hidden');
@@ -745,7 +751,7 @@ declare let currentLang: string;
         }));
       }
 
-      const config = {
+      const config: BundleConfig = {
         projectName: state.projectName,
         threshold: state.threshold,
         friends: friends,
@@ -753,19 +759,11 @@ declare let currentLang: string;
         version: window.VERSION || 'dev',
         githubURL: window.GITHUB_URL || 'https://github.com/eljojo/rememory',
         anonymous: state.anonymous,
-        defaultLanguage: currentLang || 'en'
+        defaultLanguage: currentLang || 'en',
+        onProgress: showSealProgress
       };
 
-      setProgress(10);
-      setStatus(t('archiving'));
-      await sleep(100);
-
-      setProgress(30);
-      setStatus(t('encrypting'));
-      await sleep(100);
-
-      setProgress(50);
-      setStatus(t('splitting'));
+      // Let the status paint before the WASM call takes over the thread
       await sleep(100);
 
       const result = window.rememoryCreateBundles(config);
@@ -774,9 +772,8 @@ declare let currentLang: string;
         throw new Error(result.error || 'Failed to create bundles');
       }
 
-      setProgress(80);
-
       state.bundles = result.bundles;
+      state.projectArchive = result.project;
 
       // Expose bundles for testing
       (window as unknown as { rememoryBundles?: GeneratedBundle[] }).rememoryBundles = result.bundles;
@@ -848,6 +845,18 @@ declare let currentLang: string;
     });
   }
 
+  // The seal's record, so 'rememory import-project' can manage it from the CLI
+  function downloadProjectArchive(): void {
+    if (!state.projectArchive) return;
+    const blob = new Blob([state.projectArchive.data as BlobPart], { type: 'application/zip' });
+    const url = URL.createObjectURL(blob);
+    const a = document.createElement('a');
+    a.href = url;
+    a.download = state.projectArchive.fileName;
+    a.click();
+    URL.revokeObjectURL(url);
+  }
+
   function downloadProjectYaml(): void {
     let yaml = `# ReMemory Project Configuration\n`;
     yaml += `# Generated: ${new Date().toISOString()}\n`;
@@ -898,6 +907,32 @@ declare let currentLang: string;
     }
   }
 
+  // Reflects the seal engine's events in the progress bar and status line
+  function showSealProgress(e: SealEvent): void {
+    switch (e.stage) {
+      case 'archive':
+        setProgress(10);
+        setStatus(t('archiving'));
+        break;
+      case 'encrypt':
+        setProgress(20);
+        setStatus(t('encrypting'));
+        break;
+      case 'split':
+        setProgress(30);
+        setStatus(t('splitting'));
+        break;
+      case 'verify':
+        setProgress(40);
+        setStatus(t('verifying'));
+        break;
+      case 'bundle':
+        setProgress(40 + Math.round(60 * (e.done || 0) / (e.total || 1)));
+        setStatus(t('bundling', e.done || 0, e.total || 0));
+        break;
+    }
+  }
+
   function setStatus(msg: string, type?: string): void {
     if (elements.statusMessage) {
       elements.statusMessage.textContent = msg;
@@ -14,6 +14,7 @@ export interface ParsedShare {
   dataB64: string;
   compact?: string;   // Compact-encoded string (e.g. RM1:2:5:3:BASE64:CHECK)
   isHolder?: boolean;  // True if this is the current user's share
+  signedBy?: string;   // Fingerprint of the key that signed its bundle
 }
 
 export interface ShareInput {
@@ -28,19 +29,32 @@ export interface ShareParseResult {
   share?: ParsedShare;
 }
 
-export interface CombineResult {
-  error?: string;
-  passphrase?: string;
-}
-
 // ============================================
 // Bundle Types
 // ============================================
 
 export interface BundleExtractResult {
   error?: string;
+  badSignature?: boolean; // The bundle was changed after it was signed
   share?: ParsedShare;
   manifest?: Uint8Array;
+  signedBy?: string;      // Fingerprint of the key that signed the bundle
+  repairedBlocks?: number; // Damaged blocks of MANIFEST.age rebuilt from its parity data
+}
+
+export interface RepairResult {
+  error?: string;
+  data?: Uint8Array;
+  repaired: number;
+}
+
+export interface PaperManifestResult {
+  error?: string | null;
+  data?: Uint8Array | null;
+  lines: number;
+  missing: number[];
+  badLines: number[];
+  mismatch: boolean;
 }
 
 export interface BundleFile {
@@ -55,6 +69,20 @@ export interface BundleConfig {
   files: BundleFile[];
   version: string;
   githubURL: string;
+  anonymous?: boolean;
+  defaultLanguage?: string;
+  recoveryURL?: string;
+  noEmbedManifest?: boolean;
+  noProjectManifest?: boolean; // Leave MANIFEST.age out of the project archive
+  onProgress?: (event: SealEvent) => void;
+}
+
+// Progress reported by the seal engine while creating bundles
+export interface SealEvent {
+  stage: 'archive' | 'encrypt' | 'split' | 'verify' | 'bundle';
+  friend?: string;
+  done?: number;
+  total?: number;
 }
 
 export interface GeneratedBundle {
@@ -63,9 +91,17 @@ export interface GeneratedBundle {
   data: Uint8Array;
 }
 
+// A project archive for 'rememory import-project': project.yml with the
+// seal's checksums, and MANIFEST.age
+export interface ProjectArchive {
+  fileName: string;
+  data: Uint8Array;
+}
+
 export interface BundleCreateResult {
   error?: string;
   bundles?: GeneratedBundle[];
+  project?: ProjectArchive;
 }
 
 // ============================================
@@ -77,6 +113,20 @@ export interface DecryptResult {
   data?: Uint8Array;
 }
 
+export interface DecryptStreamOptions {
+  size: number; // Total manifest bytes, for onProgress
+  onFile: (name: string, data: Uint8Array) => void;
+  onProgress?: (read: number, size: number) => void;
+  onArchive?: (chunk: Uint8Array) => void; // The decrypted tar.gz, as it's read
+}
+
+export interface DecryptStream {
+  error?: string;
+  write(chunk: Uint8Array): Promise<void>;
+  close(): Promise<{ files: number }>;
+  abort(): void;
+}
+
 export interface ExtractedFile {
   name: string;
   data: Uint8Array;
@@ -126,6 +176,8 @@ export interface PersonalizationData {
   total: number;
   language?: string;
   manifestB64?: string; // Base64-encoded MANIFEST.age (when small enough to embed)
+  parityB64?: string; // Base64-encoded parity data of the embedded MANIFEST.age
+  practice?: boolean; // Drill bundle (a PRACTICE banner is added to the page)
 }
 
 // ============================================
@@ -140,7 +192,7 @@ export interface RecoveryState {
   wasmReady: boolean;
   recovering: boolean;
   recoveryComplete: boolean;
-  decryptedArchive?: Uint8Array;
+  decryptedArchive?: Blob;
 }
 
 export interface CreationState {
@@ -149,6 +201,7 @@ export interface CreationState {
   threshold: number;
   files: BundleFile[];
   bundles: GeneratedBundle[];
+  projectArchive?: ProjectArchive;
   wasmReady: boolean;
   generating: boolean;
   generationComplete: boolean;
@@ -188,12 +241,16 @@ declare global {
 
     // Recovery functions (recover.wasm)
     rememoryParseShare(content: string): ShareParseResult;
-    rememoryCombineShares(shares: ShareInput[]): CombineResult;
-    rememoryDecryptManifest(manifest: Uint8Array, passphrase: string): DecryptResult;
+    rememoryDecryptManifest(manifest: Uint8Array, shares: ShareInput[]): DecryptResult;
     rememoryExtractTarGz(data: Uint8Array): ExtractResult;
+    rememoryDecryptStream(shares: ShareInput[], options: DecryptStreamOptions): DecryptStream;
     rememoryExtractBundle(zipData: Uint8Array): BundleExtractResult;
+    rememoryRepairManifest(manifest: Uint8Array, parity: Uint8Array): RepairResult;
+    rememoryReadPaperManifest(text: string): PaperManifestResult;
     rememoryParseCompactShare(compact: string): ShareParseResult;
     rememoryDecodeWords(words: string[]): { data: Uint8Array; index: number; checksum: string; error?: string };
+    rememoryRespondChallenge(dataB64: string, challenge: string): { code: string; error?: string };
+    rememoryChallengeWords: number;
 
     // Creation functions (create.wasm)
     rememoryCreateBundles(config: BundleConfig): BundleCreateResult;
@@ -2,6 +2,7 @@ package html
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"strings"
 )
@@ -20,3 +21,12 @@ func applyCSPNonce(html string) string {
 	nonce := generateCSPNonce()
 	return strings.ReplaceAll(html, "{{CSP_NONCE}}", nonce)
 }
+
+// DeriveCSPNonce returns a nonce derived from seed instead of a random one,
+// so the same page can be rebuilt byte for byte. A static page gains little
+// from a random nonce: it's written into the file next to the scripts it
+// allows.
+func DeriveCSPNonce(seed string) string {
+	h := sha256.Sum256([]byte("rememory csp nonce\n" + seed))
+	return base64.StdEncoding.EncodeToString(h[:16])
+}
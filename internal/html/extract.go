@@ -1,6 +1,7 @@
 package html
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 // from the PERSONALIZATION JSON embedded in recover.html.
 type personalizationManifest struct {
 	ManifestB64 string `json:"manifestB64"`
+	ParityB64   string `json:"parityB64"`
 }
 
 // personalizationRe matches the PERSONALIZATION JSON in recover.html.
@@ -48,3 +50,64 @@ func ExtractManifestFromHTML(htmlContent []byte) ([]byte, error) {
 
 	return data, nil
 }
+
+// ExtractParityFromHTML returns the parity data of the MANIFEST.age
+// embedded in a personalized recover.html, or nil if it has none.
+func ExtractParityFromHTML(htmlContent []byte) ([]byte, error) {
+	matches := personalizationRe.FindSubmatch(htmlContent)
+	if len(matches) < 2 {
+		return nil, nil
+	}
+
+	var p personalizationManifest
+	if err := json.Unmarshal(matches[1], &p); err != nil {
+		return nil, fmt.Errorf("parsing PERSONALIZATION JSON: %w", err)
+	}
+	if p.ParityB64 == "" {
+		return nil, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(p.ParityB64)
+	if err != nil {
+		return nil, fmt.Errorf("decoding parity base64: %w", err)
+	}
+	return data, nil
+}
+
+// versionRe matches the version in the recover.html footer ("ReMemory v1.2.3 &mdash; ...").
+var versionRe = regexp.MustCompile(`<p>ReMemory ([^\s<]+) &mdash;`)
+
+// IsRecoverHTML reports whether the content looks like a recover.html file,
+// personalized or not.
+func IsRecoverHTML(htmlContent []byte) bool {
+	return bytes.Contains(htmlContent, []byte("window.PERSONALIZATION"))
+}
+
+// ExtractPersonalization returns the PERSONALIZATION data embedded in a
+// recover.html file, or nil if the file is a generic (non-personalized) one.
+func ExtractPersonalization(htmlContent []byte) (*PersonalizationData, error) {
+	if !IsRecoverHTML(htmlContent) {
+		return nil, fmt.Errorf("not a recover.html file")
+	}
+
+	matches := personalizationRe.FindSubmatch(htmlContent)
+	if len(matches) < 2 {
+		return nil, nil
+	}
+
+	var p PersonalizationData
+	if err := json.Unmarshal(matches[1], &p); err != nil {
+		return nil, fmt.Errorf("parsing PERSONALIZATION JSON: %w", err)
+	}
+	return &p, nil
+}
+
+// ExtractVersion returns the rememory version shown in the recover.html footer,
+// or "" if it can't be found.
+func ExtractVersion(htmlContent []byte) string {
+	matches := versionRe.FindSubmatch(htmlContent)
+	if len(matches) < 2 {
+		return ""
+	}
+	return string(matches[1])
+}
@@ -0,0 +1,188 @@
+package html
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/eljojo/rememory/internal/core"
+)
+
+// A recover.html that isn't ours could send shares anywhere once a friend
+// opens it. The CLI carries the hashes of the recover.html of every official
+// release made since releases.json was added, so a bundle's copy can be
+// checked against them. Pages from earlier releases are unknown.
+
+// RecoverHashes identify the code in a recover.html.
+type RecoverHashes struct {
+	// WASM is the SHA-256 of the embedded recover.wasm, decompressed.
+	WASM string `json:"wasm"`
+	// Page is the SHA-256 of the rest of the page, with what differs
+	// between bundles (personalization, CSP nonce, practice banner,
+	// version and release link) put back to the template's placeholders.
+	Page string `json:"page"`
+}
+
+// Release is an official release's recover.html.
+type Release struct {
+	Version string `json:"version"`
+	RecoverHashes
+}
+
+// Origin says where a recover.html comes from.
+type Origin string
+
+const (
+	OriginOfficial  Origin = "official"   // matches an official release
+	OriginThisBuild Origin = "this-build" // matches the running rememory, which isn't in the release table
+	OriginModified  Origin = "modified"   // claims to be from a known release, but doesn't match it
+	OriginUnknown   Origin = "unknown"    // matches nothing known: a newer release, a custom build, or tampered with
+)
+
+// Provenance is the result of checking a recover.html.
+type Provenance struct {
+	Origin  Origin `json:"origin"`
+	Release string `json:"release,omitempty"` // the official release it matches
+	Claimed string `json:"claimed_version,omitempty"`
+	RecoverHashes
+}
+
+// Official reports whether the recover.html is known to be genuine.
+func (p *Provenance) Official() bool {
+	return p.Origin == OriginOfficial || p.Origin == OriginThisBuild
+}
+
+// releases.json gets an entry when each release is cut: make release adds
+// the hashes of recover.html as built from the release's source, and the
+// release workflow refuses to publish a recover.html that doesn't match it.
+//
+//go:embed releases.json
+var releasesJSON []byte
+
+// Releases returns the recover.html hashes of the official releases this
+// build knows about, oldest first.
+func Releases() []Release {
+	var releases []Release
+	if err := json.Unmarshal(releasesJSON, &releases); err != nil {
+		panic("parsing releases.json: " + err.Error())
+	}
+	return releases
+}
+
+// BuildHashes returns the hashes of recover.html as this build makes it.
+func BuildHashes() RecoverHashes {
+	return RecoverHashes{
+		WASM: core.HashBytes(recoverWASM),
+		Page: core.HashString(recoverTemplate()),
+	}
+}
+
+var (
+	wasmBinaryRe     = regexp.MustCompile(`window\.WASM_BINARY = "([A-Za-z0-9+/=]*)";`)
+	personalizeRe    = regexp.MustCompile(`window\.PERSONALIZATION = (null|\{[^\n]*\});`)
+	cspNonceRe       = regexp.MustCompile(`'nonce-([A-Za-z0-9+/=_-]+)'`)
+	practiceBannerRe = regexp.MustCompile(`<body>\n  <div class="practice-banner" data-i18n="practice_banner">[^<\n]*</div>`)
+	practiceTitleRe  = regexp.MustCompile(`<title>[^<]*? · `)
+	footerVersionRe  = regexp.MustCompile(`<p>ReMemory [^\s<]+ &mdash;`)
+	releaseLinkRe    = regexp.MustCompile(`href="https://github\.com/eljojo/rememory/releases/(?:tag/[A-Za-z0-9._+-]+|latest)"`)
+)
+
+// maxWASMSize bounds the decompressed recover.wasm.
+const maxWASMSize = 64 << 20
+
+// HashRecoverHTML returns the hashes of a recover.html.
+func HashRecoverHTML(content []byte) (*RecoverHashes, error) {
+	m := wasmBinaryRe.FindSubmatchIndex(content)
+	if m == nil {
+		return nil, fmt.Errorf("no embedded recover.wasm found")
+	}
+	compressed, err := base64.StdEncoding.DecodeString(string(content[m[2]:m[3]]))
+	if err != nil {
+		return nil, fmt.Errorf("decoding embedded recover.wasm: %w", err)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing embedded recover.wasm: %w", err)
+	}
+	wasm, err := io.ReadAll(io.LimitReader(gz, maxWASMSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing embedded recover.wasm: %w", err)
+	}
+	if len(wasm) > maxWASMSize {
+		return nil, fmt.Errorf("embedded recover.wasm is too large")
+	}
+
+	page := string(content[:m[2]]) + "{{WASM_BASE64}}" + string(content[m[3]:])
+	// Only data may be taken out of the page, never code: personalization
+	// that isn't exactly what Render writes stays in, and the page won't match
+	if p := personalizeRe.FindStringSubmatchIndex(page); p != nil && canonicalPersonalization(page[p[2]:p[3]]) {
+		page = page[:p[2]] + "{{PERSONALIZATION_DATA}}" + page[p[3]:]
+	}
+	if n := cspNonceRe.FindStringSubmatch(page); n != nil {
+		page = strings.ReplaceAll(page, "'nonce-"+n[1]+"'", "'nonce-{{CSP_NONCE}}'")
+		page = strings.ReplaceAll(page, `nonce="`+n[1]+`"`, `nonce="{{CSP_NONCE}}"`)
+	}
+	if practiceBannerRe.MatchString(page) {
+		page = practiceBannerRe.ReplaceAllLiteralString(page, "<body>")
+		page = practiceTitleRe.ReplaceAllLiteralString(page, "<title>")
+	}
+	page = footerVersionRe.ReplaceAllLiteralString(page, "<p>ReMemory {{VERSION}} &mdash;")
+	page = releaseLinkRe.ReplaceAllLiteralString(page, `href="{{GITHUB_URL}}"`)
+
+	return &RecoverHashes{WASM: core.HashBytes(wasm), Page: core.HashString(page)}, nil
+}
+
+// canonicalPersonalization reports whether s is personalization exactly as
+// Render writes it. Valid JSON isn't enough: a string holding
+// "</script><script nonce=...>" would still end the script and start
+// another. json.Marshal escapes <, > and &, so re-encoding the data and
+// comparing the bytes rules that out.
+func canonicalPersonalization(s string) bool {
+	if s == "null" {
+		return true
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	var data PersonalizationData
+	if err := dec.Decode(&data); err != nil || dec.More() {
+		return false
+	}
+	encoded, err := json.Marshal(&data)
+	return err == nil && string(encoded) == s
+}
+
+// CheckRecoverHTML tells whether a recover.html comes from an official
+// release, from this build, or from somewhere else.
+func CheckRecoverHTML(content []byte) (*Provenance, error) {
+	hashes, err := HashRecoverHTML(content)
+	if err != nil {
+		return nil, err
+	}
+	p := &Provenance{Claimed: ExtractVersion(content), RecoverHashes: *hashes}
+
+	// The same code can ship in several releases; prefer the one it claims
+	claimedKnown := false
+	for _, r := range Releases() {
+		claimedKnown = claimedKnown || r.Version == p.Claimed
+		if r.RecoverHashes == *hashes && (p.Release == "" || r.Version == p.Claimed) {
+			p.Origin, p.Release = OriginOfficial, r.Version
+		}
+	}
+	switch {
+	case p.Origin != "":
+	case BuildHashes() == *hashes:
+		p.Origin = OriginThisBuild
+	case claimedKnown:
+		// It says it's from a release we know, but isn't
+		p.Origin = OriginModified
+	default:
+		p.Origin = OriginUnknown
+	}
+	return p, nil
+}
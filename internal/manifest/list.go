@@ -0,0 +1,170 @@
+package manifest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/eljojo/rememory/internal/core"
+)
+
+// Entry describes a single file or directory inside a manifest archive.
+type Entry struct {
+	// Name is the path inside the manifest, without the archive's root folder
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"dir"`
+}
+
+// Filter selects archive entries using glob patterns (see path.Match).
+// A pattern matches an entry if it matches the entry's path or base name,
+// or the path or base name of any of its parent directories — so "*.md"
+// selects every Markdown file and "photos" selects everything under photos/.
+// An empty Include list selects everything. Exclude wins over Include.
+type Filter struct {
+	Include []string
+	Exclude []string
+}
+
+// Validate checks that all patterns are well-formed.
+func (f Filter) Validate() error {
+	for _, p := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// IsEmpty returns true if the filter selects every entry.
+func (f Filter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Match reports whether the entry name (relative to the manifest root) is selected.
+func (f Filter) Match(name string) bool {
+	name = strings.TrimSuffix(name, "/")
+	if len(f.Include) > 0 && !matchAny(f.Include, name) {
+		return false
+	}
+	return !matchAny(f.Exclude, name)
+}
+
+// matchAny reports whether any pattern matches the name or one of its parent directories.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		p = strings.TrimSuffix(p, "/")
+		for dir := name; dir != "." && dir != "/" && dir != ""; dir = path.Dir(dir) {
+			if ok, _ := path.Match(p, dir); ok {
+				return true
+			}
+			if ok, _ := path.Match(p, path.Base(dir)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relativeName strips the archive's root folder (e.g. "manifest/") from an entry name.
+func relativeName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// List reads a tar.gz archive and returns its entries, without writing anything to disk.
+// Entries rejected by the filter are left out. The archive's root folder is not listed.
+func List(r io.Reader, filter Filter) ([]Entry, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("creating gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	var entries []Entry
+	seenEntry := false
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar: %w", err)
+		}
+		seenEntry = true
+
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeDir {
+			continue
+		}
+
+		name := relativeName(header.Name)
+		if name == "" || !filter.Match(name) {
+			continue
+		}
+
+		entries = append(entries, Entry{
+			Name:    name,
+			Size:    header.Size,
+			ModTime: header.ModTime,
+			IsDir:   header.Typeflag == tar.TypeDir,
+		})
+	}
+
+	if !seenEntry {
+		return nil, fmt.Errorf("empty archive")
+	}
+
+	return entries, nil
+}
+
+// ExtractFile streams a single file from a tar.gz archive to w.
+// The name is relative to the manifest root (e.g. "instructions.md").
+// Returns the number of bytes written.
+func ExtractFile(r io.Reader, name string, w io.Writer) (int64, error) {
+	want := strings.TrimPrefix(path.Clean("/"+name), "/")
+
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("creating gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reading tar: %w", err)
+		}
+
+		if relativeName(header.Name) != want {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return 0, fmt.Errorf("%s is not a regular file", name)
+		}
+		if header.Size > core.MaxFileSize {
+			return 0, fmt.Errorf("file exceeds maximum size of %d bytes", core.MaxFileSize)
+		}
+
+		written, err := io.Copy(w, io.LimitReader(tr, core.MaxFileSize))
+		if err != nil {
+			return written, fmt.Errorf("writing %s: %w", name, err)
+		}
+		return written, nil
+	}
+
+	return 0, fmt.Errorf("file %q not found in manifest", name)
+}
@@ -8,6 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestArchiveExtract(t *testing.T) {
@@ -346,3 +349,234 @@ func TestArchiveEmptyDir(t *testing.T) {
 		t.Fatalf("Extract empty archive: %v", err)
 	}
 }
+
+// archiveTestManifest archives a small manifest directory and returns the tar.gz bytes.
+func archiveTestManifest(t *testing.T) []byte {
+	t.Helper()
+	testDir := filepath.Join(t.TempDir(), "manifest")
+	files := map[string]string{
+		"README.md":          "# Test Manifest",
+		"instructions.md":    "call the lawyer",
+		"secret.txt":         "super secret data",
+		"photos/cat.jpg":     "meow",
+		"photos/notes/a.txt": "nested",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(testDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := Archive(&buf, testDir); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestList(t *testing.T) {
+	data := archiveTestManifest(t)
+
+	entries, err := List(bytes.NewReader(data), Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	got := make(map[string]Entry)
+	for _, e := range entries {
+		got[e.Name] = e
+	}
+
+	if _, ok := got[""]; ok {
+		t.Error("root folder should not be listed")
+	}
+	if e, ok := got["secret.txt"]; !ok || e.IsDir || e.Size != int64(len("super secret data")) {
+		t.Errorf("secret.txt entry = %+v, ok=%v", e, ok)
+	}
+	if e, ok := got["photos"]; !ok || !e.IsDir {
+		t.Errorf("photos entry = %+v, ok=%v", e, ok)
+	}
+	if e := got["README.md"]; e.ModTime.IsZero() {
+		t.Error("expected modification time to be set")
+	}
+	if len(entries) != 7 {
+		t.Errorf("got %d entries, want 7", len(entries))
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		entry  string
+		want   bool
+	}{
+		{"empty filter", Filter{}, "secret.txt", true},
+		{"include base name glob", Filter{Include: []string{"*.md"}}, "photos/notes/x.md", true},
+		{"include miss", Filter{Include: []string{"*.md"}}, "secret.txt", false},
+		{"include directory", Filter{Include: []string{"photos"}}, "photos/notes/a.txt", true},
+		{"include full path", Filter{Include: []string{"photos/*.jpg"}}, "photos/cat.jpg", true},
+		{"exclude directory", Filter{Exclude: []string{"photos/"}}, "photos/cat.jpg", false},
+		{"exclude wins", Filter{Include: []string{"*"}, Exclude: []string{"secret.txt"}}, "secret.txt", false},
+		{"exclude other", Filter{Exclude: []string{"secret.txt"}}, "README.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.entry); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.entry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	if err := (Filter{Include: []string{"*.md"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (Filter{Exclude: []string{"[unclosed"}}).Validate(); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
+
+func TestExtractFiltered(t *testing.T) {
+	data := archiveTestManifest(t)
+	dstDir := t.TempDir()
+
+	filter := Filter{Include: []string{"*.md", "photos"}, Exclude: []string{"notes"}}
+	result, err := ExtractFiltered(bytes.NewReader(data), dstDir, filter)
+	if err != nil {
+		t.Fatalf("ExtractFiltered: %v", err)
+	}
+	if result.Files != 3 {
+		t.Errorf("got %d files, want 3", result.Files)
+	}
+
+	for _, path := range []string{"README.md", "instructions.md", "photos/cat.jpg"} {
+		if _, err := os.Stat(filepath.Join(result.Path, path)); err != nil {
+			t.Errorf("%s should be extracted: %v", path, err)
+		}
+	}
+	for _, path := range []string{"secret.txt", "photos/notes"} {
+		if _, err := os.Stat(filepath.Join(result.Path, path)); err == nil {
+			t.Errorf("%s should not be extracted", path)
+		}
+	}
+}
+
+func TestExtractFile(t *testing.T) {
+	data := archiveTestManifest(t)
+
+	var out bytes.Buffer
+	n, err := ExtractFile(bytes.NewReader(data), "instructions.md", &out)
+	if err != nil {
+		t.Fatalf("ExtractFile: %v", err)
+	}
+	if out.String() != "call the lawyer" || n != int64(out.Len()) {
+		t.Errorf("got %q (%d bytes)", out.String(), n)
+	}
+
+	out.Reset()
+	if _, err := ExtractFile(bytes.NewReader(data), "./photos/notes/a.txt", &out); err != nil || out.String() != "nested" {
+		t.Errorf("nested file: got %q, err %v", out.String(), err)
+	}
+
+	if _, err := ExtractFile(bytes.NewReader(data), "missing.txt", &out); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := ExtractFile(bytes.NewReader(data), "photos", &out); err == nil {
+		t.Error("expected error for directory")
+	}
+}
+
+func TestHashArchiveMatchesHashDir(t *testing.T) {
+	srcDir := t.TempDir()
+	testDir := filepath.Join(srcDir, "manifest")
+	os.MkdirAll(filepath.Join(testDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(testDir, "a.txt"), []byte("alpha"), 0644)
+	os.WriteFile(filepath.Join(testDir, "sub", "b.txt"), []byte("beta"), 0644)
+
+	var buf bytes.Buffer
+	if _, err := Archive(&buf, testDir); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	fromArchive, err := HashArchive(&buf)
+	if err != nil {
+		t.Fatalf("HashArchive: %v", err)
+	}
+	fromDir, err := HashDir(testDir)
+	if err != nil {
+		t.Fatalf("HashDir: %v", err)
+	}
+
+	if len(fromDir) != 2 || fromDir[0].Path != "a.txt" || fromDir[1].Path != "sub/b.txt" {
+		t.Fatalf("unexpected dir hashes: %+v", fromDir)
+	}
+	if TreeHash(fromArchive) != TreeHash(fromDir) {
+		t.Errorf("tree hashes differ:\narchive: %+v\ndir:     %+v", fromArchive, fromDir)
+	}
+	if !Compare(fromArchive, fromDir).IsEmpty() {
+		t.Errorf("expected no changes, got %+v", Compare(fromArchive, fromDir))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := []FileHash{
+		{Path: "kept.txt", Size: 1, Checksum: "sha256:aa"},
+		{Path: "modified.txt", Size: 1, Checksum: "sha256:bb"},
+		{Path: "removed.txt", Size: 1, Checksum: "sha256:cc"},
+	}
+	current := []FileHash{
+		{Path: "added.txt", Size: 1, Checksum: "sha256:dd"},
+		{Path: "kept.txt", Size: 1, Checksum: "sha256:aa"},
+		{Path: "modified.txt", Size: 2, Checksum: "sha256:ee"},
+	}
+
+	c := Compare(base, current)
+	if strings.Join(c.Added, ",") != "added.txt" {
+		t.Errorf("Added = %v", c.Added)
+	}
+	if strings.Join(c.Removed, ",") != "removed.txt" {
+		t.Errorf("Removed = %v", c.Removed)
+	}
+	if strings.Join(c.Modified, ",") != "modified.txt" {
+		t.Errorf("Modified = %v", c.Modified)
+	}
+	if c.IsEmpty() {
+		t.Error("expected changes")
+	}
+	if TreeHash(base) == TreeHash(current) {
+		t.Error("different trees should have different hashes")
+	}
+
+	// Without checksums, as recorded in project.yml, the modification time tells
+	sealedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	base = []FileHash{
+		{Path: "kept.txt", Size: 1, ModTime: sealedAt},
+		{Path: "touched.txt", Size: 1, ModTime: sealedAt},
+		{Path: "old.txt", Size: 1}, // indexed before modification times were recorded
+	}
+	current = []FileHash{
+		{Path: "kept.txt", Size: 1, ModTime: sealedAt, Checksum: "sha256:aa"},
+		{Path: "touched.txt", Size: 1, ModTime: sealedAt.Add(time.Second), Checksum: "sha256:bb"},
+		{Path: "old.txt", Size: 1, ModTime: sealedAt, Checksum: "sha256:cc"},
+	}
+	if c := Compare(base, current); strings.Join(c.Modified, ",") != "touched.txt" || len(c.Added)+len(c.Removed) != 0 {
+		t.Errorf("by modification time: %+v", c)
+	}
+}
+
+func TestFileHashYAML(t *testing.T) {
+	data, err := yaml.Marshal(FileHash{Path: "pin.txt", Size: 4, ModTime: time.Unix(0, 0).UTC(), Checksum: "sha256:03ac67"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "03ac67") {
+		t.Errorf("the checksum must not be written to project.yml:\n%s", data)
+	}
+}
@@ -1,6 +1,7 @@
 package pdf
 
 import (
+	"bytes"
 	_ "embed"
 
 	"github.com/go-pdf/fpdf"
@@ -36,12 +37,16 @@ const (
 
 // registerUTF8Fonts adds the embedded DejaVu Sans UTF-8 fonts to the PDF instance.
 // After calling this, use fontSans and fontMono as the family name in SetFont().
+//
+// fpdf writes into the font data while subsetting it, so each PDF gets its
+// own copy. Sharing it would make every PDF after the first come out
+// different, and race when bundles are built in parallel.
 func registerUTF8Fonts(pdf *fpdf.Fpdf) {
-	pdf.AddUTF8FontFromBytes(fontSans, "", dejaVuSansRegular)
-	pdf.AddUTF8FontFromBytes(fontSans, "B", dejaVuSansBold)
-	pdf.AddUTF8FontFromBytes(fontSans, "I", dejaVuSansOblique)
-	pdf.AddUTF8FontFromBytes(fontSans, "BI", dejaVuSansBoldOblique)
+	pdf.AddUTF8FontFromBytes(fontSans, "", bytes.Clone(dejaVuSansRegular))
+	pdf.AddUTF8FontFromBytes(fontSans, "B", bytes.Clone(dejaVuSansBold))
+	pdf.AddUTF8FontFromBytes(fontSans, "I", bytes.Clone(dejaVuSansOblique))
+	pdf.AddUTF8FontFromBytes(fontSans, "BI", bytes.Clone(dejaVuSansBoldOblique))
 
-	pdf.AddUTF8FontFromBytes(fontMono, "", dejaVuSansMonoRegular)
-	pdf.AddUTF8FontFromBytes(fontMono, "B", dejaVuSansMonoBold)
+	pdf.AddUTF8FontFromBytes(fontMono, "", bytes.Clone(dejaVuSansMonoRegular))
+	pdf.AddUTF8FontFromBytes(fontMono, "B", bytes.Clone(dejaVuSansMonoBold))
 }
@@ -33,6 +33,12 @@ type ReadmeData struct {
 	RecoveryURL      string // Base URL for QR code (e.g. "https://example.com/recover.html")
 	Language         string // Bundle language (e.g. "en", "es"); defaults to "en"
 	ManifestEmbedded bool   // true when manifest is embedded in recover.html
+	Practice         bool   // true for drill bundles: every page gets a PRACTICE watermark
+	SignedBy         string // fingerprint of the owner's signing key, if the bundle is signed
+	// PaperManifest, when set, is MANIFEST.age, printed on pages of its own
+	// as QR codes and typed lines, so the paper alone is enough to recover.
+	// It must be at most core.MaxPaperManifestSize bytes.
+	PaperManifest []byte
 }
 
 // Font sizes
@@ -73,6 +79,9 @@ func (d ReadmeData) QRContent() string {
 
 // GenerateReadme creates the README.pdf content.
 func GenerateReadme(data ReadmeData) ([]byte, error) {
+	if len(data.PaperManifest) > core.MaxPaperManifestSize {
+		return nil, fmt.Errorf("MANIFEST.age is too large to print (%d bytes, at most %d)", len(data.PaperManifest), core.MaxPaperManifestSize)
+	}
 	lang := data.Language
 	if lang == "" {
 		lang = "en"
@@ -82,6 +91,13 @@ func GenerateReadme(data ReadmeData) ([]byte, error) {
 	}
 
 	p := fpdf.New("P", "mm", "A4", "")
+	// Same bytes for the same bundle: fonts in a fixed order, and dated like
+	// the seal rather than when the bundle was generated
+	p.SetCatalogSort(true)
+	if !data.Created.IsZero() {
+		p.SetCreationDate(data.Created)
+		p.SetModificationDate(data.Created)
+	}
 	p.SetMargins(20, 20, 20)
 	p.SetAutoPageBreak(true, 20)
 
@@ -111,6 +127,13 @@ func GenerateReadme(data ReadmeData) ([]byte, error) {
 		p.SetTextColor(46, 42, 38)
 	})
 
+	// Practice watermark — drawn first on every page so content stays readable on top
+	if data.Practice {
+		p.SetHeaderFunc(func() {
+			drawPracticeWatermark(p, t("practice_watermark"))
+		})
+	}
+
 	p.AddPage()
 
 	// Page dimensions (used throughout for centered elements)
@@ -138,6 +161,16 @@ func GenerateReadme(data ReadmeData) ([]byte, error) {
 	p.CellFormat(0, 8, t("for", data.Holder), "", 1, "C", false, 0, "")
 	p.Ln(12)
 
+	if data.Practice {
+		p.SetFont(fontSans, "B", headingSize)
+		p.SetTextColor(139, 94, 94)
+		p.MultiCell(0, 6, t("practice_title"), "", "C", false)
+		p.SetFont(fontSans, "", bodySize)
+		p.MultiCell(0, 5, t("practice_notice"), "", "C", false)
+		p.SetTextColor(46, 42, 38)
+		p.Ln(8)
+	}
+
 	// ── What is this? — context first ──
 	p.SetFont(fontSans, "B", bodySize)
 	p.CellFormat(0, 6, t("what_is_this"), "", 1, "L", false, 0, "")
@@ -379,6 +412,15 @@ func GenerateReadme(data ReadmeData) ([]byte, error) {
 	addMeta(p, "github-release", data.GitHubReleaseURL)
 	addMeta(p, "checksum-manifest", data.ManifestChecksum)
 	addMeta(p, "checksum-recover-html", data.RecoverChecksum)
+	if data.SignedBy != "" {
+		addMeta(p, "signed-by", data.SignedBy)
+	}
+
+	if len(data.PaperManifest) > 0 {
+		if err := renderPaperManifest(p, data.PaperManifest, t, leftMargin, contentWidth); err != nil {
+			return nil, err
+		}
+	}
 
 	// Write to buffer
 	var buf bytes.Buffer
@@ -425,6 +467,75 @@ func renderWordGridPDF(p *fpdf.Fpdf, words []string, title string, leftMargin, c
 	p.SetY(startY + float64(half)*rowHeight + 2)
 }
 
+// Paper manifest layout: each QR code with its lines beside it.
+const (
+	paperQRSizeMM     = 42.0
+	paperLineHeight   = 5.0
+	paperRowSpacingMM = 6.0
+)
+
+// renderPaperManifest prints MANIFEST.age on pages of its own: one row per
+// QR code, with the lines it holds beside it for typing.
+func renderPaperManifest(p *fpdf.Fpdf, manifest []byte, t func(string, ...any) string, leftMargin, contentWidth float64) error {
+	lines := core.PaperLines(manifest)
+	header, lines := lines[0], lines[1:]
+	codes := core.PaperQRCodes(manifest)
+
+	p.AddPage()
+	addSection(p, t("paper_manifest_title"))
+	addBody(p, t("paper_manifest_intro"))
+	p.Ln(2)
+	addBody(p, t("paper_manifest_scan"))
+	addBody(p, t("paper_manifest_type"))
+	p.Ln(4)
+	p.SetFont(fontMono, "B", monoSize)
+	p.SetFillColor(245, 245, 245)
+	p.CellFormat(0, 6, header, "", 1, "L", true, 0, "")
+	p.Ln(4)
+
+	_, pageHeight := p.GetPageSize()
+	_, _, _, bottomMargin := p.GetMargins()
+	usableBottom := pageHeight - bottomMargin
+	opts := fpdf.ImageOptions{ImageType: "PNG", ReadDpi: true}
+	textX := leftMargin + paperQRSizeMM + 6
+	for i, code := range codes {
+		if p.GetY()+paperQRSizeMM > usableBottom {
+			p.AddPage()
+		}
+		png, err := generateQRPNG(code)
+		if err != nil {
+			return fmt.Errorf("generating QR code: %w", err)
+		}
+		name := fmt.Sprintf("manifest-%d", i)
+		p.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+		y := p.GetY()
+		p.ImageOptions(name, leftMargin, y, paperQRSizeMM, paperQRSizeMM, false, opts, 0, "")
+
+		p.SetFont(fontMono, "", monoSize)
+		chunk := lines[i*core.PaperLinesPerQR : min((i+1)*core.PaperLinesPerQR, len(lines))]
+		textY := y + (paperQRSizeMM-float64(len(chunk))*paperLineHeight)/2
+		for j, line := range chunk {
+			p.SetXY(textX, textY+float64(j)*paperLineHeight)
+			p.CellFormat(contentWidth-(textX-leftMargin), paperLineHeight, line, "", 0, "L", false, 0, "")
+		}
+		p.SetY(y + paperQRSizeMM + paperRowSpacingMM)
+	}
+	return nil
+}
+
+// drawPracticeWatermark writes large, faint diagonal text across the page.
+func drawPracticeWatermark(pdf *fpdf.Fpdf, text string) {
+	pw, ph := pdf.GetPageSize()
+	pdf.SetFont(fontSans, "B", 90)
+	pdf.SetTextColor(240, 225, 225)
+	pdf.TransformBegin()
+	pdf.TransformRotate(45, pw/2, ph/2)
+	w := pdf.GetStringWidth(text)
+	pdf.Text(pw/2-w/2, ph/2+10, text)
+	pdf.TransformEnd()
+	pdf.SetTextColor(46, 42, 38)
+}
+
 func addSection(pdf *fpdf.Fpdf, title string) {
 	pdf.SetFont(fontSans, "B", headingSize)
 	pdf.SetFillColor(230, 230, 230)
@@ -56,6 +56,48 @@ func TestGenerateReadmeAnonymous(t *testing.T) {
 	}
 }
 
+func TestGenerateReadmePractice(t *testing.T) {
+	data := testReadmeData()
+	plain, err := GenerateReadme(data)
+	if err != nil {
+		t.Fatalf("GenerateReadme: %v", err)
+	}
+
+	data.Practice = true
+	practice, err := GenerateReadme(data)
+	if err != nil {
+		t.Fatalf("GenerateReadme (practice): %v", err)
+	}
+	if !bytes.HasPrefix(practice, []byte("%PDF-")) {
+		t.Error("output does not start with PDF header")
+	}
+	if len(practice) <= len(plain) {
+		t.Errorf("practice PDF (%d bytes) should be larger than the regular one (%d bytes)", len(practice), len(plain))
+	}
+}
+
+func TestGenerateReadmePaperManifest(t *testing.T) {
+	data := testReadmeData()
+	plain, err := GenerateReadme(data)
+	if err != nil {
+		t.Fatalf("GenerateReadme: %v", err)
+	}
+
+	data.PaperManifest = bytes.Repeat([]byte{0x5a, 0xa5, 0x3c}, 1000)
+	paper, err := GenerateReadme(data)
+	if err != nil {
+		t.Fatalf("GenerateReadme (paper manifest): %v", err)
+	}
+	if pages, plainPages := bytes.Count(paper, []byte("/Type /Page\n")), bytes.Count(plain, []byte("/Type /Page\n")); pages <= plainPages {
+		t.Errorf("paper manifest PDF has %d pages, want more than %d", pages, plainPages)
+	}
+
+	data.PaperManifest = make([]byte, core.MaxPaperManifestSize+1)
+	if _, err := GenerateReadme(data); err == nil {
+		t.Error("expected an error for a manifest too large to print")
+	}
+}
+
 func TestQRContent(t *testing.T) {
 	data := testReadmeData()
 
@@ -181,3 +223,25 @@ func TestQRCodeContentMatchesCompact(t *testing.T) {
 		t.Error("parsed share data mismatch")
 	}
 }
+
+func TestGenerateReadmeDeterministic(t *testing.T) {
+	data := testReadmeData()
+	data.Share.Created = data.Created
+
+	// Several in a row: fpdf must not keep state from one PDF to the next
+	var first []byte
+	for i := 0; i < 3; i++ {
+		pdfBytes, err := GenerateReadme(data)
+		if err != nil {
+			t.Fatalf("GenerateReadme: %v", err)
+		}
+		if i == 0 {
+			first = pdfBytes
+		} else if !bytes.Equal(pdfBytes, first) {
+			t.Fatalf("PDF %d differs from the first", i+1)
+		}
+	}
+	if !bytes.Contains(first, []byte("/CreationDate (D:20260101")) {
+		t.Error("PDF should be dated like the seal")
+	}
+}
@@ -0,0 +1,321 @@
+package project
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImportedFriend is a friend read from a contacts file, along with where it came from.
+type ImportedFriend struct {
+	Friend
+	// Row is the 1-based record number in the file (CSV row, vCard entry or JSON item)
+	Row int
+}
+
+// Import holds friends and optional project settings read from a file.
+// Settings are only available from JSON files; zero values mean "not set".
+type Import struct {
+	Name      string
+	Threshold int
+	Language  string
+	Friends   []ImportedFriend
+}
+
+// ImportFile reads friends from a CSV, vCard (.vcf), JSON or YAML file,
+// choosing the format from the file extension.
+func ImportFile(path string) (*Import, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening friends file: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ImportCSV(f)
+	case ".vcf", ".vcard":
+		return ImportVCard(f)
+	case ".json":
+		return ImportJSON(f)
+	case ".yml", ".yaml":
+		return ImportYAML(f)
+	default:
+		return nil, fmt.Errorf("unsupported friends file %q (use .csv, .vcf, .json or .yml)", filepath.Base(path))
+	}
+}
+
+// csvColumns maps lowercase CSV header names to friend fields.
+var csvColumns = map[string]string{
+	"name":             "name",
+	"full name":        "name",
+	"display name":     "name",
+	"contact":          "contact",
+	"email":            "contact",
+	"e-mail":           "contact",
+	"e-mail 1 - value": "contact",
+	"phone":            "contact",
+	"telephone":        "contact",
+	"mobile":           "contact",
+	"phone 1 - value":  "contact",
+	"address":          "contact",
+	"language":         "language",
+	"lang":             "language",
+}
+
+// ImportCSV reads friends from CSV. If the first row is a header with
+// recognizable column names (name, email, phone, contact, language...) those
+// columns are used, and several contact columns are joined with ", ".
+// Without a header, columns are read as name, contact, language.
+func ImportCSV(r io.Reader) (*Import, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+
+	// Detect a header row
+	fields := []string{"name", "contact", "language"}
+	start := 0
+	var header []string
+	for _, col := range records[0] {
+		header = append(header, csvColumns[strings.ToLower(strings.TrimSpace(col))])
+	}
+	for _, field := range header {
+		if field == "name" {
+			fields = header
+			start = 1
+			break
+		}
+	}
+
+	imp := &Import{}
+	for i, record := range records[start:] {
+		var f Friend
+		var contacts []string
+		for col, value := range record {
+			value = strings.TrimSpace(value)
+			if col >= len(fields) || value == "" {
+				continue
+			}
+			switch fields[col] {
+			case "name":
+				f.Name = value
+			case "contact":
+				contacts = append(contacts, value)
+			case "language":
+				f.Language = value
+			}
+		}
+		if f.Name == "" && len(contacts) == 0 {
+			continue // blank line
+		}
+		f.Contact = strings.Join(contacts, ", ")
+		imp.Friends = append(imp.Friends, ImportedFriend{Friend: f, Row: start + i + 1})
+	}
+
+	return imp, nil
+}
+
+// ImportVCard reads friends from a vCard file (versions 2.1, 3.0 and 4.0).
+// FN (or N) becomes the name; EMAIL, TEL and ADR are joined into the contact;
+// LANG sets the language.
+func ImportVCard(r io.Reader) (*Import, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading vCard: %w", err)
+	}
+
+	// Unfold continuation lines (RFC 6350 §3.2)
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\n ", "")
+	text = strings.ReplaceAll(text, "\n\t", "")
+
+	imp := &Import{}
+	var card *Friend
+	var structuredName string
+	var contacts []string
+	count := 0
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			continue
+		}
+		prop := strings.ToUpper(line[:colon])
+		value := unescapeVCard(line[colon+1:])
+		if semi := strings.Index(prop, ";"); semi >= 0 {
+			prop = prop[:semi]
+		}
+		// Drop group prefixes like "item1.EMAIL"
+		if dot := strings.LastIndex(prop, "."); dot >= 0 {
+			prop = prop[dot+1:]
+		}
+
+		switch prop {
+		case "BEGIN":
+			if strings.EqualFold(value, "VCARD") {
+				card = &Friend{}
+				structuredName = ""
+				contacts = nil
+				count++
+			}
+		case "END":
+			if card != nil && strings.EqualFold(value, "VCARD") {
+				if card.Name == "" {
+					card.Name = structuredName
+				}
+				card.Contact = strings.Join(contacts, ", ")
+				imp.Friends = append(imp.Friends, ImportedFriend{Friend: *card, Row: count})
+				card = nil
+			}
+		}
+		if card == nil {
+			continue
+		}
+
+		switch prop {
+		case "FN":
+			card.Name = strings.TrimSpace(value)
+		case "N":
+			// N:Family;Given;Additional;Prefix;Suffix
+			parts := strings.Split(line[colon+1:], ";")
+			var names []string
+			for _, idx := range []int{3, 1, 2, 0, 4} {
+				if idx < len(parts) && strings.TrimSpace(parts[idx]) != "" {
+					names = append(names, unescapeVCard(strings.TrimSpace(parts[idx])))
+				}
+			}
+			structuredName = strings.Join(names, " ")
+		case "EMAIL", "TEL":
+			if v := strings.TrimSpace(strings.TrimPrefix(value, "tel:")); v != "" {
+				contacts = append(contacts, v)
+			}
+		case "ADR":
+			var parts []string
+			for _, p := range strings.Split(line[colon+1:], ";") {
+				if p = strings.TrimSpace(unescapeVCard(p)); p != "" {
+					parts = append(parts, p)
+				}
+			}
+			if len(parts) > 0 {
+				contacts = append(contacts, strings.Join(parts, ", "))
+			}
+		case "LANG":
+			if card.Language == "" {
+				card.Language = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	if count == 0 {
+		return nil, fmt.Errorf("no vCards found")
+	}
+	return imp, nil
+}
+
+// unescapeVCard reverses vCard text escaping.
+func unescapeVCard(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// jsonFriend accepts the project.yml field names plus common contact fields.
+type jsonFriend struct {
+	Name     string `json:"name" yaml:"name"`
+	Contact  string `json:"contact" yaml:"contact"`
+	Email    string `json:"email" yaml:"email"`
+	Phone    string `json:"phone" yaml:"phone"`
+	Language string `json:"language" yaml:"language"`
+}
+
+// importDoc is the shape of JSON and YAML friends files.
+type importDoc struct {
+	Name      string       `json:"name" yaml:"name"`
+	Threshold int          `json:"threshold" yaml:"threshold"`
+	Language  string       `json:"language" yaml:"language"`
+	Friends   []jsonFriend `json:"friends" yaml:"friends"`
+}
+
+// ImportJSON reads friends from JSON: either a list of friends, or an object
+// with a "friends" list and optional "name", "threshold" and "language" settings.
+// Each friend has "name", "contact", "language", and optionally "email" and "phone".
+func ImportJSON(r io.Reader) (*Import, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading JSON: %w", err)
+	}
+
+	var doc importDoc
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &doc.Friends)
+	} else {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	return doc.toImport(), nil
+}
+
+// ImportYAML reads friends from YAML, in the same shapes as ImportJSON.
+// A project.yml works too: its name, threshold, language and friends are read.
+func ImportYAML(r io.Reader) (*Import, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading YAML: %w", err)
+	}
+
+	var doc importDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		// Not an object; try a plain list of friends
+		if yaml.Unmarshal(data, &doc.Friends) != nil {
+			return nil, fmt.Errorf("parsing YAML: %w", err)
+		}
+	}
+	return doc.toImport(), nil
+}
+
+// toImport converts a parsed JSON or YAML file into an Import.
+func (doc *importDoc) toImport() *Import {
+	imp := &Import{Name: doc.Name, Threshold: doc.Threshold, Language: doc.Language}
+	for i, jf := range doc.Friends {
+		var contacts []string
+		for _, c := range []string{jf.Contact, jf.Email, jf.Phone} {
+			if c = strings.TrimSpace(c); c != "" {
+				contacts = append(contacts, c)
+			}
+		}
+		imp.Friends = append(imp.Friends, ImportedFriend{
+			Friend: Friend{
+				Name:     strings.TrimSpace(jf.Name),
+				Contact:  strings.Join(contacts, ", "),
+				Language: strings.TrimSpace(jf.Language),
+			},
+			Row: i + 1,
+		})
+	}
+	return imp
+}
+
+// FriendList returns the imported friends without their row numbers.
+func (imp *Import) FriendList() []Friend {
+	friends := make([]Friend, len(imp.Friends))
+	for i, f := range imp.Friends {
+		friends[i] = f.Friend
+	}
+	return friends
+}
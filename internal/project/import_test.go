@@ -0,0 +1,173 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []ImportedFriend
+	}{
+		{
+			name:  "no header",
+			input: "Alice,alice@example.com,es\nBob\n",
+			want: []ImportedFriend{
+				{Friend: Friend{Name: "Alice", Contact: "alice@example.com", Language: "es"}, Row: 1},
+				{Friend: Friend{Name: "Bob"}, Row: 2},
+			},
+		},
+		{
+			name:  "header with several contact columns",
+			input: "Email,Name,Phone,Notes\nalice@example.com,Alice,555-0100,ignored\n,,,\n\"bob@example.com\",\"Bob, Jr.\",,\n",
+			want: []ImportedFriend{
+				{Friend: Friend{Name: "Alice", Contact: "alice@example.com, 555-0100"}, Row: 2},
+				{Friend: Friend{Name: "Bob, Jr.", Contact: "bob@example.com"}, Row: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp, err := ImportCSV(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ImportCSV: %v", err)
+			}
+			if !reflect.DeepEqual(imp.Friends, tt.want) {
+				t.Errorf("friends:\n got %+v\nwant %+v", imp.Friends, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportVCard(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:Alice Smith",
+		"EMAIL;TYPE=home:alice@exa",
+		" mple.com",
+		"item1.TEL:+1 555 0100",
+		"LANG:es",
+		"END:VCARD",
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"N:Jones;Bob;;Dr.;",
+		"ADR;TYPE=home:;;1 Main St\\, Apt 2;Springfield;;;",
+		"END:VCARD",
+		"",
+	}, "\r\n")
+
+	imp, err := ImportVCard(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ImportVCard: %v", err)
+	}
+
+	want := []ImportedFriend{
+		{Friend: Friend{Name: "Alice Smith", Contact: "alice@example.com, +1 555 0100", Language: "es"}, Row: 1},
+		{Friend: Friend{Name: "Dr. Bob Jones", Contact: "1 Main St, Apt 2, Springfield"}, Row: 2},
+	}
+	if !reflect.DeepEqual(imp.Friends, want) {
+		t.Errorf("friends:\n got %+v\nwant %+v", imp.Friends, want)
+	}
+
+	if _, err := ImportVCard(strings.NewReader("not a vcard")); err == nil {
+		t.Error("expected error for file without vCards")
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	t.Run("array", func(t *testing.T) {
+		imp, err := ImportJSON(strings.NewReader(`[{"name": "Alice", "email": "alice@example.com", "phone": "555-0100"}, {"name": " Bob "}]`))
+		if err != nil {
+			t.Fatalf("ImportJSON: %v", err)
+		}
+		want := []Friend{
+			{Name: "Alice", Contact: "alice@example.com, 555-0100"},
+			{Name: "Bob"},
+		}
+		if !reflect.DeepEqual(imp.FriendList(), want) {
+			t.Errorf("friends: got %+v, want %+v", imp.FriendList(), want)
+		}
+	})
+
+	t.Run("object with settings", func(t *testing.T) {
+		imp, err := ImportJSON(strings.NewReader(`{"name": "family", "threshold": 2, "language": "de", "friends": [{"name": "Alice", "contact": "x", "language": "fr"}]}`))
+		if err != nil {
+			t.Fatalf("ImportJSON: %v", err)
+		}
+		if imp.Name != "family" || imp.Threshold != 2 || imp.Language != "de" {
+			t.Errorf("settings: got %q %d %q", imp.Name, imp.Threshold, imp.Language)
+		}
+		if len(imp.Friends) != 1 || imp.Friends[0].Language != "fr" || imp.Friends[0].Row != 1 {
+			t.Errorf("friends: got %+v", imp.Friends)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := ImportJSON(strings.NewReader(`{"friends": "nope"}`)); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestImportYAML(t *testing.T) {
+	t.Run("project.yml", func(t *testing.T) {
+		imp, err := ImportYAML(strings.NewReader("name: family\nthreshold: 2\nfriends:\n  - name: Alice\n    contact: alice@example.com\n  - name: Bob\n    language: es\n"))
+		if err != nil {
+			t.Fatalf("ImportYAML: %v", err)
+		}
+		want := []Friend{
+			{Name: "Alice", Contact: "alice@example.com"},
+			{Name: "Bob", Language: "es"},
+		}
+		if imp.Name != "family" || imp.Threshold != 2 || !reflect.DeepEqual(imp.FriendList(), want) {
+			t.Errorf("got %+v", imp)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		imp, err := ImportYAML(strings.NewReader("- name: Alice\n  email: alice@example.com\n- name: Bob\n"))
+		if err != nil {
+			t.Fatalf("ImportYAML: %v", err)
+		}
+		if len(imp.Friends) != 2 || imp.Friends[0].Contact != "alice@example.com" || imp.Friends[1].Row != 2 {
+			t.Errorf("friends: got %+v", imp.Friends)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := ImportYAML(strings.NewReader("friends: nope")); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestImportFileExtension(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "people.CSV")
+	if err := os.WriteFile(path, []byte("Alice\nBob\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	imp, err := ImportFile(path)
+	if err != nil {
+		t.Fatalf("ImportFile: %v", err)
+	}
+	if len(imp.Friends) != 2 {
+		t.Errorf("got %d friends, want 2", len(imp.Friends))
+	}
+
+	other := filepath.Join(dir, "people.txt")
+	if err := os.WriteFile(other, []byte("Alice\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportFile(other); err == nil {
+		t.Error("expected error for unsupported extension")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/manifest"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,24 +20,124 @@ const (
 
 // Friend represents a person who will hold a share.
 type Friend struct {
-	Name     string `yaml:"name"`
-	Contact  string `yaml:"contact,omitempty"`
-	Language string `yaml:"language,omitempty"` // Bundle language override (e.g. "en", "es", "de", "fr", "sl", "pt", "zh-TW")
+	Name     string `yaml:"name" json:"name"`
+	Contact  string `yaml:"contact,omitempty" json:"contact,omitempty"`
+	Language string `yaml:"language,omitempty" json:"language,omitempty"` // Bundle language override (e.g. "en", "es", "de", "fr", "sl", "pt", "zh-TW")
 }
 
 // ShareInfo stores information about a generated share.
 type ShareInfo struct {
-	Friend   string `yaml:"friend"`
-	File     string `yaml:"file"`
-	Checksum string `yaml:"checksum"`
+	Friend   string `yaml:"friend" json:"friend"`
+	File     string `yaml:"file,omitempty" json:"file,omitempty"` // empty for ephemeral seals
+	Checksum string `yaml:"checksum" json:"checksum"`             // checksum of the share file, or of the encoded share if it was never written
+	// ShareChecksum is the checksum printed inside the share itself, so a
+	// share can be recognized in any form (README.txt, compact, QR code).
+	ShareChecksum string `yaml:"share_checksum,omitempty" json:"share_checksum,omitempty"`
+	// ChallengeKey is derived from the share data and checks a holder's
+	// answers to proof-of-possession challenges.
+	ChallengeKey string `yaml:"challenge_key,omitempty" json:"-"`
 }
 
-// SealedInfo stores information about the sealed manifest.
+// Sealed stores information about one seal of the manifest.
 type Sealed struct {
 	At               time.Time   `yaml:"at"`
 	ManifestChecksum string      `yaml:"manifest_checksum"`
 	VerificationHash string      `yaml:"verification_hash"`
+	Threshold        int         `yaml:"threshold,omitempty"`
 	Shares           []ShareInfo `yaml:"shares"`
+	Version          string      `yaml:"version,omitempty"`      // ReMemory version that sealed
+	RecoveryURL      string      `yaml:"recovery_url,omitempty"` // base URL in the bundles' QR codes
+	// Ephemeral seals kept no share files on disk; the shares only exist
+	// inside the friends' bundles.
+	Ephemeral bool `yaml:"ephemeral,omitempty"`
+	// Current marks the seal whose bundles are in use. Older seals are
+	// kept so their bundles can still be recognized.
+	Current bool `yaml:"current,omitempty"`
+	// Files indexes the manifest as it was sealed, for drift detection.
+	// Empty for projects sealed before this was recorded.
+	Files []manifest.FileHash `yaml:"files,omitempty"`
+	// Deliveries tracks how each friend's bundle from this seal was handed over.
+	Deliveries []Delivery `yaml:"deliveries,omitempty"`
+	// Reshare is set when this seal was made by 'rememory reshare' from an
+	// earlier seal's shares, and records who took part.
+	Reshare *core.ReshareTranscript `yaml:"reshare,omitempty"`
+	// SignedBy is the fingerprint of the key the bundles were signed with,
+	// if they were.
+	SignedBy string `yaml:"signed_by,omitempty"`
+}
+
+// Delivery records how a friend received their bundle, and whether they
+// confirmed it.
+type Delivery struct {
+	Friend      string    `yaml:"friend" json:"friend"`
+	DeliveredAt time.Time `yaml:"delivered_at" json:"delivered_at"`
+	Method      string    `yaml:"method" json:"method"` // how it was handed over, e.g. "usb", "email", "in-person"
+	Format      string    `yaml:"format" json:"format"` // "zip", "pdf" or "paper"
+	// AckedAt is when the friend confirmed they have the bundle.
+	AckedAt *time.Time `yaml:"acked_at,omitempty" json:"acked_at,omitempty"`
+	// ShareWipedAt is when the owner's copy of the share file was wiped.
+	ShareWipedAt *time.Time `yaml:"share_wiped_at,omitempty" json:"share_wiped_at,omitempty"`
+}
+
+// Delivery returns the delivery record for a friend, or nil if their
+// bundle hasn't been handed over yet.
+func (s *Sealed) Delivery(friend string) *Delivery {
+	for i := range s.Deliveries {
+		if s.Deliveries[i].Friend == friend {
+			return &s.Deliveries[i]
+		}
+	}
+	return nil
+}
+
+// Holders returns the names of the friends who received a share in this seal.
+func (s *Sealed) Holders() []string {
+	names := make([]string, len(s.Shares))
+	for i, si := range s.Shares {
+		names[i] = si.Friend
+	}
+	return names
+}
+
+// Drill records a recovery rehearsal done with practice bundles.
+type Drill struct {
+	At time.Time `yaml:"at" json:"at"`
+	// CodeHash is the hash of the code word hidden in the practice manifest.
+	// Holders who recover it prove the drill worked.
+	CodeHash string `yaml:"code_hash" json:"-"`
+	// CodeSalt is the drill's own random salt for CodeHash, in hex. Empty
+	// for drills recorded before codes were salted.
+	CodeSalt    string     `yaml:"code_salt,omitempty" json:"-"`
+	CompletedAt *time.Time `yaml:"completed_at,omitempty" json:"completed_at,omitempty"`
+	// Participants are the holders who took part in a successful recovery.
+	Participants []string `yaml:"participants,omitempty" json:"participants"`
+}
+
+// ChallengeRound is one proof-of-possession check of the share holders.
+type ChallengeRound struct {
+	At      time.Time        `yaml:"at" json:"at"`
+	Seal    int              `yaml:"seal" json:"seal"` // number of the seal whose shares were checked
+	Holders []ChallengeEntry `yaml:"holders" json:"holders"`
+}
+
+// ChallengeEntry is the challenge sent to one friend, and its outcome.
+type ChallengeEntry struct {
+	Friend    string     `yaml:"friend" json:"friend"`
+	Challenge string     `yaml:"challenge" json:"challenge"`
+	ProvedAt  *time.Time `yaml:"proved_at,omitempty" json:"proved_at,omitempty"`
+	// OutdatedSeal is set when the friend answered with a share from an
+	// earlier seal, meaning they kept an old bundle.
+	OutdatedSeal int `yaml:"outdated_seal,omitempty" json:"outdated_seal,omitempty"`
+}
+
+// Entry returns the challenge sent to a friend in this round, or nil.
+func (r *ChallengeRound) Entry(friend string) *ChallengeEntry {
+	for i := range r.Holders {
+		if r.Holders[i].Friend == friend {
+			return &r.Holders[i]
+		}
+	}
+	return nil
 }
 
 // Project represents a rememory project configuration.
@@ -46,7 +148,14 @@ type Project struct {
 	Anonymous bool     `yaml:"anonymous,omitempty"`
 	Language  string   `yaml:"language,omitempty"` // Default bundle language (e.g. "en", "es", "de", "fr", "sl", "pt", "zh-TW")
 	Friends   []Friend `yaml:"friends"`
-	Sealed    *Sealed  `yaml:"sealed,omitempty"`
+	Seals     []Sealed `yaml:"seals,omitempty"` // every seal, oldest first
+	Drills    []Drill  `yaml:"drills,omitempty"`
+
+	Challenges []ChallengeRound `yaml:"challenges,omitempty"`
+
+	// LegacySealed is the single seal record written by older versions.
+	// Load moves it into Seals.
+	LegacySealed *Sealed `yaml:"sealed,omitempty"`
 
 	// Path is the directory containing this project (not serialized)
 	Path string `yaml:"-"`
@@ -60,20 +169,43 @@ func Load(dir string) (*Project, error) {
 		return nil, fmt.Errorf("reading project file: %w", err)
 	}
 
+	p, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	p.Path = dir
+	return p, nil
+}
+
+// Parse reads a project from the contents of a project.yml. Its Path is
+// left empty.
+func Parse(data []byte) (*Project, error) {
 	var p Project
 	if err := yaml.Unmarshal(data, &p); err != nil {
 		return nil, fmt.Errorf("parsing project file: %w", err)
 	}
 
-	p.Path = dir
+	if p.LegacySealed != nil {
+		p.AddSeal(*p.LegacySealed)
+		p.LegacySealed = nil
+	}
 	return &p, nil
 }
 
+// Encode returns the project as it's written to project.yml.
+func (p *Project) Encode() ([]byte, error) {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("encoding project: %w", err)
+	}
+	return data, nil
+}
+
 // Save writes the project configuration to disk.
 func (p *Project) Save() error {
-	data, err := yaml.Marshal(p)
+	data, err := p.Encode()
 	if err != nil {
-		return fmt.Errorf("encoding project: %w", err)
+		return err
 	}
 
 	path := filepath.Join(p.Path, ProjectFileName)
@@ -108,6 +240,104 @@ func (p *Project) Validate() error {
 	return nil
 }
 
+// CurrentSeal returns the seal in use, or nil if the project hasn't been sealed.
+func (p *Project) CurrentSeal() *Sealed {
+	if i := p.CurrentSealIndex(); i >= 0 {
+		return &p.Seals[i]
+	}
+	return nil
+}
+
+// CurrentSealIndex returns the index in Seals of the seal in use, or -1.
+// Seals are numbered from 1 for people, so seal #n is Seals[n-1].
+func (p *Project) CurrentSealIndex() int {
+	for i := len(p.Seals) - 1; i >= 0; i-- {
+		if p.Seals[i].Current {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddSeal records a new seal and makes it the current one.
+func (p *Project) AddSeal(s Sealed) {
+	for i := range p.Seals {
+		p.Seals[i].Current = false
+	}
+	s.Current = true
+	p.Seals = append(p.Seals, s)
+}
+
+// FindSeal returns the index in Seals of the newest seal that produced a
+// file or share with one of the given checksums, or -1 if none did. Manifest
+// checksums, share file checksums and share data checksums all match.
+func (p *Project) FindSeal(checksums ...string) int {
+	for i := len(p.Seals) - 1; i >= 0; i-- {
+		s := &p.Seals[i]
+		for _, c := range checksums {
+			if c == "" {
+				continue
+			}
+			if c == s.ManifestChecksum {
+				return i
+			}
+			for _, si := range s.Shares {
+				if c == si.Checksum || c == si.ShareChecksum {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// SupersededAt returns when the seal at index i was replaced by a later
+// one, or nil if it is the latest.
+func (p *Project) SupersededAt(i int) *time.Time {
+	if i < 0 || i+1 >= len(p.Seals) {
+		return nil
+	}
+	return &p.Seals[i+1].At
+}
+
+// LastDrill returns the most recent drill, or nil if there hasn't been one.
+func (p *Project) LastDrill() *Drill {
+	if len(p.Drills) == 0 {
+		return nil
+	}
+	return &p.Drills[len(p.Drills)-1]
+}
+
+// LastChallenge returns the most recent challenge round, or nil if there hasn't been one.
+func (p *Project) LastChallenge() *ChallengeRound {
+	if len(p.Challenges) == 0 {
+		return nil
+	}
+	return &p.Challenges[len(p.Challenges)-1]
+}
+
+// LastChallengeOf returns the most recent challenge sent to a friend for the
+// given seal number, and the round it was part of. A round that only covers
+// some friends leaves the others' earlier challenges standing. Both are nil
+// if the friend hasn't been challenged for that seal.
+func (p *Project) LastChallengeOf(friend string, seal int) (*ChallengeRound, *ChallengeEntry) {
+	for i := len(p.Challenges) - 1; i >= 0; i-- {
+		r := &p.Challenges[i]
+		if r.Seal != seal {
+			continue
+		}
+		if e := r.Entry(friend); e != nil {
+			return r, e
+		}
+	}
+	return nil, nil
+}
+
+// DrillPath returns the path to the practice bundles directory.
+func (p *Project) DrillPath() string {
+	return filepath.Join(p.OutputPath(), "drill")
+}
+
 // ManifestPath returns the path to the manifest directory.
 func (p *Project) ManifestPath() string {
 	return filepath.Join(p.Path, ManifestDir)
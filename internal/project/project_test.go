@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/eljojo/rememory/internal/manifest"
 )
 
 func TestNewAndLoad(t *testing.T) {
@@ -356,9 +359,17 @@ func TestSaveAndReload(t *testing.T) {
 	}
 
 	// Modify and save
-	p.Sealed = &Sealed{
+	p.AddSeal(Sealed{
 		ManifestChecksum: "sha256:abc",
 		VerificationHash: "sha256:def",
+		Files: []manifest.FileHash{
+			{Path: "notes/secret.txt", Size: 42, ModTime: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Checksum: "sha256:123"},
+		},
+	})
+	completed := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	p.Drills = []Drill{
+		{At: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), CodeHash: "sha256:aaa"},
+		{At: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), CodeHash: "sha256:bbb", CompletedAt: &completed, Participants: []string{"Alice"}},
 	}
 	if err := p.Save(); err != nil {
 		t.Fatalf("Save: %v", err)
@@ -370,11 +381,106 @@ func TestSaveAndReload(t *testing.T) {
 		t.Fatalf("Load: %v", err)
 	}
 
-	if loaded.Sealed == nil {
-		t.Fatal("Sealed should not be nil")
+	sealed := loaded.CurrentSeal()
+	if sealed == nil {
+		t.Fatal("CurrentSeal should not be nil")
 	}
-	if loaded.Sealed.ManifestChecksum != "sha256:abc" {
-		t.Errorf("ManifestChecksum: got %q", loaded.Sealed.ManifestChecksum)
+	if sealed.ManifestChecksum != "sha256:abc" {
+		t.Errorf("ManifestChecksum: got %q", sealed.ManifestChecksum)
+	}
+	want := p.Seals[0].Files[0]
+	want.Checksum = "" // kept out of project.yml
+	if len(sealed.Files) != 1 || sealed.Files[0].Path != want.Path || sealed.Files[0].Size != want.Size || !sealed.Files[0].ModTime.Equal(want.ModTime) || sealed.Files[0].Checksum != "" {
+		t.Errorf("Files: got %+v", sealed.Files)
+	}
+
+	last := loaded.LastDrill()
+	if len(loaded.Drills) != 2 || last == nil {
+		t.Fatalf("Drills: got %+v", loaded.Drills)
+	}
+	if last.CodeHash != "sha256:bbb" || last.CompletedAt == nil || !last.CompletedAt.Equal(completed) || len(last.Participants) != 1 {
+		t.Errorf("LastDrill: got %+v", last)
+	}
+}
+
+func TestSealHistory(t *testing.T) {
+	projectDir := t.TempDir()
+
+	// A project file from before seal history was kept
+	legacy := `name: test
+created: "2024-01-01"
+threshold: 2
+friends:
+  - name: Alice
+  - name: Bob
+sealed:
+  at: 2024-01-01T10:00:00Z
+  manifest_checksum: sha256:m1
+  verification_hash: sha256:v1
+  shares:
+    - friend: Alice
+      file: output/shares/SHARE-alice.txt
+      checksum: sha256:a1
+`
+	if err := os.WriteFile(filepath.Join(projectDir, ProjectFileName), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(p.Seals) != 1 || p.CurrentSeal() == nil || p.CurrentSeal().ManifestChecksum != "sha256:m1" {
+		t.Fatalf("legacy seal not migrated: %+v", p.Seals)
+	}
+
+	second := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	p.AddSeal(Sealed{
+		At:               second,
+		ManifestChecksum: "sha256:m2",
+		Shares:           []ShareInfo{{Friend: "Alice", Checksum: "sha256:a2", ShareChecksum: "sha256:s2"}},
+	})
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(projectDir, ProjectFileName))
+	if contains(string(data), "\nsealed:") {
+		t.Error("legacy sealed: key should not be written back")
+	}
+
+	loaded, err := Load(projectDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Seals) != 2 || loaded.Seals[0].Current || !loaded.Seals[1].Current {
+		t.Fatalf("Seals: got %+v", loaded.Seals)
+	}
+	if loaded.CurrentSealIndex() != 1 {
+		t.Errorf("CurrentSealIndex: got %d", loaded.CurrentSealIndex())
+	}
+
+	tests := []struct {
+		checksum string
+		want     int
+	}{
+		{"sha256:m1", 0},
+		{"sha256:a1", 0},
+		{"sha256:m2", 1},
+		{"sha256:s2", 1},
+		{"sha256:other", -1},
+	}
+	for _, tt := range tests {
+		if got := loaded.FindSeal(tt.checksum); got != tt.want {
+			t.Errorf("FindSeal(%q) = %d, want %d", tt.checksum, got, tt.want)
+		}
+	}
+
+	if at := loaded.SupersededAt(0); at == nil || !at.Equal(second) {
+		t.Errorf("SupersededAt(0): got %v", at)
+	}
+	if at := loaded.SupersededAt(1); at != nil {
+		t.Errorf("SupersededAt(1): got %v, want nil", at)
 	}
 }
 
@@ -390,3 +496,37 @@ func containsAt(s, substr string, start int) bool {
 	}
 	return false
 }
+
+func TestLastChallengeOf(t *testing.T) {
+	p := &Project{Challenges: []ChallengeRound{
+		{Seal: 1, Holders: []ChallengeEntry{{Friend: "Alice", Challenge: "seal one"}}},
+		{Seal: 2, Holders: []ChallengeEntry{{Friend: "Alice", Challenge: "alice words"}, {Friend: "Bob", Challenge: "bob words"}}},
+		// A later round for Bob alone
+		{Seal: 2, Holders: []ChallengeEntry{{Friend: "Bob", Challenge: "new bob words"}}},
+	}}
+
+	tests := []struct {
+		friend string
+		seal   int
+		want   string
+	}{
+		{"Alice", 2, "alice words"},
+		{"Bob", 2, "new bob words"},
+		{"Alice", 1, "seal one"},
+		{"Bob", 1, ""},
+		{"Camila", 2, ""},
+	}
+	for _, tt := range tests {
+		round, entry := p.LastChallengeOf(tt.friend, tt.seal)
+		got := ""
+		if entry != nil {
+			got = entry.Challenge
+			if round.Entry(tt.friend) != entry {
+				t.Errorf("%s, seal %d: entry isn't from the round returned", tt.friend, tt.seal)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s, seal %d: got %q, want %q", tt.friend, tt.seal, got, tt.want)
+		}
+	}
+}
@@ -3,23 +3,15 @@
 package main
 
 import (
-	"archive/tar"
-	"archive/zip"
 	"bytes"
-	"compress/gzip"
-	"encoding/base64"
 	"fmt"
-	"strings"
 	"syscall/js"
 	"time"
 
 	"github.com/eljojo/rememory/internal/bundle"
-	"github.com/eljojo/rememory/internal/core"
-	"github.com/eljojo/rememory/internal/crypto"
 	"github.com/eljojo/rememory/internal/html"
-	"github.com/eljojo/rememory/internal/pdf"
+	"github.com/eljojo/rememory/internal/manifest"
 	"github.com/eljojo/rememory/internal/project"
-	"github.com/eljojo/rememory/internal/translations"
 
 	"gopkg.in/yaml.v3"
 )
@@ -47,6 +39,12 @@ type CreateBundlesConfig struct {
 	GitHubURL       string
 	Anonymous       bool
 	DefaultLanguage string // Default bundle language for all friends
+	RecoveryURL     string // Base URL for the QR code in README.pdf (default: GitHub Pages)
+	NoEmbedManifest bool   // Keep MANIFEST.age out of recover.html even when small enough
+	// NoProjectManifest keeps MANIFEST.age out of the project archive; the
+	// CLI can then take it from any bundle when the project is imported.
+	NoProjectManifest bool
+	Progress          bundle.Progress
 }
 
 // BundleOutput represents a generated bundle for JavaScript.
@@ -57,8 +55,10 @@ type BundleOutput struct {
 }
 
 // createBundlesJS is the WASM entry point for bundle creation.
-// Args: config object with projectName, threshold, friends, files, version, githubURL
-// Returns: { bundles: [...], error: string|null }
+// Args: config object with projectName, threshold, friends, files, version, githubURL,
+// and optionally recoveryURL, noEmbedManifest, noProjectManifest and onProgress(event)
+// Returns: { bundles: [...], project: {fileName, data}, error: string|null }
+// project is a project archive for 'rememory import-project'.
 func createBundlesJS(this js.Value, args []js.Value) any {
 	if len(args) < 1 {
 		return errorResult("missing config argument")
@@ -77,6 +77,25 @@ func createBundlesJS(this js.Value, args []js.Value) any {
 	if defLang := configJS.Get("defaultLanguage"); !defLang.IsUndefined() && !defLang.IsNull() {
 		config.DefaultLanguage = defLang.String()
 	}
+	if url := configJS.Get("recoveryURL"); !url.IsUndefined() && !url.IsNull() {
+		config.RecoveryURL = url.String()
+	}
+	if noEmbed := configJS.Get("noEmbedManifest"); !noEmbed.IsUndefined() && !noEmbed.IsNull() {
+		config.NoEmbedManifest = noEmbed.Bool()
+	}
+	if noProject := configJS.Get("noProjectManifest"); !noProject.IsUndefined() && !noProject.IsNull() {
+		config.NoProjectManifest = noProject.Bool()
+	}
+	if onProgress := configJS.Get("onProgress"); onProgress.Type() == js.TypeFunction {
+		config.Progress = func(e bundle.Event) {
+			onProgress.Invoke(map[string]any{
+				"stage":  string(e.Stage),
+				"friend": e.Friend,
+				"done":   e.Done,
+				"total":  e.Total,
+			})
+		}
+	}
 
 	// Parse friends array
 	friendsJS := configJS.Get("friends")
@@ -113,7 +132,7 @@ func createBundlesJS(this js.Value, args []js.Value) any {
 	}
 
 	// Create bundles
-	bundles, err := createBundles(config)
+	bundles, projectArchive, err := createBundles(config)
 	if err != nil {
 		return errorResult(err.Error())
 	}
@@ -130,319 +149,106 @@ func createBundlesJS(this js.Value, args []js.Value) any {
 		}
 	}
 
+	jsProject := js.Global().Get("Uint8Array").New(len(projectArchive))
+	js.CopyBytesToJS(jsProject, projectArchive)
+
 	return js.ValueOf(map[string]any{
 		"bundles": jsBundles,
-		"error":   nil,
+		"project": map[string]any{
+			"fileName": bundle.ProjectArchiveName,
+			"data":     jsProject,
+		},
+		"error": nil,
 	})
 }
 
-// createBundles creates bundles for all friends.
-func createBundles(config CreateBundlesConfig) ([]BundleOutput, error) {
+// createBundles creates bundles for all friends, and a project archive
+// recording the seal, so the CLI can manage the project afterwards.
+func createBundles(config CreateBundlesConfig) ([]BundleOutput, []byte, error) {
 	// Validate inputs
 	if config.ProjectName == "" {
-		return nil, fmt.Errorf("project name is required")
+		return nil, nil, fmt.Errorf("project name is required")
 	}
 	if len(config.Friends) < 2 {
-		return nil, fmt.Errorf("need at least 2 friends, got %d", len(config.Friends))
+		return nil, nil, fmt.Errorf("need at least 2 friends, got %d", len(config.Friends))
 	}
 	if config.Threshold < 2 {
-		return nil, fmt.Errorf("threshold must be at least 2, got %d", config.Threshold)
+		return nil, nil, fmt.Errorf("threshold must be at least 2, got %d", config.Threshold)
 	}
 	if config.Threshold > len(config.Friends) {
-		return nil, fmt.Errorf("threshold (%d) cannot exceed number of friends (%d)", config.Threshold, len(config.Friends))
+		return nil, nil, fmt.Errorf("threshold (%d) cannot exceed number of friends (%d)", config.Threshold, len(config.Friends))
 	}
 	if len(config.Files) == 0 {
-		return nil, fmt.Errorf("no files provided")
+		return nil, nil, fmt.Errorf("no files provided")
 	}
 
 	// Validate friends
 	for i, f := range config.Friends {
 		if f.Name == "" {
-			return nil, fmt.Errorf("friend %d: name is required", i+1)
+			return nil, nil, fmt.Errorf("friend %d: name is required", i+1)
 		}
 	}
 
-	// Create tar.gz archive of files
-	archiveData, err := createTarGz(config.Files)
-	if err != nil {
-		return nil, fmt.Errorf("creating archive: %w", err)
-	}
-
-	// Generate random passphrase (v2: split raw bytes, not the base64 string)
-	raw, passphrase, err := crypto.GenerateRawPassphrase(crypto.DefaultPassphraseBytes)
-	if err != nil {
-		return nil, fmt.Errorf("generating passphrase: %w", err)
-	}
-
-	// Encrypt archive
-	var encryptedBuf bytes.Buffer
-	if err := core.Encrypt(&encryptedBuf, bytes.NewReader(archiveData), passphrase); err != nil {
-		return nil, fmt.Errorf("encrypting archive: %w", err)
+	p := &project.Project{
+		Name:      config.ProjectName,
+		Threshold: config.Threshold,
+		Language:  config.DefaultLanguage,
+		Anonymous: config.Anonymous,
+		Friends:   make([]project.Friend, len(config.Friends)),
 	}
-	manifestData := encryptedBuf.Bytes()
-	manifestChecksum := core.HashBytes(manifestData)
-
-	// Split passphrase using Shamir's Secret Sharing
-	n := len(config.Friends)
-	k := config.Threshold
-	rawShares, err := core.Split(raw, n, k)
-	if err != nil {
-		return nil, fmt.Errorf("splitting passphrase: %w", err)
-	}
-
-	// Current timestamp for all bundles
-	now := time.Now().UTC()
-
-	// Get recovery WASM bytes for embedding in recover.html
-	// Note: In WASM context, we use the embedded recover.wasm (smaller, recovery-only)
-	wasmBytes := html.GetRecoverWASMBytes()
-
-	// Create shares and bundles
-	bundles := make([]BundleOutput, n)
-	shares := make([]*core.Share, n)
-
-	// Create all shares first
-	for i, friend := range config.Friends {
-		share := &core.Share{
-			Version:   2,
-			Index:     i + 1,
-			Total:     n,
-			Threshold: k,
-			Holder:    friend.Name,
-			Created:   now,
-			Data:      rawShares[i],
-			Checksum:  core.HashBytes(rawShares[i]),
-		}
-		shares[i] = share
-	}
-
-	// Convert friends to project.Friend for bundle generation
-	projectFriends := make([]project.Friend, len(config.Friends))
 	for i, f := range config.Friends {
-		projectFriends[i] = project.Friend{
+		p.Friends[i] = project.Friend{
 			Name:     f.Name,
 			Contact:  f.Contact,
 			Language: f.Language,
 		}
 	}
-
-	// Generate bundle for each friend
-	for i, friend := range config.Friends {
-		share := shares[i]
-
-		// Resolve language: friend override > project default > "en"
-		lang := friend.Language
-		if lang == "" {
-			lang = config.DefaultLanguage
-		}
-		if lang == "" {
-			lang = "en"
-		}
-
-		// Get other friends (excluding this one) - empty for anonymous mode
-		var otherFriends []project.Friend
-		var otherFriendsInfo []html.FriendInfo
-		if !config.Anonymous {
-			otherFriends = make([]project.Friend, 0, n-1)
-			otherFriendsInfo = make([]html.FriendInfo, 0, n-1)
-			for j, f := range projectFriends {
-				if j != i {
-					otherFriends = append(otherFriends, f)
-					otherFriendsInfo = append(otherFriendsInfo, html.FriendInfo{
-						Name:       f.Name,
-						Contact:    f.Contact,
-						ShareIndex: j + 1, // 1-based share index
-					})
-				}
-			}
-		}
-
-		// Generate personalized recover.html
-		personalization := &html.PersonalizationData{
-			Holder:       friend.Name,
-			HolderShare:  share.Encode(),
-			OtherFriends: otherFriendsInfo,
-			Threshold:    k,
-			Total:        n,
-			Language:     lang,
-		}
-
-		// Embed manifest in recover.html when small enough
-		manifestEmbedded := len(manifestData) <= html.MaxEmbeddedManifestSize
-		if manifestEmbedded {
-			personalization.ManifestB64 = base64.StdEncoding.EncodeToString(manifestData)
-		}
-
-		recoverHTML := html.GenerateRecoverHTML(wasmBytes, config.Version, config.GitHubURL, personalization)
-		recoverChecksum := core.HashString(recoverHTML)
-
-		// Generate README.txt
-		readmeData := bundle.ReadmeData{
-			ProjectName:      config.ProjectName,
-			Holder:           friend.Name,
-			Share:            share,
-			OtherFriends:     otherFriends,
-			Threshold:        k,
-			Total:            n,
-			Version:          config.Version,
-			GitHubReleaseURL: config.GitHubURL,
-			ManifestChecksum: manifestChecksum,
-			RecoverChecksum:  recoverChecksum,
-			Created:          now,
-			Anonymous:        config.Anonymous,
-			Language:         lang,
-			ManifestEmbedded: manifestEmbedded,
-		}
-		readmeContent := bundle.GenerateReadme(readmeData)
-
-		// Generate README.pdf
-		// Web-created bundles always use the GitHub Pages recovery URL
-		pdfData := pdf.ReadmeData{
-			ProjectName:      config.ProjectName,
-			Holder:           friend.Name,
-			Share:            share,
-			OtherFriends:     otherFriends,
-			Threshold:        k,
-			Total:            n,
-			Version:          config.Version,
-			GitHubReleaseURL: config.GitHubURL,
-			ManifestChecksum: manifestChecksum,
-			RecoverChecksum:  recoverChecksum,
-			Created:          now,
-			Anonymous:        config.Anonymous,
-			Language:         lang,
-			ManifestEmbedded: manifestEmbedded,
-		}
-		pdfContent, err := pdf.GenerateReadme(pdfData)
-		if err != nil {
-			return nil, fmt.Errorf("generating PDF for %s: %w", friend.Name, err)
-		}
-
-		// Create ZIP bundle.
-		// When the manifest is embedded in recover.html, skip the separate MANIFEST.age
-		// file to avoid duplicating data and inflating the ZIP size.
-		readmeFileTxt := translations.ReadmeFilename(lang, ".txt")
-		readmeFilePdf := translations.ReadmeFilename(lang, ".pdf")
-		zipFiles := []bundle.ZipFile{
-			{Name: readmeFileTxt, Content: []byte(readmeContent), ModTime: now},
-			{Name: readmeFilePdf, Content: pdfContent, ModTime: now},
-			{Name: "recover.html", Content: []byte(recoverHTML), ModTime: now},
-		}
-		if !manifestEmbedded {
-			zipFiles = append(zipFiles, bundle.ZipFile{Name: "MANIFEST.age", Content: manifestData, ModTime: now})
-		}
-
-		zipData, err := createZipInMemory(zipFiles)
-		if err != nil {
-			return nil, fmt.Errorf("creating ZIP for %s: %w", friend.Name, err)
-		}
-
-		bundles[i] = BundleOutput{
-			FriendName: friend.Name,
-			FileName:   fmt.Sprintf("bundle-%s.zip", core.SanitizeFilename(friend.Name)),
-			Data:       zipData,
-		}
-	}
-
-	return bundles, nil
-}
-
-// createTarGz creates a tar.gz archive from file entries.
-func createTarGz(files []FileEntry) ([]byte, error) {
-	var buf bytes.Buffer
-	gzw := gzip.NewWriter(&buf)
-	tw := tar.NewWriter(gzw)
-
-	// Use "manifest" as the root directory name
-	rootDir := "manifest"
-
-	// Add root directory entry
-	if err := tw.WriteHeader(&tar.Header{
-		Name:     rootDir + "/",
-		Mode:     0755,
-		Typeflag: tar.TypeDir,
-		ModTime:  time.Now().UTC(),
-	}); err != nil {
-		return nil, fmt.Errorf("writing directory header: %w", err)
+	files := make([]manifest.File, len(config.Files))
+	for i, f := range config.Files {
+		files[i] = manifest.File{Name: f.Name, Data: f.Data}
 	}
 
-	for _, f := range files {
-		// Normalize the file path - ensure it's under manifest/
-		name := f.Name
-		// Remove leading slashes or "manifest/" prefix if present
-		name = trimLeadingSlashes(name)
-		// Security: reject path traversal attempts
-		if strings.Contains(name, "..") {
-			return nil, fmt.Errorf("invalid path in file entry: %s", f.Name)
-		}
-		if len(name) > 9 && name[:9] == "manifest/" {
-			name = name[9:]
-		}
-		// Add the manifest/ prefix
-		fullPath := rootDir + "/" + name
-
-		header := &tar.Header{
-			Name:     fullPath,
-			Mode:     0644,
-			Size:     int64(len(f.Data)),
-			ModTime:  time.Now().UTC(),
-			Typeflag: tar.TypeReg,
-		}
-
-		if err := tw.WriteHeader(header); err != nil {
-			return nil, fmt.Errorf("writing header for %s: %w", f.Name, err)
-		}
-
-		if _, err := tw.Write(f.Data); err != nil {
-			return nil, fmt.Errorf("writing data for %s: %w", f.Name, err)
-		}
+	// Same engine as 'rememory seal': archive, encrypt, split and verify
+	sealed, err := bundle.SealFiles(p, files, config.Progress)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := tw.Close(); err != nil {
-		return nil, fmt.Errorf("closing tar: %w", err)
-	}
-	if err := gzw.Close(); err != nil {
-		return nil, fmt.Errorf("closing gzip: %w", err)
+	// Recovery-only WASM for recover.html (smaller than the creation one)
+	cfg := bundle.Config{
+		Version:          config.Version,
+		GitHubReleaseURL: config.GitHubURL,
+		WASMBytes:        html.GetRecoverWASMBytes(),
+		RecoveryURL:      config.RecoveryURL,
+		NoEmbedManifest:  config.NoEmbedManifest,
+		Progress:         config.Progress,
 	}
-
-	return buf.Bytes(), nil
-}
-
-// createZipInMemory creates a ZIP archive in memory.
-func createZipInMemory(files []bundle.ZipFile) ([]byte, error) {
-	var buf bytes.Buffer
-	w := zip.NewWriter(&buf)
-
-	for _, file := range files {
-		header := &zip.FileHeader{
-			Name:   file.Name,
-			Method: zip.Deflate,
-		}
-		header.Modified = file.ModTime
-
-		fw, err := w.CreateHeader(header)
-		if err != nil {
-			return nil, fmt.Errorf("creating entry %s: %w", file.Name, err)
-		}
-
-		if _, err := fw.Write(file.Content); err != nil {
-			return nil, fmt.Errorf("writing entry %s: %w", file.Name, err)
-		}
+	at := time.Now().UTC()
+	var bundles []BundleOutput
+	err = bundle.Build(p, sealed.Shares, sealed.Manifest, at, cfg, func(b bundle.Bundle) error {
+		bundles = append(bundles, BundleOutput{
+			FriendName: b.Friend.Name,
+			FileName:   b.FileName,
+			Data:       b.Data,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := w.Close(); err != nil {
-		return nil, fmt.Errorf("closing zip: %w", err)
+	// The shares only exist inside the bundles, as in an ephemeral seal
+	p.Created = at.Format("2006-01-02")
+	p.AddSeal(sealed.Record(p, at, cfg))
+	var projectManifest []byte
+	if !config.NoProjectManifest {
+		projectManifest = sealed.Manifest
 	}
-
-	return buf.Bytes(), nil
-}
-
-// trimLeadingSlashes removes leading slashes from a path.
-func trimLeadingSlashes(s string) string {
-	for len(s) > 0 && (s[0] == '/' || s[0] == '\\') {
-		s = s[1:]
+	var projectArchive bytes.Buffer
+	if err := bundle.WriteProjectArchive(&projectArchive, p, projectManifest); err != nil {
+		return nil, nil, fmt.Errorf("creating project archive: %w", err)
 	}
-	return s
+	return bundles, projectArchive.Bytes(), nil
 }
 
 // parseProjectYAMLJS parses a project.yml file to extract friend information.
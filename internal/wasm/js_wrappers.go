@@ -3,7 +3,11 @@
 package main
 
 import (
+	"errors"
+	"io"
 	"syscall/js"
+
+	"github.com/eljojo/rememory/internal/core"
 )
 
 // parseShareJS parses a share from text content.
@@ -26,17 +30,9 @@ func parseShareJS(this js.Value, args []js.Value) any {
 	})
 }
 
-// combineSharesJS combines multiple shares to recover the passphrase.
-// Args: sharesJSON (array of share objects with dataB64)
-// Returns: { passphrase: string, error: string|null }
-func combineSharesJS(this js.Value, args []js.Value) any {
-	if len(args) < 1 {
-		return errorResult("missing shares argument")
-	}
-
-	sharesArray := args[0]
+// readShares reads share objects (with dataB64) from a JS array.
+func readShares(sharesArray js.Value) []ShareData {
 	length := sharesArray.Length()
-
 	shares := make([]ShareData, length)
 	for i := 0; i < length; i++ {
 		shareObj := sharesArray.Index(i)
@@ -47,24 +43,16 @@ func combineSharesJS(this js.Value, args []js.Value) any {
 			DataB64:   shareObj.Get("dataB64").String(),
 		}
 	}
-
-	passphrase, err := combineShares(shares)
-	if err != nil {
-		return errorResult(err.Error())
-	}
-
-	return js.ValueOf(map[string]any{
-		"passphrase": passphrase,
-		"error":      nil,
-	})
+	return shares
 }
 
-// decryptManifestJS decrypts an age-encrypted manifest.
-// Args: encryptedData (Uint8Array), passphrase (string)
+// decryptManifestJS combines shares and decrypts an age-encrypted manifest.
+// The passphrase stays inside WASM memory and is wiped afterwards.
+// Args: encryptedData (Uint8Array), shares (array of share objects with dataB64)
 // Returns: { data: Uint8Array, error: string|null }
 func decryptManifestJS(this js.Value, args []js.Value) any {
 	if len(args) < 2 {
-		return errorResult("missing arguments (need encryptedData, passphrase)")
+		return errorResult("missing arguments (need encryptedData, shares)")
 	}
 
 	// Read Uint8Array from JS
@@ -73,9 +61,7 @@ func decryptManifestJS(this js.Value, args []js.Value) any {
 	encryptedData := make([]byte, dataLen)
 	js.CopyBytesToGo(encryptedData, jsData)
 
-	passphrase := args[1].String()
-
-	decrypted, err := decryptManifest(encryptedData, passphrase)
+	decrypted, err := decryptManifest(encryptedData, readShares(args[1]))
 	if err != nil {
 		return errorResult(err.Error())
 	}
@@ -126,9 +112,151 @@ func extractTarGzJS(this js.Value, args []js.Value) any {
 	})
 }
 
+// decryptStreamJS starts decrypting a manifest fed to it in chunks, so
+// neither the whole ciphertext nor the whole archive is ever copied into
+// WASM memory. Shares are combined right away; the passphrase stays inside
+// WASM and is wiped once decryption ends.
+// Args: shares (array of share objects with dataB64), options: {
+//
+//	size: number,                                   total ciphertext bytes, for onProgress
+//	onFile(name: string, data: Uint8Array),         called for each file, in archive order
+//	onProgress?(read: number, size: number),        ciphertext bytes consumed so far
+//	onArchive?(chunk: Uint8Array),                  the decrypted tar.gz, as it's read
+//
+// }
+// Returns: { write(chunk: Uint8Array): Promise, close(): Promise<{files: number}>,
+// abort(): void, error: string|null }
+// write resolves once the chunk is consumed and rejects once decryption
+// fails. Files are only complete once close resolves.
+func decryptStreamJS(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return errorResult("missing arguments (need shares, options)")
+	}
+	passphrase, err := combineShares(readShares(args[0]))
+	if err != nil {
+		return errorResult(err.Error())
+	}
+	options := args[1]
+	size := options.Get("size").Int()
+	onFile := options.Get("onFile")
+	onProgress := options.Get("onProgress")
+	onArchive := options.Get("onArchive")
+
+	pr, pw := io.Pipe()
+	src := &progressReader{r: pr, progress: func(read int64) {
+		if onProgress.Type() == js.TypeFunction {
+			onProgress.Invoke(read, size)
+		}
+	}}
+	var archive io.Writer
+	if onArchive.Type() == js.TypeFunction {
+		archive = jsChunkWriter{onArchive}
+	}
+
+	type streamResult struct {
+		files int
+		err   error
+	}
+	done := make(chan streamResult, 1)
+	go func() {
+		files, err := decryptStream(src, passphrase, archive, func(name string, data []byte) error {
+			onFile.Invoke(name, bytesToJS(data))
+			return nil
+		})
+		// Unblock a pending write, and fail later ones
+		pr.CloseWithError(err)
+		done <- streamResult{files, err}
+	}()
+
+	write := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < 1 {
+			return rejectedPromise(errors.New("missing chunk argument"))
+		}
+		chunk := make([]byte, args[0].Get("length").Int())
+		js.CopyBytesToGo(chunk, args[0])
+		return newPromise(func() (any, error) {
+			_, err := pw.Write(chunk)
+			return nil, err
+		})
+	})
+	var release func()
+	closeFn := js.FuncOf(func(this js.Value, args []js.Value) any {
+		pw.Close()
+		return newPromise(func() (any, error) {
+			result := <-done
+			done <- result
+			release()
+			if result.err != nil {
+				return nil, result.err
+			}
+			return map[string]any{"files": result.files}, nil
+		})
+	})
+	abort := js.FuncOf(func(this js.Value, args []js.Value) any {
+		pw.CloseWithError(errors.New("recovery cancelled"))
+		return nil
+	})
+	release = func() {
+		write.Release()
+		closeFn.Release()
+		abort.Release()
+	}
+
+	return js.ValueOf(map[string]any{
+		"write": write,
+		"close": closeFn,
+		"abort": abort,
+		"error": nil,
+	})
+}
+
+// jsChunkWriter passes everything written to it to a JS callback, as a
+// Uint8Array.
+type jsChunkWriter struct {
+	fn js.Value
+}
+
+func (w jsChunkWriter) Write(p []byte) (int, error) {
+	w.fn.Invoke(bytesToJS(p))
+	return len(p), nil
+}
+
+// bytesToJS copies data into a new Uint8Array.
+func bytesToJS(data []byte) js.Value {
+	jsData := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(jsData, data)
+	return jsData
+}
+
+// newPromise returns a Promise settled by fn, which runs on its own
+// goroutine so it may block: a JS callback can't.
+func newPromise(fn func() (any, error)) js.Value {
+	var executor js.Func
+	executor = js.FuncOf(func(this js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			defer executor.Release()
+			value, err := fn()
+			if err != nil {
+				reject.Invoke(js.Global().Get("Error").New(err.Error()))
+				return
+			}
+			resolve.Invoke(value)
+		}()
+		return nil
+	})
+	return js.Global().Get("Promise").New(executor)
+}
+
+// rejectedPromise returns a Promise rejected with err.
+func rejectedPromise(err error) js.Value {
+	return js.Global().Get("Promise").Call("reject", js.Global().Get("Error").New(err.Error()))
+}
+
 // extractBundleJS extracts share and manifest from a bundle ZIP.
 // Args: zipData (Uint8Array)
-// Returns: { share: {...}, manifest: Uint8Array|null, error: string|null }
+// Returns: { share: {...}, manifest: Uint8Array|null, signedBy: string, repairedBlocks: number, error: string|null }
+// A bundle whose signature doesn't match also has badSignature: true.
 func extractBundleJS(this js.Value, args []js.Value) any {
 	if len(args) < 1 {
 		return errorResult("missing zipData argument")
@@ -141,13 +269,21 @@ func extractBundleJS(this js.Value, args []js.Value) any {
 	js.CopyBytesToGo(zipData, jsData)
 
 	bundle, err := extractBundle(zipData)
+	if errors.Is(err, core.ErrBadSignature) {
+		return js.ValueOf(map[string]any{
+			"error":        err.Error(),
+			"badSignature": true,
+		})
+	}
 	if err != nil {
 		return errorResult(err.Error())
 	}
 
 	result := map[string]any{
-		"share": shareInfoToJS(bundle.Share),
-		"error": nil,
+		"share":          shareInfoToJS(bundle.Share),
+		"signedBy":       bundle.SignedBy,
+		"repairedBlocks": bundle.Repaired,
+		"error":          nil,
 	}
 
 	// Include manifest if present
@@ -162,6 +298,72 @@ func extractBundleJS(this js.Value, args []js.Value) any {
 	return js.ValueOf(result)
 }
 
+// repairManifestJS repairs a damaged MANIFEST.age with its parity data.
+// Args: manifest (Uint8Array), parity (Uint8Array)
+// Returns: { data: Uint8Array, repaired: number, error: string|null }
+func repairManifestJS(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return errorResult("missing arguments (need manifest, parity)")
+	}
+
+	manifestData := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(manifestData, args[0])
+	parityData := make([]byte, args[1].Get("length").Int())
+	js.CopyBytesToGo(parityData, args[1])
+
+	repaired, n, err := repairManifest(manifestData, parityData)
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	jsResult := js.Global().Get("Uint8Array").New(len(repaired))
+	js.CopyBytesToJS(jsResult, repaired)
+	return js.ValueOf(map[string]any{
+		"data":     jsResult,
+		"repaired": n,
+		"error":    nil,
+	})
+}
+
+// readPaperManifestJS puts MANIFEST.age back together from the lines printed
+// in README.pdf, typed or scanned from its QR codes.
+// Args: text (string, every line read so far)
+// Returns: { data: Uint8Array|null, lines: number, missing: number[], badLines: number[], mismatch: boolean, error: string|null }
+func readPaperManifestJS(this js.Value, args []js.Value) any {
+	if len(args) < 1 {
+		return errorResult("missing text argument")
+	}
+
+	result := readPaperManifest(args[0].String())
+	var data any
+	if result.Data != nil {
+		jsData := js.Global().Get("Uint8Array").New(len(result.Data))
+		js.CopyBytesToJS(jsData, result.Data)
+		data = jsData
+	}
+	var errMsg any
+	if result.Err != "" {
+		errMsg = result.Err
+	}
+	return js.ValueOf(map[string]any{
+		"data":     data,
+		"lines":    result.Lines,
+		"missing":  intsToJS(result.Missing),
+		"badLines": intsToJS(result.BadLines),
+		"mismatch": result.Mismatch,
+		"error":    errMsg,
+	})
+}
+
+// intsToJS converts a slice of ints to a JS array.
+func intsToJS(values []int) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
 // parseCompactShareJS parses a compact-encoded share string (e.g. RM1:2:5:3:BASE64:CHECK).
 // Args: compact (string)
 // Returns: { share: {...}, error: string|null }
@@ -217,6 +419,25 @@ func decodeWordsJS(this js.Value, args []js.Value) any {
 	})
 }
 
+// respondChallengeJS answers a proof-of-possession challenge with a share.
+// Args: dataB64 (string), challenge (string)
+// Returns: { code: string, error: string|null }
+func respondChallengeJS(this js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return errorResult("missing share or challenge argument")
+	}
+
+	code, err := respondToChallenge(args[0].String(), args[1].String())
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	return js.ValueOf(map[string]any{
+		"code":  code,
+		"error": nil,
+	})
+}
+
 // shareInfoToJS converts a ShareInfo to a JS-compatible map.
 func shareInfoToJS(s *ShareInfo) map[string]any {
 	return map[string]any{
@@ -4,17 +4,23 @@ package main
 
 import (
 	"syscall/js"
+
+	"github.com/eljojo/rememory/internal/core"
 )
 
 func main() {
 	// Register recovery functions on the global object
 	js.Global().Set("rememoryParseShare", js.FuncOf(parseShareJS))
-	js.Global().Set("rememoryCombineShares", js.FuncOf(combineSharesJS))
 	js.Global().Set("rememoryDecryptManifest", js.FuncOf(decryptManifestJS))
 	js.Global().Set("rememoryExtractTarGz", js.FuncOf(extractTarGzJS))
+	js.Global().Set("rememoryDecryptStream", js.FuncOf(decryptStreamJS))
 	js.Global().Set("rememoryExtractBundle", js.FuncOf(extractBundleJS))
+	js.Global().Set("rememoryRepairManifest", js.FuncOf(repairManifestJS))
+	js.Global().Set("rememoryReadPaperManifest", js.FuncOf(readPaperManifestJS))
 	js.Global().Set("rememoryParseCompactShare", js.FuncOf(parseCompactShareJS))
 	js.Global().Set("rememoryDecodeWords", js.FuncOf(decodeWordsJS))
+	js.Global().Set("rememoryRespondChallenge", js.FuncOf(respondChallengeJS))
+	js.Global().Set("rememoryChallengeWords", core.ChallengeWords)
 
 	// Signal that WASM is ready
 	js.Global().Set("rememoryReady", true)
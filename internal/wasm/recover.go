@@ -6,8 +6,10 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/eljojo/rememory/internal/core"
 	"github.com/eljojo/rememory/internal/translations"
@@ -78,30 +80,35 @@ func shareToInfo(share *core.Share) *ShareInfo {
 }
 
 // combineShares combines multiple shares to recover the passphrase.
-// Uses core.Combine for the actual combination.
-func combineShares(shares []ShareData) (string, error) {
+// Uses core.Combine for the actual combination. Wipe the result when done.
+func combineShares(shares []ShareData) (*core.Secret, error) {
 	if len(shares) < 2 {
-		return "", fmt.Errorf("need at least 2 shares, got %d", len(shares))
+		return nil, fmt.Errorf("need at least 2 shares, got %d", len(shares))
 	}
 
 	// Validate all shares have the same version
 	for i := 1; i < len(shares); i++ {
 		if shares[i].Version != shares[0].Version {
-			return "", fmt.Errorf("share %d has different version (v%d vs v%d) — all shares must be from the same bundle", i+1, shares[i].Version, shares[0].Version)
+			return nil, fmt.Errorf("share %d has different version (v%d vs v%d) — all shares must be from the same bundle", i+1, shares[i].Version, shares[0].Version)
 		}
 	}
 
 	// Validate threshold is met (shares carry the threshold from parsing)
 	if shares[0].Threshold > 0 && len(shares) < shares[0].Threshold {
-		return "", fmt.Errorf("need at least %d shares to recover, got %d", shares[0].Threshold, len(shares))
+		return nil, fmt.Errorf("need at least %d shares to recover, got %d", shares[0].Threshold, len(shares))
 	}
 
 	// Convert to raw bytes for core.Combine
 	rawShares := make([][]byte, len(shares))
+	defer func() {
+		for _, data := range rawShares {
+			core.Wipe(data)
+		}
+	}()
 	for i, s := range shares {
 		data, err := base64.StdEncoding.DecodeString(s.DataB64)
 		if err != nil {
-			return "", fmt.Errorf("decoding share %d: %w", i+1, err)
+			return nil, fmt.Errorf("decoding share %d: %w", i+1, err)
 		}
 		rawShares[i] = data
 	}
@@ -109,16 +116,37 @@ func combineShares(shares []ShareData) (string, error) {
 	// Use core.Combine
 	secret, err := core.Combine(rawShares)
 	if err != nil {
-		return "", fmt.Errorf("combining shares: %w", err)
+		return nil, fmt.Errorf("combining shares: %w", err)
 	}
+	defer core.Wipe(secret)
+
+	return core.RecoverSecret(secret, shares[0].Version), nil
+}
 
-	return core.RecoverPassphrase(secret, shares[0].Version), nil
+// respondToChallenge answers an owner's proof-of-possession challenge with a share.
+// Uses core.ChallengeResponse, so the code matches 'rememory respond'.
+func respondToChallenge(dataB64, challenge string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(dataB64)
+	if err != nil {
+		return "", fmt.Errorf("decoding share: %w", err)
+	}
+	challenge = core.NormalizeChallenge(challenge)
+	if n := len(strings.Fields(challenge)); n != core.ChallengeWords {
+		return "", fmt.Errorf("expected a challenge of %d words, got %d", core.ChallengeWords, n)
+	}
+	return core.ChallengeResponse(core.ChallengeKey(data), challenge), nil
 }
 
-// decryptManifest decrypts age-encrypted data using a passphrase.
-// Uses core.DecryptBytes for the actual decryption.
-func decryptManifest(encryptedData []byte, passphrase string) ([]byte, error) {
-	return core.DecryptBytes(encryptedData, passphrase)
+// decryptManifest combines shares and decrypts age-encrypted data with the
+// passphrase they give. The passphrase never leaves Go, and is wiped before
+// returning. Uses core.DecryptBytesSecret for the actual decryption.
+func decryptManifest(encryptedData []byte, shares []ShareData) ([]byte, error) {
+	passphrase, err := combineShares(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer passphrase.Wipe()
+	return core.DecryptBytesSecret(encryptedData, passphrase)
 }
 
 // extractTarGz extracts files from tar.gz data in memory.
@@ -127,6 +155,67 @@ func extractTarGz(tarGzData []byte) ([]core.ExtractedFile, error) {
 	return core.ExtractTarGz(tarGzData)
 }
 
+// decryptStream decrypts MANIFEST.age as it's read from src and extracts
+// its files one at a time, passing each to onFile, so only one file is in
+// memory at once. When archive isn't nil, the decrypted tar.gz is also
+// written to it as it's read. The passphrase is wiped before returning.
+//
+// age authenticates every chunk before it's used, but a truncated
+// MANIFEST.age is only noticed at its end: the files passed to onFile are
+// only complete once decryptStream returns without an error.
+func decryptStream(src io.Reader, passphrase *core.Secret, archive io.Writer, onFile func(name string, data []byte) error) (int, error) {
+	defer passphrase.Wipe()
+	plain, err := core.DecryptReaderSecret(src, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	plain = decryptErrorReader{plain}
+	if archive != nil {
+		plain = io.TeeReader(plain, archive)
+	}
+
+	count := 0
+	err = core.WalkTarGz(plain, func(name string, size int64, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("reading file %s from archive: %w", name, err)
+		}
+		count++
+		return onFile(name, data)
+	})
+	return count, err
+}
+
+// decryptErrorReader marks errors from age's reader, which reach the caller
+// through gzip and tar, as decryption errors.
+type decryptErrorReader struct {
+	r io.Reader
+}
+
+func (d decryptErrorReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("decrypting: %w", err)
+	}
+	return n, err
+}
+
+// progressReader calls progress with the total bytes read so far.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	progress func(read int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.progress(p.read)
+	}
+	return n, err
+}
+
 // decodeShareWords converts 25 BIP39 words to raw share data bytes and share index.
 // Auto-detects the word list language. The first 24 words encode the data;
 // the 25th word packs 4 bits of index + 7 bits of checksum.
@@ -142,7 +231,9 @@ func decodeShareWords(words []string) ([]byte, int, string, string, error) {
 // BundleContents represents extracted content from a bundle ZIP.
 type BundleContents struct {
 	Share    *ShareInfo // Parsed share from README.txt
-	Manifest []byte     // Raw MANIFEST.age content
+	Manifest []byte     // Raw MANIFEST.age content, repaired if it was damaged
+	SignedBy string     // Fingerprint of the key that signed the bundle, if it's signed
+	Repaired int        // Damaged blocks of MANIFEST.age rebuilt from its parity data
 }
 
 // extractBundle extracts share and manifest from a bundle ZIP file.
@@ -157,7 +248,10 @@ func extractBundle(zipData []byte) (*BundleContents, error) {
 
 	var readmeContent string
 	var manifestData []byte
+	var signatureData []byte
+	var parityData []byte
 	var totalSize int64
+	contents := make(map[string][]byte)
 
 	for _, f := range r.File {
 		rc, err := f.Open()
@@ -181,6 +275,16 @@ func extractBundle(zipData []byte) (*BundleContents, error) {
 			return nil, fmt.Errorf("bundle exceeds maximum total size (%d bytes)", core.MaxTotalSize)
 		}
 
+		switch f.Name {
+		case core.SignatureFileName:
+			signatureData = data
+			continue
+		case core.ParityFileName:
+			parityData = data
+			continue
+		}
+		contents[f.Name] = data
+
 		switch {
 		case translations.IsReadmeFile(f.Name, ".txt"):
 			readmeContent = string(data)
@@ -189,6 +293,30 @@ func extractBundle(zipData []byte) (*BundleContents, error) {
 		}
 	}
 
+	// Repair a damaged MANIFEST.age before checking the signature, which
+	// covers the manifest as it was sealed
+	var repaired int
+	if manifestData != nil && parityData != nil {
+		if manifestData, repaired, err = repairManifest(manifestData, parityData); err != nil {
+			return nil, err
+		}
+		contents["MANIFEST.age"] = manifestData
+	}
+
+	// A signature that doesn't match means the bundle was altered after
+	// the owner signed it, so nothing in it can be trusted.
+	var signedBy string
+	if signatureData != nil {
+		sig, err := core.ParseBundleSignature(signatureData)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v: %w", core.SignatureFileName, err, core.ErrBadSignature)
+		}
+		if err := sig.VerifyFiles(contents); err != nil {
+			return nil, err
+		}
+		signedBy = sig.Fingerprint()
+	}
+
 	if readmeContent == "" {
 		return nil, fmt.Errorf("README file not found in bundle")
 	}
@@ -202,5 +330,53 @@ func extractBundle(zipData []byte) (*BundleContents, error) {
 	return &BundleContents{
 		Share:    share,
 		Manifest: manifestData,
+		SignedBy: signedBy,
+		Repaired: repaired,
 	}, nil
 }
+
+// repairManifest rebuilds the damaged blocks of MANIFEST.age from its parity
+// data. Parity data that can't be read is ignored, so it never stands in
+// the way of an intact manifest.
+func repairManifest(manifestData, parityData []byte) ([]byte, int, error) {
+	repaired, n, err := core.Repair(manifestData, parityData)
+	if errors.Is(err, core.ErrUnrepairable) {
+		return nil, 0, fmt.Errorf("MANIFEST.age is damaged: %w", err)
+	}
+	if err != nil {
+		return manifestData, 0, nil
+	}
+	return repaired, n, nil
+}
+
+// PaperManifest is MANIFEST.age read back from the lines printed in
+// README.pdf, as far as it got.
+type PaperManifest struct {
+	Data     []byte // nil until every line is read
+	Lines    int    // 0 until the header is read
+	Missing  []int
+	BadLines []int  // lines that don't match their check code
+	Mismatch bool   // every line is read, but they don't match the header
+	Err      string // anything else, such as lines of two manifests
+}
+
+// readPaperManifest reads every line typed or scanned so far. Bad lines
+// are reported, not fatal: the friend fixes them and tries again.
+func readPaperManifest(text string) *PaperManifest {
+	r := core.NewPaperReader()
+	result := &PaperManifest{}
+	var lineErr *core.PaperLineError
+	if err := r.Add(text); errors.As(err, &lineErr) {
+		result.BadLines = lineErr.Lines
+	} else if err != nil {
+		result.Err = err.Error()
+	}
+	result.Lines = r.Lines()
+	result.Missing = r.Missing()
+	if result.Lines > 0 && len(result.Missing) == 0 {
+		data, err := r.Read()
+		result.Data = data
+		result.Mismatch = err != nil
+	}
+	return result
+}
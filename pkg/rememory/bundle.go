@@ -0,0 +1,173 @@
+package rememory
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/internal/project"
+)
+
+// Friend is a share holder as shown in bundles.
+type Friend = project.Friend
+
+// DefaultRecoveryURL is the recover.html the QR code in README.pdf points to
+// unless BundleOptions.RecoveryURL says otherwise.
+const DefaultRecoveryURL = core.DefaultRecoveryURL
+
+// BundleOptions describes one friend's bundle.
+type BundleOptions struct {
+	// Project is the name shown in the bundle.
+	Project string
+	// Friends lists every holder of the seal, in share order. The bundle
+	// belongs to Friends[Share.Index-1].
+	Friends []Friend
+	// Share is the share of the friend the bundle is for.
+	Share *Share
+	// Manifest is the MANIFEST.age written by Seal.
+	Manifest []byte
+	// Language of README and recover.html when the friend has none set.
+	// Defaults to "en".
+	Language string
+	// Anonymous leaves the other friends out of the bundle.
+	Anonymous bool
+	// Created is the date shown in the bundle and on its files.
+	Created time.Time
+	// RecoveryURL is the base URL of the QR code in README.pdf.
+	// Defaults to DefaultRecoveryURL.
+	RecoveryURL string
+	// NoEmbedManifest keeps MANIFEST.age out of recover.html even when it
+	// is small enough to embed.
+	NoEmbedManifest bool
+	// PaperManifest prints MANIFEST.age in README.pdf, as QR codes and
+	// typed lines, so the paper alone is enough to recover. Manifest must
+	// be at most MaxPaperManifestSize bytes.
+	PaperManifest bool
+	// Version is the rememory version named in the bundle; it picks the
+	// release README.txt links to. Defaults to "dev".
+	Version string
+	// Reproducible makes the same options always give the same bytes, so a
+	// bundle can be rebuilt and compared.
+	Reproducible bool
+	// Signer, when set, signs the bundle with the owner's Ed25519 key,
+	// such as an ed25519.PrivateKey. Its fingerprint is printed in the
+	// bundle, and VerifyBundleSignature returns it.
+	Signer crypto.Signer
+}
+
+// WriteBundle writes the bundle ZIP for opts.Share to w: README.txt,
+// README.pdf, a personalized recover.html and, unless it's embedded in
+// recover.html, MANIFEST.age.
+func WriteBundle(w io.Writer, opts BundleOptions) error {
+	if opts.Share == nil {
+		return fmt.Errorf("no share given")
+	}
+	i := opts.Share.Index - 1
+	if i < 0 || i >= len(opts.Friends) || len(opts.Friends) != opts.Share.Total {
+		return fmt.Errorf("share %d of %d doesn't match %d friends", opts.Share.Index, opts.Share.Total, len(opts.Friends))
+	}
+	if err := opts.Share.Verify(); err != nil {
+		return err
+	}
+
+	wasmBytes := html.GetRecoverWASMBytes()
+	if len(wasmBytes) == 0 {
+		return fmt.Errorf("recover.wasm not embedded - rebuild with 'make build'")
+	}
+	version := opts.Version
+	if version == "" {
+		version = "dev"
+	}
+	recoveryURL := opts.RecoveryURL
+	if recoveryURL == "" {
+		recoveryURL = DefaultRecoveryURL
+	}
+
+	p := &project.Project{
+		Name:      opts.Project,
+		Friends:   opts.Friends,
+		Threshold: opts.Share.Threshold,
+		Language:  opts.Language,
+		Anonymous: opts.Anonymous,
+	}
+	cfg := bundle.Config{
+		Version:          version,
+		GitHubReleaseURL: fmt.Sprintf("https://github.com/eljojo/rememory/releases/tag/%s", version),
+		WASMBytes:        wasmBytes,
+		RecoveryURL:      recoveryURL,
+		NoEmbedManifest:  opts.NoEmbedManifest,
+		PaperManifest:    opts.PaperManifest,
+		Reproducible:     opts.Reproducible,
+		Signer:           opts.Signer,
+	}
+	return bundle.WriteBundle(w, bundle.NewBundleParams(p, i, opts.Share, opts.Manifest, opts.Created, cfg))
+}
+
+// VerifyBundle checks a bundle ZIP of size bytes read from r: README.txt,
+// README.pdf and recover.html are present, MANIFEST.age and recover.html
+// match the checksums in README.txt, and the share is intact. Checksum
+// failures match ErrChecksumMismatch.
+func VerifyBundle(r io.ReaderAt, size int64) error {
+	return bundle.VerifyBundleReader(r, size)
+}
+
+// BundleSignature is the owner's signature over the files of a bundle.
+// Its Fingerprint tells whose key made it.
+type BundleSignature = core.BundleSignature
+
+// VerifyBundleSignature is VerifyBundle for bundles that may be signed. It
+// also checks the signature against the bundle's files, and returns it, or
+// nil when the bundle isn't signed. A signature that doesn't match matches
+// ErrBadSignature. Compare its Fingerprint with the owner's key: anyone can
+// sign a bundle with a key of their own.
+func VerifyBundleSignature(r io.ReaderAt, size int64) (*BundleSignature, error) {
+	return bundle.VerifySigned(r, size)
+}
+
+// ExtractManifest returns MANIFEST.age from a bundle ZIP, a recover.html
+// with the manifest embedded, or MANIFEST.age itself. A damaged MANIFEST.age
+// is repaired with the parity data the bundle keeps with it.
+func ExtractManifest(data []byte) ([]byte, error) {
+	return bundle.ExtractManifest(data)
+}
+
+// RepairManifest is ExtractManifest that also returns how many damaged
+// blocks of MANIFEST.age were rebuilt. A manifest too damaged to repair
+// matches ErrUnrepairable.
+func RepairManifest(data []byte) ([]byte, int, error) {
+	return bundle.RepairManifest(data)
+}
+
+// ParityFileName is the name of MANIFEST.age's parity data, kept next to it.
+const ParityFileName = core.ParityFileName
+
+// NewParity returns parity data for MANIFEST.age, to store next to it.
+// Blocks of MANIFEST.age damaged later, up to about 5% of them, can be
+// rebuilt from it with Repair.
+func NewParity(manifest []byte) []byte {
+	return core.NewParity(manifest)
+}
+
+// Repair rebuilds the damaged blocks of MANIFEST.age from its parity data,
+// and returns the repaired manifest and how many blocks were rebuilt.
+func Repair(manifest, parity []byte) ([]byte, int, error) {
+	return core.Repair(manifest, parity)
+}
+
+// MaxPaperManifestSize is the largest MANIFEST.age that can be printed on
+// paper with BundleOptions.PaperManifest.
+const MaxPaperManifestSize = core.MaxPaperManifestSize
+
+// PaperReader puts MANIFEST.age back together from the lines printed in
+// README.pdf: typed by hand, or the text of its scanned QR codes. Add them
+// in any order, then Read.
+type PaperReader = core.PaperReader
+
+// NewPaperReader returns an empty PaperReader.
+func NewPaperReader() *PaperReader {
+	return core.NewPaperReader()
+}
@@ -0,0 +1,12 @@
+// Package rememory seals a directory into an encrypted manifest and Shamir
+// shares, writes the bundles handed to friends, verifies them, and recovers
+// the files from enough shares. The rememory command is built on it.
+//
+// Functions take options structs and read and write through io.Reader and
+// io.Writer; nothing is printed and nothing is written to disk unless a
+// function is given a directory. Failures can be matched with errors.Is
+// against the Err variables.
+//
+// The API is versioned with the module: exported names and the formats they
+// produce only change incompatibly in a new major version.
+package rememory
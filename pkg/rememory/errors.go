@@ -0,0 +1,34 @@
+package rememory
+
+import (
+	"errors"
+
+	"github.com/eljojo/rememory/internal/core"
+)
+
+var (
+	// ErrChecksumMismatch means a share, MANIFEST.age or recover.html
+	// doesn't match its recorded checksum.
+	ErrChecksumMismatch = core.ErrChecksumMismatch
+
+	// ErrThresholdNotMet means fewer shares were given than the threshold.
+	ErrThresholdNotMet = core.ErrThresholdNotMet
+
+	// ErrMixedSeals means the shares disagree on version, total or
+	// threshold, so they can't come from the same seal.
+	ErrMixedSeals = core.ErrMixedSeals
+
+	// ErrDuplicateShare means the same share was given more than once.
+	ErrDuplicateShare = core.ErrDuplicateShare
+
+	// ErrBadSignature means a bundle's signature doesn't match its files.
+	ErrBadSignature = core.ErrBadSignature
+
+	// ErrUnrepairable means MANIFEST.age is too damaged for its parity
+	// data to repair.
+	ErrUnrepairable = core.ErrUnrepairable
+
+	// ErrDecryptionFailed means the shares don't decrypt the manifest:
+	// they belong to another seal, or the manifest was altered.
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
@@ -0,0 +1,38 @@
+package rememory
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/eljojo/rememory/internal/core"
+	"github.com/eljojo/rememory/internal/manifest"
+)
+
+// ExtractResult describes what Extract wrote.
+type ExtractResult = manifest.ExtractResult
+
+// Decrypt decrypts MANIFEST.age read from src with the passphrase and
+// writes the archive inside it to dst.
+func Decrypt(dst io.Writer, src io.Reader, passphrase *Secret) error {
+	if err := core.DecryptSecret(dst, src, passphrase); err != nil {
+		return fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	return nil
+}
+
+// Recover combines shares and decrypts MANIFEST.age read from src, writing
+// the archive inside it (a tar.gz) to dst.
+func Recover(dst io.Writer, src io.Reader, shares []*Share) error {
+	passphrase, err := Combine(shares)
+	if err != nil {
+		return err
+	}
+	defer passphrase.Wipe()
+	return Decrypt(dst, src, passphrase)
+}
+
+// Extract unpacks an archive written by Recover into dir. Symlinks and
+// other special files are skipped and reported in the result's Warnings.
+func Extract(src io.Reader, dir string) (*ExtractResult, error) {
+	return manifest.Extract(src, dir)
+}
@@ -0,0 +1,138 @@
+package rememory_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eljojo/rememory/internal/html"
+	"github.com/eljojo/rememory/pkg/rememory"
+)
+
+func TestSealAndRecover(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "manifest")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	secret := "the key is under the mat"
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte(secret), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var manifestAge bytes.Buffer
+	sealed, err := rememory.SealDir(&manifestAge, dir, rememory.SealOptions{
+		Holders:   []string{"Alice", "Bob", "Carol"},
+		Threshold: 2,
+	})
+	if err != nil {
+		t.Fatalf("SealDir: %v", err)
+	}
+	if len(sealed.Shares) != 3 || sealed.Shares[1].Holder != "Bob" {
+		t.Fatalf("unexpected shares: %+v", sealed.Shares)
+	}
+	if len(sealed.Files) != 1 || sealed.Files[0].Path != "secret.txt" {
+		t.Errorf("Files = %+v", sealed.Files)
+	}
+
+	// Shares survive a round trip through their text form
+	parsed, err := rememory.ParseShare([]byte(sealed.Shares[2].Encode()))
+	if err != nil {
+		t.Fatalf("ParseShare: %v", err)
+	}
+
+	var archive bytes.Buffer
+	if err := rememory.Recover(&archive, bytes.NewReader(manifestAge.Bytes()), []*rememory.Share{sealed.Shares[0], parsed}); err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	out := t.TempDir()
+	result, err := rememory.Extract(&archive, out)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(result.Path, "secret.txt"))
+	if err != nil || string(got) != secret {
+		t.Errorf("recovered %q, %v", got, err)
+	}
+
+	if len(html.GetRecoverWASMBytes()) == 0 {
+		t.Skip("recover.wasm not built")
+	}
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zip bytes.Buffer
+	err = rememory.WriteBundle(&zip, rememory.BundleOptions{
+		Project:  "test",
+		Friends:  []rememory.Friend{{Name: "Alice"}, {Name: "Bob"}, {Name: "Carol"}},
+		Share:    sealed.Shares[1],
+		Manifest: manifestAge.Bytes(),
+		Created:  time.Now(),
+		Signer:   key,
+	})
+	if err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+	if err := rememory.VerifyBundle(bytes.NewReader(zip.Bytes()), int64(zip.Len())); err != nil {
+		t.Fatalf("VerifyBundle: %v", err)
+	}
+	sig, err := rememory.VerifyBundleSignature(bytes.NewReader(zip.Bytes()), int64(zip.Len()))
+	if err != nil || sig == nil {
+		t.Fatalf("VerifyBundleSignature: %v, %v", sig, err)
+	}
+	extracted, err := rememory.ExtractManifest(zip.Bytes())
+	if err != nil || !bytes.Equal(extracted, manifestAge.Bytes()) {
+		t.Errorf("ExtractManifest from bundle: %v", err)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	var manifestAge bytes.Buffer
+	sealed, err := rememory.Seal(&manifestAge, bytes.NewReader([]byte("data")), rememory.SealOptions{
+		Holders:   []string{"Alice", "Bob", "Carol"},
+		Threshold: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rememory.Seal(&bytes.Buffer{}, bytes.NewReader([]byte("data")), rememory.SealOptions{
+		Holders:   []string{"Alice", "Bob", "Carol", "Dana"},
+		Threshold: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrupted := *sealed.Shares[1]
+	corrupted.Data = append([]byte{}, corrupted.Data...)
+	corrupted.Data[0] ^= 0xFF
+
+	tests := []struct {
+		name   string
+		shares []*rememory.Share
+		want   error
+	}{
+		{"too few", sealed.Shares[:1], rememory.ErrThresholdNotMet},
+		{"mixed seals", []*rememory.Share{sealed.Shares[0], other.Shares[1]}, rememory.ErrMixedSeals},
+		{"duplicate", []*rememory.Share{sealed.Shares[0], sealed.Shares[0]}, rememory.ErrDuplicateShare},
+		{"corrupted", []*rememory.Share{sealed.Shares[0], &corrupted}, rememory.ErrChecksumMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rememory.Combine(tt.shares)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Combine() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Enough shares, but from another seal
+	err = rememory.Recover(&bytes.Buffer{}, bytes.NewReader(manifestAge.Bytes()), other.Shares[:3])
+	if !errors.Is(err, rememory.ErrDecryptionFailed) {
+		t.Errorf("Recover with another seal's shares = %v, want ErrDecryptionFailed", err)
+	}
+}
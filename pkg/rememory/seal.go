@@ -0,0 +1,103 @@
+package rememory
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/eljojo/rememory/internal/bundle"
+	"github.com/eljojo/rememory/internal/manifest"
+	"github.com/eljojo/rememory/internal/project"
+)
+
+// SealOptions configures Seal and SealDir.
+type SealOptions struct {
+	// Holders names who gets each share, in share order. Their number is
+	// the total number of shares.
+	Holders []string
+	// Threshold is how many shares are needed to recover.
+	Threshold int
+	// Progress, if set, is called as each step starts.
+	Progress func(Event)
+}
+
+// Event reports the progress of a seal or of bundle generation.
+type Event = bundle.Event
+
+// Stage is a step of sealing or bundling.
+type Stage = bundle.Stage
+
+// The stages reported in Event.Stage.
+const (
+	StageArchive = bundle.StageArchive
+	StageEncrypt = bundle.StageEncrypt
+	StageSplit   = bundle.StageSplit
+	StageVerify  = bundle.StageVerify
+	StageBundle  = bundle.StageBundle
+)
+
+// FileHash records the path, size, modification time and checksum of one
+// sealed file.
+type FileHash = manifest.FileHash
+
+// Sealed is the result of a seal. The passphrase itself is not kept.
+type Sealed struct {
+	// Shares holds one share per holder, in the order of SealOptions.Holders.
+	Shares []*Share
+	// ManifestChecksum is the "sha256:..." checksum of MANIFEST.age.
+	ManifestChecksum string
+	// VerificationHash is the checksum of the passphrase, to recognize it
+	// later without storing it.
+	VerificationHash string
+	// Files lists what was sealed. Only set by SealDir.
+	Files []FileHash
+	// Warnings describes files SealDir skipped (symlinks, special files).
+	Warnings []string
+}
+
+// Seal encrypts the archive read from src with a new passphrase, writes
+// MANIFEST.age to dst and splits the passphrase into shares. src is usually
+// a tar.gz archive, which is what recover.html expects.
+func Seal(dst io.Writer, src io.Reader, opts SealOptions) (*Sealed, error) {
+	archive, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	sealed, err := bundle.SealArchive(opts.project(), archive, opts.Progress)
+	if err != nil {
+		return nil, err
+	}
+	return write(dst, sealed)
+}
+
+// SealDir archives dir, seals it like Seal and records the archived files.
+// The archive's root folder is named after dir.
+func SealDir(dst io.Writer, dir string, opts SealOptions) (*Sealed, error) {
+	sealed, err := bundle.SealDir(opts.project(), dir, opts.Progress)
+	if err != nil {
+		return nil, err
+	}
+	return write(dst, sealed)
+}
+
+// project describes the holders the way the seal engine expects them.
+func (opts SealOptions) project() *project.Project {
+	p := &project.Project{Threshold: opts.Threshold}
+	for _, name := range opts.Holders {
+		p.Friends = append(p.Friends, project.Friend{Name: name})
+	}
+	return p
+}
+
+// write writes MANIFEST.age to dst and returns the rest of the result.
+func write(dst io.Writer, sealed *bundle.Sealed) (*Sealed, error) {
+	if _, err := dst.Write(sealed.Manifest); err != nil {
+		return nil, fmt.Errorf("writing MANIFEST.age: %w", err)
+	}
+	return &Sealed{
+		Shares:           sealed.Shares,
+		ManifestChecksum: sealed.ManifestChecksum,
+		VerificationHash: sealed.VerificationHash,
+		Files:            sealed.Files,
+		Warnings:         sealed.Warnings,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package rememory
+
+import (
+	"fmt"
+
+	"github.com/eljojo/rememory/internal/core"
+)
+
+// Share is one friend's piece of the passphrase, with its metadata.
+type Share = core.Share
+
+// ParseShare reads a share from its text form: a SHARE-*.txt file or a
+// README.txt that contains one. The checksum is verified.
+func ParseShare(content []byte) (*Share, error) {
+	share, err := core.ParseShare(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := share.Verify(); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// ParseCompactShare reads a share from its compact form (RM2:...), as
+// found in QR codes.
+func ParseCompactShare(s string) (*Share, error) {
+	return core.ParseCompact(s)
+}
+
+// CheckShares reports whether shares can be combined: they come from the
+// same seal, are at least the threshold, and don't repeat.
+func CheckShares(shares []*Share) error {
+	return core.CheckShares(shares)
+}
+
+// Secret holds a passphrase in memory that can be wiped. Call Wipe when
+// done with it.
+type Secret = core.Secret
+
+// NewSecret moves a passphrase into a Secret, wiping b.
+func NewSecret(b []byte) *Secret {
+	return core.NewSecret(b)
+}
+
+// Combine reconstructs the passphrase from enough shares of one seal.
+func Combine(shares []*Share) (*Secret, error) {
+	if err := CheckShares(shares); err != nil {
+		return nil, err
+	}
+	data := make([][]byte, len(shares))
+	for i, share := range shares {
+		if err := share.Verify(); err != nil {
+			return nil, fmt.Errorf("share %d: %w", share.Index, err)
+		}
+		data[i] = share.Data
+	}
+	secret, err := core.Combine(data)
+	if err != nil {
+		return nil, err
+	}
+	defer core.Wipe(secret)
+	return core.RecoverSecret(secret, shares[0].Version), nil
+}